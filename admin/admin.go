@@ -8,35 +8,30 @@ SPDX-License-Identifier: Apache-2.0
 package admin
 
 import (
+	"context"
 	"did-sdk/invoke"
-	"encoding/hex"
+	"encoding/json"
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
-	bcx509 "github.com/liuxinfeng96/bc-crypto/x509"
 )
 
 // SetAdminForDidContract 为DID合约设置管理员（仅合约创建者有权限）
 // @params pubKeyPem：公钥PEM编码
 // @params client：长安链客户端
-func SetAdminForDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) error {
+func SetAdminForDidContract(pubKeyPem []byte, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return SetAdminForDidContractCtx(context.Background(), pubKeyPem, client)
+}
 
-	pubKey, err := bcx509.ParsePublicKey(pubKeyPem)
-	if err != nil {
-		return err
-	}
+// SetAdminForDidContractCtx 同SetAdminForDidContract，可以通过ctx设置超时时间或取消调用
+func SetAdminForDidContractCtx(ctx context.Context, pubKeyPem []byte, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
 
-	// 由于长安链合约中获取的CreatorPk和SenderPk是公钥的SKI
-	// 所以这里进行SKI的转换
-	ski, err := bcx509.ComputeSKI(pubKey)
+	skiStr, err := PubKeyPemToSki(pubKeyPem)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 十六进制编码
-	skiStr := hex.EncodeToString(ski)
-
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -45,34 +40,31 @@ func SetAdminForDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) error {
 	})
 
 	// 需要区块链落块持久化，采用Invoke方式发送交易
-	_, err = invoke.InvokeContract(invoke.DIDContractName, model.Method_SetAdmin, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_SetAdmin,
+		params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // DeleteAdminForDidContract 为DID合约删除管理员（仅合约创建者有权限）
 // @params pubKeyPem：公钥PEM编码
 // @params client：长安链客户端
-func DeleteAdminForDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) error {
+func DeleteAdminForDidContract(pubKeyPem []byte, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return DeleteAdminForDidContractCtx(context.Background(), pubKeyPem, client)
+}
 
-	pubKey, err := bcx509.ParsePublicKey(pubKeyPem)
-	if err != nil {
-		return err
-	}
+// DeleteAdminForDidContractCtx 同DeleteAdminForDidContract，可以通过ctx设置超时时间或取消调用
+func DeleteAdminForDidContractCtx(ctx context.Context, pubKeyPem []byte,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
 
-	// 由于长安链合约中获取的CreatorPk和SenderPk是公钥的SKI
-	// 所以这里进行SKI的转换
-	ski, err := bcx509.ComputeSKI(pubKey)
+	skiStr, err := PubKeyPemToSki(pubKeyPem)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 十六进制编码
-	skiStr := hex.EncodeToString(ski)
-
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -81,34 +73,30 @@ func DeleteAdminForDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) erro
 	})
 
 	// 需要区块链落块持久化，采用Invoke方式发送交易
-	_, err = invoke.InvokeContract(invoke.DIDContractName, model.Method_DeleteAdmin, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_DeleteAdmin,
+		params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // IsAdminOfDidContract 查询是否拥有合约管理员权限
 // @params pubKeyPem：公钥PEM编码
 // @params client：长安链客户端
-func IsAdminOfDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) (bool, error) {
+func IsAdminOfDidContract(pubKeyPem []byte, client invoke.ChainClient) (bool, error) {
+	return IsAdminOfDidContractCtx(context.Background(), pubKeyPem, client)
+}
 
-	pubKey, err := bcx509.ParsePublicKey(pubKeyPem)
-	if err != nil {
-		return false, err
-	}
+// IsAdminOfDidContractCtx 同IsAdminOfDidContract，可以通过ctx设置超时时间或取消调用
+func IsAdminOfDidContractCtx(ctx context.Context, pubKeyPem []byte, client invoke.ChainClient) (bool, error) {
 
-	// 由于长安链合约中获取的CreatorPk和SenderPk是公钥的SKI
-	// 所以这里进行SKI的转换
-	ski, err := bcx509.ComputeSKI(pubKey)
+	skiStr, err := PubKeyPemToSki(pubKeyPem)
 	if err != nil {
 		return false, err
 	}
 
-	// 十六进制编码
-	skiStr := hex.EncodeToString(ski)
-
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -117,7 +105,7 @@ func IsAdminOfDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) (bool, er
 	})
 
 	// 只是查询，采用Query方式发送交易
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_IsAdmin, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_IsAdmin, params, client)
 	if err != nil {
 		return false, err
 	}
@@ -128,3 +116,91 @@ func IsAdminOfDidContract(pubKeyPem []byte, client *cmsdk.ChainClient) (bool, er
 
 	return false, nil
 }
+
+// SetAdminByDidForDidContract 以DID为DID合约设置管理员，DID Document中所有验证方法的公钥都将成为管理员（仅合约创建者有权限）
+// @params did：管理员的DID
+// @params client：长安链客户端
+func SetAdminByDidForDidContract(did string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return SetAdminByDidForDidContractCtx(context.Background(), did, client)
+}
+
+// SetAdminByDidForDidContractCtx 同SetAdminByDidForDidContract，可以通过ctx设置超时时间或取消调用
+func SetAdminByDidForDidContractCtx(ctx context.Context, did string, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Did,
+		Value: []byte(did),
+	})
+
+	// 需要区块链落块持久化，采用Invoke方式发送交易
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_SetAdmin,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// DeleteAdminByDidForDidContract 删除DID对应的所有管理员公钥（仅合约创建者有权限）
+// @params did：管理员的DID
+// @params client：长安链客户端
+func DeleteAdminByDidForDidContract(did string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return DeleteAdminByDidForDidContractCtx(context.Background(), did, client)
+}
+
+// DeleteAdminByDidForDidContractCtx 同DeleteAdminByDidForDidContract，可以通过ctx设置超时时间或取消调用
+func DeleteAdminByDidForDidContractCtx(ctx context.Context, did string, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Did,
+		Value: []byte(did),
+	})
+
+	// 需要区块链落块持久化，采用Invoke方式发送交易
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_DeleteAdmin,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetAdminListOfDidContract 获取DID合约的管理员列表，第一个为合约创建者
+// @params client：长安链客户端
+func GetAdminListOfDidContract(client invoke.ChainClient) ([]*model.AdminRecord, error) {
+	return GetAdminListOfDidContractCtx(context.Background(), client)
+}
+
+// GetAdminListOfDidContractCtx 同GetAdminListOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetAdminListOfDidContractCtx(ctx context.Context, client invoke.ChainClient) ([]*model.AdminRecord, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	// 只是查询，采用Query方式发送交易
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetAdminList, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*model.AdminRecord, 0)
+
+	err = json.Unmarshal(resp, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// PubKeyPemToSki 将公钥PEM编码转换为合约中使用的SKI（十六进制编码）
+// @params pubKeyPem：公钥PEM编码
+func PubKeyPemToSki(pubKeyPem []byte) (string, error) {
+	// 由于长安链合约中获取的CreatorPk和SenderPk是公钥的SKI
+	// 所以这里进行SKI的转换
+	return model.PubKeyPemToSki(string(pubKeyPem))
+}
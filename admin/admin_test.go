@@ -10,8 +10,10 @@ package admin
 import (
 	"did-sdk/did"
 	"did-sdk/testdata"
+	"encoding/json"
 	"testing"
 
+	"chainmaker.org/chainmaker/did-contract/model"
 	"github.com/test-go/testify/require"
 )
 
@@ -25,21 +27,21 @@ func TestDidContractAdmin(t *testing.T) {
 	cpk, err := c.GetPublicKey().String()
 	require.Nil(t, err)
 
-	err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	_, err = DeleteAdminForDidContract([]byte(cpk), creatorC)
 	require.Nil(t, err)
 
 	ok, err := IsAdminOfDidContract([]byte(cpk), c)
 	require.Nil(t, err)
 	require.Equal(t, false, ok)
 
-	err = SetAdminForDidContract([]byte(cpk), creatorC)
+	_, err = SetAdminForDidContract([]byte(cpk), creatorC)
 	require.Nil(t, err)
 
 	ok2, err := IsAdminOfDidContract([]byte(cpk), c)
 	require.Nil(t, err)
 	require.Equal(t, true, ok2)
 
-	err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	_, err = DeleteAdminForDidContract([]byte(cpk), creatorC)
 	require.Nil(t, err)
 
 	ok3, err := IsAdminOfDidContract([]byte(cpk), c)
@@ -47,13 +49,46 @@ func TestDidContractAdmin(t *testing.T) {
 	require.Equal(t, false, ok3)
 }
 
+func TestGetAdminListOfDidContract(t *testing.T) {
+	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
+	require.Nil(t, err)
+
+	cpk, err := c.GetPublicKey().String()
+	require.Nil(t, err)
+
+	ski, err := PubKeyPemToSki([]byte(cpk))
+	require.Nil(t, err)
+
+	_, err = SetAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+
+	list, err := GetAdminListOfDidContract(c)
+	require.Nil(t, err)
+	require.NotEmpty(t, list)
+	require.Equal(t, true, list[0].IsCreator)
+
+	var found bool
+	for _, admin := range list {
+		if admin.Ski == ski {
+			found = true
+		}
+	}
+	require.Equal(t, true, found)
+
+	_, err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+}
+
 func TestPermissionDidContractAdmin(t *testing.T) {
 	c, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
 	require.Nil(t, err)
 
 	// 添加黑名单测试
 	var blackList = []string{"did:cm:test1"}
-	err = did.AddDidBlackListToChain(blackList, c)
+	_, err = did.AddDidBlackListToChain(blackList, c)
 	require.NotNil(t, err)
 
 	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
@@ -63,7 +98,7 @@ func TestPermissionDidContractAdmin(t *testing.T) {
 	require.Nil(t, err)
 
 	// 添加管理员
-	err = SetAdminForDidContract([]byte(cpk), creatorC)
+	_, err = SetAdminForDidContract([]byte(cpk), creatorC)
 	require.Nil(t, err)
 
 	ok, err := IsAdminOfDidContract([]byte(cpk), c)
@@ -71,9 +106,193 @@ func TestPermissionDidContractAdmin(t *testing.T) {
 	require.Equal(t, true, ok)
 
 	// 重新添加黑名单
-	err = did.AddDidBlackListToChain(blackList, c)
+	_, err = did.AddDidBlackListToChain(blackList, c)
+	require.Nil(t, err)
+
+	_, err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+}
+
+func TestRoleDidContract(t *testing.T) {
+	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
+	require.Nil(t, err)
+
+	cpk, err := c.GetPublicKey().String()
+	require.Nil(t, err)
+
+	ski, err := PubKeyPemToSki([]byte(cpk))
+	require.Nil(t, err)
+
+	var blackList = []string{"did:cm:test1"}
+
+	// 授予黑名单管理员角色
+	_, err = GrantRoleForDidContract(model.Role_BlackListManager, ski, creatorC)
+	require.Nil(t, err)
+
+	list, err := GetRoleListOfDidContract(model.Role_BlackListManager, 0, 0, c)
+	require.Nil(t, err)
+
+	var hasRole bool
+	for _, v := range list {
+		if v.Member == ski {
+			hasRole = true
+		}
+	}
+	require.Equal(t, true, hasRole)
+
+	_, err = did.AddDidBlackListToChain(blackList, c)
+	require.Nil(t, err)
+
+	// 没有签发者管理员角色
+	_, err = did.AddTrustIssuerListToChain([]string{"did:cm:test1"}, c)
+	require.NotNil(t, err)
+
+	// 撤销角色后不能再操作黑名单
+	_, err = RevokeRoleForDidContract(model.Role_BlackListManager, ski, creatorC)
+	require.Nil(t, err)
+
+	_, err = did.DeleteDidBlackListFromChain(blackList, c)
+	require.NotNil(t, err)
+}
+
+func TestProposalDidContract(t *testing.T) {
+	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
+	require.Nil(t, err)
+
+	cpk, err := c.GetPublicKey().String()
+	require.Nil(t, err)
+
+	_, err = SetAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+
+	// 启用提案治理，需要两位管理员同意
+	_, err = SetProposalQuorumForDidContract(2, creatorC)
+	require.Nil(t, err)
+
+	var blackList = []string{"did:cm:test2"}
+
+	// 启用提案治理后管理员不能直接操作
+	_, err = did.AddDidBlackListToChain(blackList, creatorC)
+	require.NotNil(t, err)
+
+	blackListBytes, err := json.Marshal(blackList)
+	require.Nil(t, err)
+
+	id, _, err := CreateProposalForDidContract(model.Method_AddBlackList, map[string]string{
+		model.Params_DidList: string(blackListBytes),
+		model.Params_Reason:  "test",
+	}, 0, creatorC)
+	require.Nil(t, err)
+
+	proposal, err := GetProposalOfDidContract(id, c)
+	require.Nil(t, err)
+	require.Equal(t, model.ProposalStatus_Pending, proposal.Status)
+
+	_, err = ApproveProposalForDidContract(id, c)
+	require.Nil(t, err)
+
+	proposal, err = GetProposalOfDidContract(id, c)
+	require.Nil(t, err)
+	require.Equal(t, model.ProposalStatus_Executed, proposal.Status)
+
+	// 通过提案关闭提案治理
+	id, _, err = CreateProposalForDidContract(model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "1",
+	}, 0, creatorC)
+	require.Nil(t, err)
+
+	_, err = ApproveProposalForDidContract(id, c)
+	require.Nil(t, err)
+
+	quorum, err := GetProposalQuorumOfDidContract(c)
+	require.Nil(t, err)
+	require.Equal(t, 1, quorum)
+
+	_, err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+}
+
+func TestGetAuditLogOfDidContract(t *testing.T) {
+	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
+	require.Nil(t, err)
+
+	cpk, err := c.GetPublicKey().String()
+	require.Nil(t, err)
+
+	creatorPk, err := creatorC.GetPublicKey().String()
+	require.Nil(t, err)
+
+	creatorSki, err := PubKeyPemToSki([]byte(creatorPk))
+	require.Nil(t, err)
+
+	_, err = SetAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+
+	list, err := GetAuditLogOfDidContract(model.Method_SetAdmin, creatorSki, 0, 0, 0, 0, c)
+	require.Nil(t, err)
+	require.NotEmpty(t, list)
+
+	for _, record := range list {
+		require.Equal(t, model.Method_SetAdmin, record.Method)
+		require.Equal(t, creatorSki, record.Operator)
+		require.NotEmpty(t, record.ParamsDigest)
+	}
+
+	_, err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	require.Nil(t, err)
+}
+
+func TestContractConfigOfDidContract(t *testing.T) {
+	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
+	require.Nil(t, err)
+
+	config, err := GetContractConfigOfDidContract(creatorC)
+	require.Nil(t, err)
+	require.NotEmpty(t, config.DidMethod)
+
+	// 非管理员不能修改配置
+	_, err = SetContractConfigForDidContract(config, c)
+	require.NotNil(t, err)
+
+	oldPageSize := config.DefaultPageSize
+
+	config.DefaultPageSize = 50
+	_, err = SetContractConfigForDidContract(config, creatorC)
+	require.Nil(t, err)
+
+	newConfig, err := GetContractConfigOfDidContract(creatorC)
 	require.Nil(t, err)
+	require.Equal(t, 50, newConfig.DefaultPageSize)
+	require.Equal(t, config.EnableTrustIssuer, newConfig.EnableTrustIssuer)
 
-	err = DeleteAdminForDidContract([]byte(cpk), creatorC)
+	config.DefaultPageSize = oldPageSize
+	_, err = SetContractConfigForDidContract(config, creatorC)
 	require.Nil(t, err)
 }
+
+func TestMigrateDidContract(t *testing.T) {
+	creatorC, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	status, err := GetMigrationStatusOfDidContract(creatorC)
+	require.Nil(t, err)
+
+	for !status.IsDone() {
+		status, _, err = MigrateDidContract(100, creatorC)
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, status.LatestVersion, status.SchemaVersion)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"did-sdk/invoke"
+	"encoding/json"
+	"strconv"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// GetAuditLogOfDidContract 获取DID合约的审计日志，按交易顺序排列
+// @params method：合约方法（空字符串表示不限制）
+// @params operator：操作者公钥的SKI或DID（空字符串表示不限制）
+// @params startTime：开始时间，Unix秒（0表示不限制）
+// @params endTime：结束时间，Unix秒（0表示不限制）
+// @params start：开始的索引，0表示从第一个开始
+// @params count：要获取的数量，0表示获取所有
+// @params client：长安链客户端
+func GetAuditLogOfDidContract(method, operator string, startTime, endTime int64, start int, count int,
+	client invoke.ChainClient) ([]*model.AuditRecord, error) {
+	return GetAuditLogOfDidContractCtx(context.Background(), method, operator, startTime, endTime, start, count, client)
+}
+
+// GetAuditLogOfDidContractCtx 同GetAuditLogOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetAuditLogOfDidContractCtx(ctx context.Context, method, operator string, startTime, endTime int64, start int,
+	count int, client invoke.ChainClient) ([]*model.AuditRecord, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_AuditMethod,
+		Value: []byte(method),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Operator,
+		Value: []byte(operator),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_StartTime,
+		Value: []byte(strconv.FormatInt(startTime, 10)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_EndTime,
+		Value: []byte(strconv.FormatInt(endTime, 10)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_SearchStart,
+		Value: []byte(strconv.Itoa(start)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_SearchCount,
+		Value: []byte(strconv.Itoa(count)),
+	})
+
+	// 只是查询，采用Query方式发送交易
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetAuditLog, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*model.AuditRecord, 0)
+
+	err = json.Unmarshal(resp, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"did-sdk/invoke"
+	"encoding/json"
+	"strconv"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// GetContractConfigOfDidContract 获取DID合约的运行时配置
+// @params client：长安链客户端
+func GetContractConfigOfDidContract(client invoke.ChainClient) (*model.ContractConfig, error) {
+	return GetContractConfigOfDidContractCtx(context.Background(), client)
+}
+
+// GetContractConfigOfDidContractCtx 同GetContractConfigOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetContractConfigOfDidContractCtx(ctx context.Context, client invoke.ChainClient) (*model.ContractConfig, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	// 只是查询，采用Query方式发送交易
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetContractConfig,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var config model.ContractConfig
+
+	err = json.Unmarshal(resp, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// SetContractConfigForDidContract 修改DID合约的运行时配置（仅管理员有权限）
+// 会修改是否启用信任签发者、DID Document的最大字节数和列表查询的默认条数，
+// 可以先通过GetContractConfigOfDidContract获取当前配置再修改；DidMethod和ProposalQuorum不会被修改
+// @params config：合约配置
+// @params client：长安链客户端
+func SetContractConfigForDidContract(config *model.ContractConfig, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
+	return SetContractConfigForDidContractCtx(context.Background(), config, client)
+}
+
+// SetContractConfigForDidContractCtx 同SetContractConfigForDidContract，可以通过ctx设置超时时间或取消调用
+func SetContractConfigForDidContractCtx(ctx context.Context, config *model.ContractConfig,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_EnableTrustIssuer,
+		Value: []byte(strconv.FormatBool(config.EnableTrustIssuer)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_MaxDocumentSize,
+		Value: []byte(strconv.Itoa(config.MaxDocumentSize)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_DefaultPageSize,
+		Value: []byte(strconv.Itoa(config.DefaultPageSize)),
+	})
+
+	// 需要区块链落块持久化，采用Invoke方式发送交易
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_SetContractConfig, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"did-sdk/invoke"
+	"encoding/json"
+	"strconv"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// MigrateDidContract 继续执行DID合约升级后未完成的数据迁移（仅管理员有权限）
+// 数据量较大时一笔交易无法完成迁移，需要多次调用直到返回的状态IsDone()为true
+// @params batch：每个迁移步骤本次最多处理的数据条数，0表示使用合约的默认值
+// @params client：长安链客户端
+// @return 迁移状态和交易回执
+func MigrateDidContract(batch int, client invoke.ChainClient) (*model.MigrationStatus, *invoke.TxReceipt, error) {
+	return MigrateDidContractCtx(context.Background(), batch, client)
+}
+
+// MigrateDidContractCtx 同MigrateDidContract，可以通过ctx设置超时时间或取消调用
+func MigrateDidContractCtx(ctx context.Context, batch int,
+	client invoke.ChainClient) (*model.MigrationStatus, *invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	if batch > 0 {
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_MigrationBatch,
+			Value: []byte(strconv.Itoa(batch)),
+		})
+	}
+
+	// 需要区块链落块持久化，采用Invoke方式发送交易
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_Migrate,
+		params, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var status model.MigrationStatus
+
+	err = json.Unmarshal([]byte(receipt.Result), &status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &status, receipt, nil
+}
+
+// GetMigrationStatusOfDidContract 获取DID合约的数据迁移状态
+// @params client：长安链客户端
+func GetMigrationStatusOfDidContract(client invoke.ChainClient) (*model.MigrationStatus, error) {
+	return GetMigrationStatusOfDidContractCtx(context.Background(), client)
+}
+
+// GetMigrationStatusOfDidContractCtx 同GetMigrationStatusOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetMigrationStatusOfDidContractCtx(ctx context.Context,
+	client invoke.ChainClient) (*model.MigrationStatus, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	// 只是查询，采用Query方式发送交易
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetMigrationStatus,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var status model.MigrationStatus
+
+	err = json.Unmarshal(resp, &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"did-sdk/invoke"
+	"encoding/json"
+	"strconv"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// CreateProposalForDidContract 管理员创建治理提案，提案者默认同意，同意人数达到法定人数时立即执行
+// @params action：提案要执行的合约方法，如model.Method_AddBlackList
+// @params actionParams：合约方法的参数，key与合约方法的参数名保持一致，如model.Params_DidList
+// @params expireTime：过期时间（Unix秒），0表示默认7天后过期
+// @params client：长安链客户端
+// @return 提案ID和交易回执
+func CreateProposalForDidContract(action string, actionParams map[string]string, expireTime int64,
+	client invoke.ChainClient) (string, *invoke.TxReceipt, error) {
+	return CreateProposalForDidContractCtx(context.Background(), action, actionParams, expireTime, client)
+}
+
+// CreateProposalForDidContractCtx 同CreateProposalForDidContract，可以通过ctx设置超时时间或取消调用
+func CreateProposalForDidContractCtx(ctx context.Context, action string, actionParams map[string]string,
+	expireTime int64, client invoke.ChainClient) (string, *invoke.TxReceipt, error) {
+
+	actionParamsBytes, err := json.Marshal(actionParams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ProposalAction,
+		Value: []byte(action),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ProposalParams,
+		Value: actionParamsBytes,
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ExpireTime,
+		Value: []byte(strconv.FormatInt(expireTime, 10)),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_CreateProposal,
+		params, client)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return receipt.Result, receipt, nil
+}
+
+// ApproveProposalForDidContract 管理员同意治理提案，同意人数达到法定人数时执行提案
+// @params id：提案ID
+// @params client：长安链客户端
+func ApproveProposalForDidContract(id string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return ApproveProposalForDidContractCtx(context.Background(), id, client)
+}
+
+// ApproveProposalForDidContractCtx 同ApproveProposalForDidContract，可以通过ctx设置超时时间或取消调用
+func ApproveProposalForDidContractCtx(ctx context.Context, id string, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ProposalId,
+		Value: []byte(id),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_ApproveProposal, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// CancelProposalForDidContract 提案者取消未执行的治理提案
+// @params id：提案ID
+// @params client：长安链客户端
+func CancelProposalForDidContract(id string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return CancelProposalForDidContractCtx(context.Background(), id, client)
+}
+
+// CancelProposalForDidContractCtx 同CancelProposalForDidContract，可以通过ctx设置超时时间或取消调用
+func CancelProposalForDidContractCtx(ctx context.Context, id string, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ProposalId,
+		Value: []byte(id),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_CancelProposal,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetProposalOfDidContract 获取治理提案
+// @params id：提案ID
+// @params client：长安链客户端
+func GetProposalOfDidContract(id string, client invoke.ChainClient) (*model.Proposal, error) {
+	return GetProposalOfDidContractCtx(context.Background(), id, client)
+}
+
+// GetProposalOfDidContractCtx 同GetProposalOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetProposalOfDidContractCtx(ctx context.Context, id string, client invoke.ChainClient) (*model.Proposal, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ProposalId,
+		Value: []byte(id),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetProposal, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var proposal model.Proposal
+
+	err = json.Unmarshal(resp, &proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proposal, nil
+}
+
+// GetProposalListOfDidContract 获取治理提案列表
+// @params status：提案状态，如pending、executed、canceled、expired（空字符串可以查找全部列表）
+// @params start：开始的索引，0表示从第一个开始
+// @params count：要获取的数量，0表示获取所有
+// @params client：长安链客户端
+func GetProposalListOfDidContract(status string, start int, count int,
+	client invoke.ChainClient) ([]*model.Proposal, error) {
+	return GetProposalListOfDidContractCtx(context.Background(), status, start, count, client)
+}
+
+// GetProposalListOfDidContractCtx 同GetProposalListOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetProposalListOfDidContractCtx(ctx context.Context, status string, start int, count int,
+	client invoke.ChainClient) ([]*model.Proposal, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ProposalStatus,
+		Value: []byte(status),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_SearchStart,
+		Value: []byte(strconv.Itoa(start)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_SearchCount,
+		Value: []byte(strconv.Itoa(count)),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetProposalList,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*model.Proposal, 0)
+
+	err = json.Unmarshal(resp, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// SetProposalQuorumForDidContract 设置治理提案的法定人数（仅合约创建者有权限）
+// 法定人数大于1时启用提案治理，管理员不能再直接执行治理操作，法定人数也只能通过提案修改
+// @params quorum：法定人数
+// @params client：长安链客户端
+func SetProposalQuorumForDidContract(quorum int, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return SetProposalQuorumForDidContractCtx(context.Background(), quorum, client)
+}
+
+// SetProposalQuorumForDidContractCtx 同SetProposalQuorumForDidContract，可以通过ctx设置超时时间或取消调用
+func SetProposalQuorumForDidContractCtx(ctx context.Context, quorum int,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Quorum,
+		Value: []byte(strconv.Itoa(quorum)),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_SetProposalQuorum, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetProposalQuorumOfDidContract 获取治理提案的法定人数
+// @params client：长安链客户端
+func GetProposalQuorumOfDidContract(client invoke.ChainClient) (int, error) {
+	return GetProposalQuorumOfDidContractCtx(context.Background(), client)
+}
+
+// GetProposalQuorumOfDidContractCtx 同GetProposalQuorumOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetProposalQuorumOfDidContractCtx(ctx context.Context, client invoke.ChainClient) (int, error) {
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetProposalQuorum,
+		nil, client)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(string(resp))
+}
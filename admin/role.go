@@ -0,0 +1,127 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"did-sdk/invoke"
+	"encoding/json"
+	"strconv"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// GrantRoleForDidContract 为DID合约授予角色（仅管理员有权限）
+// @params role：角色名称，如template-manager、blacklist-manager、issuer-manager、did-operator、vc-manager
+// @params member：成员的DID或公钥SKI（可通过PubKeyPemToSki获取）
+// @params client：长安链客户端
+func GrantRoleForDidContract(role, member string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return GrantRoleForDidContractCtx(context.Background(), role, member, client)
+}
+
+// GrantRoleForDidContractCtx 同GrantRoleForDidContract，可以通过ctx设置超时时间或取消调用
+func GrantRoleForDidContractCtx(ctx context.Context, role, member string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Role,
+		Value: []byte(role),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Member,
+		Value: []byte(member),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_GrantRole,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// RevokeRoleForDidContract 为DID合约撤销角色（仅管理员有权限）
+// @params role：角色名称
+// @params member：成员的DID或公钥SKI
+// @params client：长安链客户端
+func RevokeRoleForDidContract(role, member string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return RevokeRoleForDidContractCtx(context.Background(), role, member, client)
+}
+
+// RevokeRoleForDidContractCtx 同RevokeRoleForDidContract，可以通过ctx设置超时时间或取消调用
+func RevokeRoleForDidContractCtx(ctx context.Context, role, member string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Role,
+		Value: []byte(role),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Member,
+		Value: []byte(member),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_RevokeRole,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetRoleListOfDidContract 获取DID合约的角色成员列表
+// @params role：角色名称（空字符串可以查找所有角色）
+// @params start：开始的索引，0表示从第一个开始
+// @params count：要获取的数量，0表示获取所有
+// @params client：长安链客户端
+func GetRoleListOfDidContract(role string, start int, count int,
+	client invoke.ChainClient) ([]*model.RoleMember, error) {
+	return GetRoleListOfDidContractCtx(context.Background(), role, start, count, client)
+}
+
+// GetRoleListOfDidContractCtx 同GetRoleListOfDidContract，可以通过ctx设置超时时间或取消调用
+func GetRoleListOfDidContractCtx(ctx context.Context, role string, start int, count int,
+	client invoke.ChainClient) ([]*model.RoleMember, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Role,
+		Value: []byte(role),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_SearchStart,
+		Value: []byte(strconv.Itoa(start)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_SearchCount,
+		Value: []byte(strconv.Itoa(count)),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetRoleList, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*model.RoleMember, 0)
+
+	err = json.Unmarshal(resp, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,378 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"context"
+	"did-sdk/admin"
+	"did-sdk/did"
+	"did-sdk/events"
+	"did-sdk/invoke"
+	"did-sdk/vc"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// Options 缓存选项
+type Options struct {
+	// TTL 缓存的有效期，没有收到失效事件的缓存在有效期后重新从链上获取，0表示使用DefaultTTL
+	TTL time.Duration
+	// ReconnectInterval 事件订阅断开后重新订阅前的等待时间，0表示使用events.DefaultReconnectInterval
+	ReconnectInterval time.Duration
+	// OnError 订阅事件过程中的错误，如订阅断开，为nil时忽略
+	OnError func(err error)
+}
+
+// DefaultTTL 缓存的默认有效期
+var DefaultTTL = 5 * time.Minute
+
+// ErrCacheMiss 离线模式下缓存中没有要获取的数据
+var ErrCacheMiss = errors.New("cache miss")
+
+// 缓存key的前缀
+const (
+	prefixDidDocument = "didDocument:"
+	prefixVcTemplate  = "vcTemplate:"
+	prefixRevokedVc   = "revokedVc:"
+	prefixSuspendedVc = "suspendedVc:"
+	prefixBlackList   = "blackList:"
+	prefixStatusList  = "statusList:"
+	prefixTrustChain  = "trustChain:"
+
+	keyEnableTrustIssuer = "enableTrustIssuer"
+)
+
+// entry 缓存项
+type entry struct {
+	value interface{}
+	// expire 过期时间，零值表示永不过期
+	expire time.Time
+}
+
+// Cache 链上DID文档、VC模板、VC吊销和暂停状态、DID黑名单、可信签发者和状态列表凭证的本地缓存
+// 调用Watch后收到对应的合约事件时缓存立即失效，没有收到事件时缓存在有效期后失效
+// 离线模式下只从缓存获取数据，可以在无法连接链时使用预热的缓存验证VC
+type Cache struct {
+	client invoke.ChainClient
+	opts   Options
+	now    func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+	offline bool
+	// version 每次失效时递增，从链上获取期间缓存失效时不写入获取到的旧数据
+	version uint64
+}
+
+// New 新建缓存
+// @params client: 链客户端连接，可以是invoke.NewClient绑定了合约名称的客户端
+// @params opts: 缓存选项
+func New(client invoke.ChainClient, opts Options) *Cache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+
+	return &Cache{
+		client:  client,
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Watch 订阅DID合约事件，收到事件时使对应的缓存失效，ctx结束时停止订阅
+// 返回时已经完成订阅，之后上链的事件都会使缓存失效
+// @params ctx: 订阅的上下文
+func (c *Cache) Watch(ctx context.Context) error {
+	sub, err := events.Subscribe(ctx, c.client, events.Options{
+		StartBlock: -1,
+		Topics: []string{model.Topic_SetDidDocument, model.Topic_SetVcTemplate, model.Topic_RevokeVc,
+			model.Topic_SuspendVc, model.Topic_UnsuspendVc, model.Topic_AddBlackList, model.Topic_DeleteBlackList,
+			model.Topic_SetStatusList, model.Topic_AddTrustIssuer, model.Topic_DeleteTrustIssuer,
+			model.Topic_AccreditIssuer, model.Topic_RevokeAccreditation, model.Topic_SetContractConfig},
+		ReconnectInterval: c.opts.ReconnectInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		errs := sub.Errors()
+		for {
+			select {
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				c.opts.OnError(err)
+			case e, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				c.invalidate(e)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// SetOffline 设置离线模式，离线模式下不访问链，过期的缓存仍然可以获取，缓存中没有时返回ErrCacheMiss
+// @params offline: 是否离线
+func (c *Cache) SetOffline(offline bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offline = offline
+}
+
+// Purge 清空缓存
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*entry)
+	c.version++
+}
+
+// GetDidDocument 获取DID文档，缓存中没有时从链上获取
+// @params ctx: 调用上下文
+// @params didStr: DID
+func (c *Cache) GetDidDocument(ctx context.Context, didStr string) ([]byte, error) {
+	v, err := c.get(prefixDidDocument+didStr, func() (interface{}, time.Time, error) {
+		doc, err := did.GetDidDocFromChainCtx(ctx, didStr, c.client)
+		return doc, c.now().Add(c.opts.TTL), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// GetVcTemplate 获取VC模板，缓存中没有时从链上获取
+// @params ctx: 调用上下文
+// @params id: 模板ID
+func (c *Cache) GetVcTemplate(ctx context.Context, id string) ([]byte, error) {
+	v, err := c.get(prefixVcTemplate+id, func() (interface{}, time.Time, error) {
+		template, err := vc.GetVcTemplateFromChainCtx(ctx, id, c.client)
+		return template, c.now().Add(c.opts.TTL), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// GetVcRevocation 获取签发者的VC的吊销记录，VC未被吊销时返回nil，缓存中没有时从链上获取，吊销记录不会过期
+// @params ctx: 调用上下文
+// @params issuer: VC的签发者DID
+// @params vcId: VC编号
+func (c *Cache) GetVcRevocation(ctx context.Context, issuer, vcId string) (*model.VcRevocation, error) {
+	v, err := c.get(vcRecordKey(prefixRevokedVc, issuer, vcId), func() (interface{}, time.Time, error) {
+		record, err := vc.GetVcRevocationFromChainCtx(ctx, issuer, vcId, c.client)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		// 吊销是永久的
+		if record != nil {
+			return record, time.Time{}, nil
+		}
+		return record, c.now().Add(c.opts.TTL), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*model.VcRevocation), nil
+}
+
+// GetVcSuspension 获取签发者的VC的暂停记录，VC未被暂停时返回nil，缓存中没有时从链上获取
+// @params ctx: 调用上下文
+// @params issuer: VC的签发者DID
+// @params vcId: VC编号
+func (c *Cache) GetVcSuspension(ctx context.Context, issuer, vcId string) (*model.VcSuspension, error) {
+	v, err := c.get(vcRecordKey(prefixSuspendedVc, issuer, vcId), func() (interface{}, time.Time, error) {
+		record, err := vc.GetVcSuspensionFromChainCtx(ctx, issuer, vcId, c.client)
+		return record, c.now().Add(c.opts.TTL), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*model.VcSuspension), nil
+}
+
+// vcRecordKey 按签发者区分的VC记录的缓存key，DID中不包含"/"，不同的签发者和VC编号不会得到相同的key
+func vcRecordKey(prefix, issuer, vcId string) string {
+	return prefix + issuer + "/" + vcId
+}
+
+// IsInBlackList 获取DID在now时是否在黑名单中，缓存中没有时从链上获取
+// 缓存的是黑名单记录，记录是否过期按now判断，可以使用固定的验证时间
+// @params ctx: 调用上下文
+// @params didStr: DID
+// @params now: 验证使用的当前时间
+func (c *Cache) IsInBlackList(ctx context.Context, didStr string, now time.Time) (bool, error) {
+	v, err := c.get(prefixBlackList+didStr, func() (interface{}, time.Time, error) {
+		list, err := did.GetDidBlackListFromChainCtx(ctx, didStr, 0, 1, c.client)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		if len(list) == 0 || list[0].Did != didStr {
+			return (*model.BlackListRecord)(nil), c.now().Add(c.opts.TTL), nil
+		}
+		return list[0], c.now().Add(c.opts.TTL), nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	record := v.(*model.BlackListRecord)
+	return record != nil && !record.IsExpired(now.Unix()), nil
+}
+
+// GetStatusListCredential 获取状态列表凭证，缓存中没有时从链上获取
+// @params ctx: 调用上下文
+// @params id: 状态列表凭证的ID
+func (c *Cache) GetStatusListCredential(ctx context.Context, id string) ([]byte, error) {
+	v, err := c.get(prefixStatusList+id, func() (interface{}, time.Time, error) {
+		list, err := vc.GetStatusListFromChainCtx(ctx, id, c.client)
+		return list, c.now().Add(c.opts.TTL), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// IsTrustIssuerEnabled 获取DID合约是否开启了可信签发者检查，缓存中没有时从链上获取
+// @params ctx: 调用上下文
+func (c *Cache) IsTrustIssuerEnabled(ctx context.Context) (bool, error) {
+	v, err := c.get(keyEnableTrustIssuer, func() (interface{}, time.Time, error) {
+		config, err := admin.GetContractConfigOfDidContractCtx(ctx, c.client)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return config.EnableTrustIssuer, c.now().Add(c.opts.TTL), nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// GetAccreditationChain 获取签发者的认证链，缓存中没有时从链上获取
+// 签发者不是可信签发者时不缓存，每次都从链上获取
+// @params ctx: 调用上下文
+// @params didStr: 签发者DID
+func (c *Cache) GetAccreditationChain(ctx context.Context, didStr string) ([]*model.TrustIssuer, error) {
+	v, err := c.get(prefixTrustChain+didStr, func() (interface{}, time.Time, error) {
+		chain, err := did.GetIssuerAccreditationChainFromChainCtx(ctx, didStr, c.client)
+		return chain, c.now().Add(c.opts.TTL), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*model.TrustIssuer), nil
+}
+
+// Options 返回使用该缓存的本地验证选项
+func (c *Cache) Options() vc.LocalVerifyOptions {
+	return vc.LocalVerifyOptions{Resolver: c, Templates: c, Status: c, Trust: c, StatusLists: c}
+}
+
+// get 获取缓存，缓存中没有或已过期时调用load从链上获取并写入缓存
+// @params load: 从链上获取数据，返回数据和缓存的过期时间
+func (c *Cache) get(key string, load func() (interface{}, time.Time, error)) (interface{}, error) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	offline := c.offline
+	version := c.version
+	c.mu.RUnlock()
+
+	if ok && (offline || e.expire.IsZero() || c.now().Before(e.expire)) {
+		return e.value, nil
+	}
+
+	if offline {
+		return nil, fmt.Errorf("%w, key: [%s]", ErrCacheMiss, key)
+	}
+
+	value, expire, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.version == version {
+		c.entries[key] = &entry{value: value, expire: expire}
+	}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// invalidate 根据合约事件使缓存失效
+func (c *Cache) invalidate(e *events.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch d := e.Data.(type) {
+	case *events.DidDocumentSet:
+		delete(c.entries, prefixDidDocument+d.Did)
+	case *events.VcTemplateSet:
+		delete(c.entries, prefixVcTemplate+d.Id)
+	case *events.VcRevoked:
+		if d.Record != nil && len(d.Record.Issuer) != 0 {
+			// 吊销是永久的，直接写入吊销记录
+			c.entries[vcRecordKey(prefixRevokedVc, d.Record.Issuer, d.VcId)] = &entry{value: d.Record}
+			break
+		}
+		// 不知道签发者时使所有签发者的同名VC的缓存失效
+		for k := range c.entries {
+			if strings.HasPrefix(k, prefixRevokedVc) && strings.HasSuffix(k, "/"+d.VcId) {
+				delete(c.entries, k)
+			}
+		}
+	case *events.VcSuspended:
+		c.entries[vcRecordKey(prefixSuspendedVc, d.Record.Issuer, d.VcId)] = &entry{value: d.Record,
+			expire: c.now().Add(c.opts.TTL)}
+	case *events.VcUnsuspended:
+		delete(c.entries, vcRecordKey(prefixSuspendedVc, d.Record.Issuer, d.VcId))
+	case *events.BlackListAdded:
+		for _, didStr := range d.Dids {
+			delete(c.entries, prefixBlackList+didStr)
+		}
+	case *events.BlackListDeleted:
+		for _, didStr := range d.Dids {
+			delete(c.entries, prefixBlackList+didStr)
+		}
+	case *events.StatusListSet:
+		delete(c.entries, prefixStatusList+d.Id)
+	case *events.TrustIssuerAdded, *events.TrustIssuerDeleted, *events.IssuerAccredited,
+		*events.AccreditationRevoked:
+		// 认证链包含所有上级签发者，任一签发者变化都可能影响其他签发者的认证链
+		for k := range c.entries {
+			if strings.HasPrefix(k, prefixTrustChain) {
+				delete(c.entries, k)
+			}
+		}
+	case *events.ContractConfigSet:
+		c.entries[keyEnableTrustIssuer] = &entry{value: d.Config.EnableTrustIssuer,
+			expire: c.now().Add(c.opts.TTL)}
+	}
+
+	c.version++
+}
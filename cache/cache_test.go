@@ -0,0 +1,231 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package cache
+
+import (
+	"context"
+	"did-sdk/admin"
+	"did-sdk/did"
+	"did-sdk/invoke"
+	"did-sdk/key"
+	"did-sdk/simulator"
+	"did-sdk/vc"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/test-go/testify/require"
+)
+
+// countingClient 记录查询次数的客户端，离线时查询失败
+type countingClient struct {
+	*simulator.Client
+	queries int32
+	offline int32
+}
+
+func (c *countingClient) QueryContract(contractName, method string, kvs []*common.KeyValuePair,
+	timeout int64) (*common.TxResponse, error) {
+	if atomic.LoadInt32(&c.offline) == 1 {
+		return nil, errors.New("connection refused")
+	}
+	atomic.AddInt32(&c.queries, 1)
+	return c.Client.QueryContract(contractName, method, kvs, timeout)
+}
+
+func (c *countingClient) count() int32 {
+	return atomic.LoadInt32(&c.queries)
+}
+
+// waitUntil 等待cond成立
+func waitUntil(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		require.True(t, time.Now().Before(deadline), "wait timeout")
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCache(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+
+	keyInfo, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	issuerClient, err := sim.NewClient(keyInfo.PkPEM)
+	require.Nil(t, err)
+
+	doc, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo}, issuerClient)
+	require.Nil(t, err)
+	_, err = did.AddDidDocToChain(string(doc), issuerClient)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+	err = json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+	issuerDid := document.Id
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+	_, err = vc.AddVcIssueLogToChain(issuerDid, issuerDid, "vc1", "1", issuerClient)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &countingClient{Client: sim.CreatorClient()}
+	c := New(client, Options{TTL: time.Hour, ReconnectInterval: time.Millisecond})
+	require.Nil(t, c.Watch(ctx))
+
+	// 缓存命中时不查询链
+	got, err := c.GetDidDocument(ctx, issuerDid)
+	require.Nil(t, err)
+	require.Equal(t, doc, got)
+	_, err = c.GetVcTemplate(ctx, "1")
+	require.Nil(t, err)
+	revoked, err := c.GetVcRevocation(ctx, issuerDid, "vc1")
+	require.Nil(t, err)
+	require.Nil(t, revoked)
+	black, err := c.IsInBlackList(ctx, "did:cm:test1", time.Now())
+	require.Nil(t, err)
+	require.False(t, black)
+
+	queries := client.count()
+	_, err = c.GetDidDocument(ctx, issuerDid)
+	require.Nil(t, err)
+	_, err = c.GetVcRevocation(ctx, issuerDid, "vc1")
+	require.Nil(t, err)
+	require.Equal(t, queries, client.count())
+
+	// 收到事件时缓存失效
+	_, err = vc.RevokeVCOnChain("vc1", issuerClient)
+	require.Nil(t, err)
+	newKeyInfo, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+	newDoc, err := did.UpdateDidDoc(document, []*key.KeyInfo{newKeyInfo})
+	require.Nil(t, err)
+	_, err = did.UpdateDidDocToChain(string(newDoc), issuerClient)
+	require.Nil(t, err)
+
+	_, err = did.AddDidBlackListToChain([]string{"did:cm:test1"}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	waitUntil(t, func() bool {
+		black, err = c.IsInBlackList(ctx, "did:cm:test1", time.Now())
+		require.Nil(t, err)
+		return black
+	})
+
+	got, err = c.GetDidDocument(ctx, issuerDid)
+	require.Nil(t, err)
+	require.Equal(t, newDoc, got)
+
+	// 吊销记录由事件直接写入
+	queries = client.count()
+	revoked, err = c.GetVcRevocation(ctx, issuerDid, "vc1")
+	require.Nil(t, err)
+	require.NotNil(t, revoked)
+	require.Equal(t, queries, client.count())
+
+	// 超过有效期后重新从链上获取
+	c.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = c.GetVcTemplate(ctx, "1")
+	require.Nil(t, err)
+	require.Equal(t, queries+1, client.count())
+
+	// 离线时使用缓存，过期的缓存仍然可用
+	atomic.StoreInt32(&client.offline, 1)
+	c.SetOffline(true)
+
+	got, err = c.GetDidDocument(ctx, issuerDid)
+	require.Nil(t, err)
+	require.Equal(t, newDoc, got)
+	revoked, err = c.GetVcRevocation(ctx, issuerDid, "vc1")
+	require.Nil(t, err)
+	require.NotNil(t, revoked)
+
+	_, err = c.GetVcTemplate(ctx, "2")
+	require.True(t, errors.Is(err, ErrCacheMiss))
+}
+
+func TestCacheTrust(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulator(creatorKey.PkPEM, "cm", true)
+	require.Nil(t, err)
+
+	issuerKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+	issuerClient, err := sim.NewClient(issuerKey.PkPEM)
+	require.Nil(t, err)
+	doc, err := did.GenerateDidDoc([]*key.KeyInfo{issuerKey}, issuerClient)
+	require.Nil(t, err)
+	_, err = did.AddDidDocToChain(string(doc), issuerClient)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+	require.Nil(t, json.Unmarshal(doc, &document))
+	issuerDid := document.Id
+
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDid}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &countingClient{Client: sim.CreatorClient()}
+	c := New(client, Options{TTL: time.Hour, ReconnectInterval: time.Millisecond})
+	require.Nil(t, c.Watch(ctx))
+
+	enable, err := c.IsTrustIssuerEnabled(ctx)
+	require.Nil(t, err)
+	require.True(t, enable)
+	chain, err := c.GetAccreditationChain(ctx, issuerDid)
+	require.Nil(t, err)
+	require.Len(t, chain, 1)
+
+	// 缓存命中时不查询链
+	queries := client.count()
+	_, err = c.GetAccreditationChain(ctx, issuerDid)
+	require.Nil(t, err)
+	require.Equal(t, queries, client.count())
+
+	// 删除可信签发者后认证链缓存失效
+	_, err = did.DeleteTrustIssuerListFromChain([]string{issuerDid}, sim.CreatorClient())
+	require.Nil(t, err)
+	waitUntil(t, func() bool {
+		_, err = c.GetAccreditationChain(ctx, issuerDid)
+		return errors.Is(err, invoke.ErrNotTrustedIssuer)
+	})
+
+	// 修改合约配置事件直接写入是否开启可信签发者检查
+	config, err := admin.GetContractConfigOfDidContract(sim.CreatorClient())
+	require.Nil(t, err)
+	config.EnableTrustIssuer = false
+	_, err = admin.SetContractConfigForDidContract(config, sim.CreatorClient())
+	require.Nil(t, err)
+	waitUntil(t, func() bool {
+		enable, err = c.IsTrustIssuerEnabled(ctx)
+		require.Nil(t, err)
+		return !enable
+	})
+
+	// 缓存可以直接作为本地验证的全部数据源
+	opts := c.Options()
+	require.Equal(t, c, opts.Trust)
+}
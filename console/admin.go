@@ -9,6 +9,7 @@ package main
 
 import (
 	"did-sdk/admin"
+	"did-sdk/invoke"
 	"fmt"
 	"strings"
 
@@ -27,11 +28,16 @@ func AdminCMD() *cobra.Command {
 	adminCmd.AddCommand(adminAdd())
 	adminCmd.AddCommand(adminDelete())
 	adminCmd.AddCommand(authAdmin())
+	adminCmd.AddCommand(adminList())
+	adminCmd.AddCommand(adminRole())
+	adminCmd.AddCommand(adminProposal())
+	adminCmd.AddCommand(adminConfig())
+	adminCmd.AddCommand(adminMigrate())
 	return adminCmd
 }
 
 func adminAdd() *cobra.Command {
-	var sdkPath, adminSdkPath string
+	var sdkPath, adminSdkPath, didStr string
 
 	adminAddCmd := &cobra.Command{
 		Use:   "add",
@@ -42,6 +48,10 @@ Example:
 $ ./console admin add \
 --admin-sdk-path=./testdata/sdk_config2.yml \
 --sdk-path=./testdata/sdk_config.yml
+
+$ ./console admin add \
+--did=did:cm:admin1 \
+--sdk-path=./testdata/sdk_config.yml
 `,
 		),
 
@@ -51,15 +61,25 @@ $ ./console admin add \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			if len(adminSdkPath) == 0 {
-				return ParamsEmptyError(ParamsFlagAdminSdkPath)
-			}
-
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
+			// 指定DID时，DID Document中所有验证方法的公钥都将作为管理员
+			if len(didStr) != 0 {
+				receipt, err := admin.SetAdminByDidForDidContract(didStr, c)
+				if err != nil {
+					return err
+				}
+
+				return printTxReceipt(receipt)
+			}
+
+			if len(adminSdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagAdminSdkPath)
+			}
+
 			adminc, err := cmsdk.NewChainClient(cmsdk.WithConfPath(adminSdkPath))
 			if err != nil {
 				return err
@@ -70,25 +90,24 @@ $ ./console admin add \
 				return err
 			}
 
-			err = admin.SetAdminForDidContract([]byte(adminPk), c)
+			receipt, err := admin.SetAdminForDidContract([]byte(adminPk), c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
 	attachFlagString(adminAddCmd, ParamsFlagCMSdkPath, &sdkPath)
 	attachFlagString(adminAddCmd, ParamsFlagAdminSdkPath, &adminSdkPath)
+	attachFlagString(adminAddCmd, ParamsFlagDid, &didStr)
 
 	return adminAddCmd
 }
 
 func adminDelete() *cobra.Command {
-	var sdkPath, adminSdkPath string
+	var sdkPath, adminSdkPath, didStr string
 
 	adminDeleteCmd := &cobra.Command{
 		Use:   "delete",
@@ -99,6 +118,10 @@ Example:
 $ ./console admin delete \
 --admin-sdk-path=./testdata/sdk_config2.yml \
 --sdk-path=./testdata/sdk_config.yml
+
+$ ./console admin delete \
+--did=did:cm:admin1 \
+--sdk-path=./testdata/sdk_config.yml
 `,
 		),
 
@@ -108,15 +131,25 @@ $ ./console admin delete \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			if len(adminSdkPath) == 0 {
-				return ParamsEmptyError(ParamsFlagAdminSdkPath)
-			}
-
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
+			// 指定DID时，DID Document中所有验证方法的公钥都将作为管理员
+			if len(didStr) != 0 {
+				receipt, err := admin.DeleteAdminByDidForDidContract(didStr, c)
+				if err != nil {
+					return err
+				}
+
+				return printTxReceipt(receipt)
+			}
+
+			if len(adminSdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagAdminSdkPath)
+			}
+
 			adminc, err := cmsdk.NewChainClient(cmsdk.WithConfPath(adminSdkPath))
 			if err != nil {
 				return err
@@ -127,19 +160,18 @@ $ ./console admin delete \
 				return err
 			}
 
-			err = admin.DeleteAdminForDidContract([]byte(adminPk), c)
+			receipt, err := admin.DeleteAdminForDidContract([]byte(adminPk), c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
 	attachFlagString(adminDeleteCmd, ParamsFlagCMSdkPath, &sdkPath)
 	attachFlagString(adminDeleteCmd, ParamsFlagAdminSdkPath, &adminSdkPath)
+	attachFlagString(adminDeleteCmd, ParamsFlagDid, &didStr)
 
 	return adminDeleteCmd
 }
@@ -174,7 +206,7 @@ $ ./console admin auth \
 				return err
 			}
 
-			ok, err := admin.IsAdminOfDidContract([]byte(pk), c)
+			ok, err := admin.IsAdminOfDidContract([]byte(pk), invoke.NewClient(c, contractName))
 			if err != nil {
 				return err
 			}
@@ -189,3 +221,46 @@ $ ./console admin auth \
 
 	return authAdminCmd
 }
+
+func adminList() *cobra.Command {
+	var sdkPath string
+
+	adminListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Get did admin list",
+		Long: strings.TrimSpace(
+			`Get the admin list of did contract, the first one is the creator of the contract.
+Example:
+$ ./console admin list \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			list, err := admin.GetAdminListOfDidContract(c)
+			if err != nil {
+				return err
+			}
+
+			for _, v := range list {
+				fmt.Printf("ski: [%s], did: [%s], creator: [%v]\n", v.Ski, v.Did, v.IsCreator)
+			}
+
+			return nil
+		},
+	}
+
+	attachFlagString(adminListCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return adminListCmd
+}
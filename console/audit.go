@@ -0,0 +1,165 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"did-sdk/admin"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/spf13/cobra"
+)
+
+const (
+	auditFormatJson = "json"
+	auditFormatCsv  = "csv"
+)
+
+func AuditCMD() *cobra.Command {
+	var method, operator, startTime, endTime, format, output, sdkPath string
+	var start, count int
+
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Export the audit log of did contract",
+		Long: strings.TrimSpace(
+			`Export the audit log of did contract, every state-changing method appends an audit record on blockchain.
+Example:
+$ ./console audit \
+--method=AddBlackList \
+--operator=did:cm:admin \
+--start-time=2024-01-01 \
+--end-time=2024-12-31 \
+--format=csv \
+--output=./testdata/audit.csv \
+--sdk-path=./testdata/sdk_config.yml
+
+If --output is not specified, the audit log is printed to the console.
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(format) == 0 {
+				format = auditFormatJson
+			}
+
+			if format != auditFormatJson && format != auditFormatCsv {
+				return fmt.Errorf("unsupported format: [%s], eg. json,csv", format)
+			}
+
+			var startUnix, endUnix int64
+			if len(startTime) != 0 {
+				t, err := time.ParseInLocation("2006-01-02", startTime, time.Local)
+				if err != nil {
+					return err
+				}
+				startUnix = t.Unix()
+			}
+
+			if len(endTime) != 0 {
+				t, err := time.ParseInLocation("2006-01-02", endTime, time.Local)
+				if err != nil {
+					return err
+				}
+				// 结束日期包含当天
+				endUnix = t.AddDate(0, 0, 1).Unix() - 1
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			list, err := admin.GetAuditLogOfDidContract(method, operator, startUnix, endUnix, start, count, c)
+			if err != nil {
+				return err
+			}
+
+			var data []byte
+			if format == auditFormatCsv {
+				data, err = auditLogToCsv(list)
+			} else {
+				data, err = json.MarshalIndent(list, "", "  ")
+			}
+			if err != nil {
+				return err
+			}
+
+			if len(output) == 0 {
+				fmt.Println(string(data))
+				return nil
+			}
+
+			err = os.WriteFile(output, data, 0600)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(ConsoleOutputSuccessfulOperation)
+
+			return nil
+		},
+	}
+
+	attachFlagString(auditCmd, ParamsFlagMethod, &method)
+	attachFlagString(auditCmd, ParamsFlagOperator, &operator)
+	attachFlagString(auditCmd, ParamsFlagStartTime, &startTime)
+	attachFlagString(auditCmd, ParamsFlagEndTime, &endTime)
+	attachFlagString(auditCmd, ParamsFlagFormat, &format)
+	attachFlagString(auditCmd, ParamsFlagOutput, &output)
+	attachFlagInt(auditCmd, ParamsFlagListStart, &start)
+	attachFlagInt(auditCmd, ParamsFlagListCount, &count)
+	attachFlagString(auditCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return auditCmd
+}
+
+// auditLogToCsv 将审计日志转换为CSV格式，第一行为表头
+func auditLogToCsv(list []*model.AuditRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	err := w.Write([]string{"seq", "txId", "method", "operator", "operatorDid", "paramsDigest", "txTime"})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range list {
+		err = w.Write([]string{
+			strconv.FormatUint(v.Seq, 10),
+			v.TxId,
+			v.Method,
+			v.Operator,
+			v.OperatorDid,
+			v.ParamsDigest,
+			time.Unix(v.TxTime, 0).Format(time.RFC3339),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
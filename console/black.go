@@ -11,8 +11,8 @@ import (
 	"did-sdk/did"
 	"fmt"
 	"strings"
+	"time"
 
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -32,16 +32,22 @@ func BlackCMD() *cobra.Command {
 
 func blackAdd() *cobra.Command {
 	var dids []string
-	var sdkPath string
+	var sdkPath, reason, expiration string
+	var reasonCode int
+	var expireTime int64
 
 	blackAddCmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add did black list",
 		Long: strings.TrimSpace(
 			`Add did black list DID to blockchain.
+Reason codes: 0:unspecified,1:key compromise,2:fraud,3:legal order,4:violation
 Example:
 $ ./console black add \
 --dids=did:cm:test1,did:cm:test2 \
+--reason-code=2 \
+--reason="fraudulent activity" \
+--expiration=2025-01-25 \
 --sdk-path=./testdata/sdk_config.yml 
 `,
 		),
@@ -56,24 +62,35 @@ $ ./console black add \
 				return ParamsEmptyError(ParamsFlagDids)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			// 不指定过期时间则永久有效
+			if len(expiration) != 0 {
+				t, err := time.ParseInLocation("2006-01-02", expiration, time.Local)
+				if err != nil {
+					return err
+				}
+
+				expireTime = t.Unix()
+			}
+
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
-			err = did.AddDidBlackListToChain(dids, c)
+			receipt, err := did.AddDidBlackListWithReasonToChain(dids, reasonCode, reason, expireTime, c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
 	attachFlagString(blackAddCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagString(blackAddCmd, ParamsFlagReason, &reason)
+	attachFlagString(blackAddCmd, ParamsFlagExpiration, &expiration)
 	attachFlagStringSlice(blackAddCmd, ParamsFlagDids, &dids)
+	attachFlagInt(blackAddCmd, ParamsFlagReasonCode, &reasonCode)
 
 	return blackAddCmd
 }
@@ -102,7 +119,7 @@ $ ./console black list \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -112,7 +129,9 @@ $ ./console black list \
 				return err
 			}
 
-			fmt.Printf("get the did black list: [%+v]\n", list)
+			for _, v := range list {
+				fmt.Printf("%+v\n", v)
+			}
 
 			return nil
 		},
@@ -151,19 +170,17 @@ $ ./console black delete \
 				return ParamsEmptyError(ParamsFlagDids)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
-			err = did.DeleteDidBlackListFromChain(dids, c)
+			receipt, err := did.DeleteDidBlackListFromChain(dids, c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
@@ -0,0 +1,84 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"did-sdk/did"
+	"did-sdk/invoke"
+	"did-sdk/key"
+	"did-sdk/simulator"
+	"testing"
+
+	"github.com/test-go/testify/require"
+)
+
+// useSimulator 使控制台命令连接进程内的链模拟器，所有命令都以合约创建者身份发送交易
+func useSimulator(t *testing.T) *simulator.Client {
+	return useSimulatorWithContractName(t, invoke.DIDContractName)
+}
+
+// useSimulatorWithContractName 同useSimulator，模拟器中的DID合约以指定的名称安装
+func useSimulatorWithContractName(t *testing.T, contractName string) *simulator.Client {
+	keyInfo, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulatorWithContractName(contractName, keyInfo.PkPEM, "cm", false)
+	require.Nil(t, err)
+
+	c := sim.CreatorClient()
+
+	old := connectChainClient
+	connectChainClient = func(_ string) (invoke.ChainClient, error) {
+		return c, nil
+	}
+	t.Cleanup(func() {
+		connectChainClient = old
+	})
+
+	return c
+}
+
+func TestBlackCMD(t *testing.T) {
+	c := useSimulator(t)
+
+	cmd := BlackCMD()
+	cmd.SetArgs([]string{"add", "--sdk-path=simulator", "--dids=did:cm:test1,did:cm:test2", "--reason-code=2"})
+	require.Nil(t, cmd.Execute())
+
+	list, err := did.GetDidBlackListFromChain("", 0, 0, c)
+	require.Nil(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, 2, list[0].ReasonCode)
+
+	cmd = BlackCMD()
+	cmd.SetArgs([]string{"delete", "--sdk-path=simulator", "--dids=did:cm:test1"})
+	require.Nil(t, cmd.Execute())
+
+	list, err = did.GetDidBlackListFromChain("", 0, 0, c)
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+}
+
+func TestContractNameFlag(t *testing.T) {
+	c := useSimulatorWithContractName(t, "DidStaging")
+	t.Cleanup(func() {
+		contractName = invoke.DIDContractName
+	})
+
+	// 默认调用ChainMakerDid合约
+	cmd := newMainCmd()
+	cmd.SetArgs([]string{"black", "add", "--sdk-path=simulator", "--dids=did:cm:test1"})
+	require.NotNil(t, cmd.Execute())
+
+	cmd = newMainCmd()
+	cmd.SetArgs([]string{"black", "add", "--sdk-path=simulator", "--dids=did:cm:test1", "--contract-name=DidStaging"})
+	require.Nil(t, cmd.Execute())
+
+	list, err := did.GetDidBlackListFromChain("", 0, 0, invoke.NewClient(c, "DidStaging"))
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+}
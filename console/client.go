@@ -0,0 +1,50 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/invoke"
+	"encoding/json"
+	"fmt"
+
+	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
+)
+
+// contractName 调用的DID合约名称，通过--contract-name指定
+var contractName = invoke.DIDContractName
+
+// connectChainClient 根据长安链SDK配置文件连接长安链，离线测试时可以替换为链模拟器的客户端
+var connectChainClient = func(sdkPath string) (invoke.ChainClient, error) {
+	c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newChainClient 创建调用--contract-name指定的DID合约的客户端
+func newChainClient(sdkPath string) (invoke.ChainClient, error) {
+	c, err := connectChainClient(sdkPath)
+	if err != nil {
+		return nil, err
+	}
+	return invoke.NewClient(c, contractName), nil
+}
+
+// printTxReceipt 输出操作成功和交易回执
+func printTxReceipt(receipt *invoke.TxReceipt) error {
+	data, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ConsoleOutputSuccessfulOperation)
+	fmt.Println(string(data))
+
+	return nil
+}
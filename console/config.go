@@ -0,0 +1,142 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/admin"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func adminConfig() *cobra.Command {
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "ChainMaker DID contract config command",
+		Long:  "ChainMaker DID contract config command",
+	}
+
+	configCmd.AddCommand(configGet())
+	configCmd.AddCommand(configSet())
+	return configCmd
+}
+
+func configGet() *cobra.Command {
+	var sdkPath string
+
+	configGetCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get the config of did contract",
+		Long: strings.TrimSpace(
+			`Get the runtime config of did contract.
+Example:
+$ ./console admin config get \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			config, err := admin.GetContractConfigOfDidContract(c)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%+v\n", config)
+
+			return nil
+		},
+	}
+
+	attachFlagString(configGetCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return configGetCmd
+}
+
+func configSet() *cobra.Command {
+	var enableTrustIssuer bool
+	var maxDocSize, pageSize int
+	var sdkPath string
+
+	configSetCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the config of did contract",
+		Long: strings.TrimSpace(
+			`Set the runtime config of did contract, only admin can do it.
+Only the specified flags are changed, the others keep the current value.
+Example:
+$ ./console admin config set \
+--enable-trust-issuer=true \
+--max-doc-size=10240 \
+--page-size=100 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			flags := cmd.Flags()
+			if !flags.Changed(ParamsFlagTrustIssuer) && !flags.Changed(ParamsFlagMaxDocSize) &&
+				!flags.Changed(ParamsFlagPageSize) {
+				return fmt.Errorf("at least one of the parameters [%s], [%s], [%s] must be specified",
+					ParamsFlagTrustIssuer, ParamsFlagMaxDocSize, ParamsFlagPageSize)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			config, err := admin.GetContractConfigOfDidContract(c)
+			if err != nil {
+				return err
+			}
+
+			if flags.Changed(ParamsFlagTrustIssuer) {
+				config.EnableTrustIssuer = enableTrustIssuer
+			}
+
+			if flags.Changed(ParamsFlagMaxDocSize) {
+				config.MaxDocumentSize = maxDocSize
+			}
+
+			if flags.Changed(ParamsFlagPageSize) {
+				config.DefaultPageSize = pageSize
+			}
+
+			receipt, err := admin.SetContractConfigForDidContract(config, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagBool(configSetCmd, ParamsFlagTrustIssuer, &enableTrustIssuer)
+	attachFlagInt(configSetCmd, ParamsFlagMaxDocSize, &maxDocSize)
+	attachFlagInt(configSetCmd, ParamsFlagPageSize, &pageSize)
+	attachFlagString(configSetCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return configSetCmd
+}
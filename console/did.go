@@ -13,7 +13,6 @@ import (
 	"os"
 	"strings"
 
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -52,7 +51,7 @@ $ ./console did method \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -98,7 +97,7 @@ $ ./console did gen \
 				return ParamsEmptyError(ParamsFlagPkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -149,7 +148,7 @@ $ ./console did valid \
 				return ParamsEmptyError(ParamsFlagDid)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -191,7 +190,7 @@ $ ./console did get \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
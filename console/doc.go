@@ -17,7 +17,6 @@ import (
 	"strings"
 
 	"chainmaker.org/chainmaker/did-contract/model"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -71,7 +70,7 @@ $ ./console doc gen \
 				return ParamsEmptyError(ParamsFlagSksPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -152,7 +151,7 @@ $ ./console doc add \
 				return ParamsEmptyError(ParamsFlagDocPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -162,14 +161,12 @@ $ ./console doc add \
 				return err
 			}
 
-			err = did.AddDidDocToChain(string(doc), c)
+			receipt, err := did.AddDidDocToChain(string(doc), c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
@@ -208,7 +205,7 @@ $ ./console doc get \
 				return ParamsEmptyError(ParamsFlagDocPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -351,7 +348,7 @@ $ ./console doc update \
 				return ParamsEmptyError(ParamsFlagDocPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -361,14 +358,12 @@ $ ./console doc update \
 				return err
 			}
 
-			err = did.UpdateDidDocToChain(string(doc), c)
+			receipt, err := did.UpdateDidDocToChain(string(doc), c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
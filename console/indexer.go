@@ -0,0 +1,102 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/indexer"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+func IndexerCMD() *cobra.Command {
+
+	indexerCmd := &cobra.Command{
+		Use:   "indexer",
+		Short: "ChainMaker DID indexer command",
+		Long:  "ChainMaker DID indexer command",
+	}
+
+	indexerCmd.AddCommand(indexerRunCmd())
+
+	return indexerCmd
+}
+
+func indexerRunCmd() *cobra.Command {
+	var dbPath, sdkPath string
+
+	indexerRunCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the local indexer of did contract",
+		Long: strings.TrimSpace(
+			`Subscribe the events of did contract and write them into the local index db until interrupted.
+The indexer resumes from the last indexed block, the index db can be queried by the Go API of package indexer.
+Example:
+$ ./console indexer run \
+--db-path=./testdata/did_index.db \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if len(dbPath) == 0 {
+				return ParamsEmptyError(ParamsFlagDbPath)
+			}
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			ix, err := indexer.Open(dbPath)
+			if err != nil {
+				return err
+			}
+			defer ix.Close()
+
+			height, err := ix.LastBlockHeight()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("indexer is running, last indexed block height: %d\n", height)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			err = ix.Run(ctx, c, indexer.RunOptions{
+				OnError: func(err error) {
+					fmt.Println("indexer error:", err)
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			height, err = ix.LastBlockHeight()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("indexer stopped, last indexed block height: %d\n", height)
+
+			return nil
+		},
+	}
+
+	attachFlagString(indexerRunCmd, ParamsFlagDbPath, &dbPath)
+	attachFlagString(indexerRunCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return indexerRunCmd
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"context"
+	"did-sdk/did"
+	"did-sdk/indexer"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+func TestIndexerRunCMD(t *testing.T) {
+	c := useSimulator(t)
+
+	receipt, err := did.AddDidBlackListToChain([]string{"did:cm:test1", "did:cm:test2"}, c)
+	require.Nil(t, err)
+
+	dbPath := filepath.Join(t.TempDir(), "index.db")
+
+	// 每次运行一段时间后中断，直到索引到黑名单交易所在的区块
+	var height int64
+	for deadline := time.Now().Add(5 * time.Second); height < int64(receipt.BlockHeight); {
+		require.True(t, time.Now().Before(deadline), "wait index timeout")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		cmd := IndexerCMD()
+		cmd.SetArgs([]string{"run", "--sdk-path=simulator", "--db-path=" + dbPath})
+		err = cmd.ExecuteContext(ctx)
+		cancel()
+		require.Nil(t, err)
+
+		ix, err := indexer.Open(dbPath)
+		require.Nil(t, err)
+		height, err = ix.LastBlockHeight()
+		require.Nil(t, err)
+		require.Nil(t, ix.Close())
+	}
+
+	ix, err := indexer.Open(dbPath)
+	require.Nil(t, err)
+	defer ix.Close()
+
+	list, err := ix.GetDidBlackList(0, 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"did:cm:test1", "did:cm:test2"}, list)
+}
@@ -9,10 +9,10 @@ package main
 
 import (
 	"did-sdk/did"
+	"did-sdk/invoke"
 	"fmt"
 	"strings"
 
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -26,13 +26,19 @@ func IssuerCMD() *cobra.Command {
 
 	issuerCmd.AddCommand(issuerAdd())
 	issuerCmd.AddCommand(issuerList())
+	issuerCmd.AddCommand(issuerGet())
 	issuerCmd.AddCommand(issuerDelete())
+	issuerCmd.AddCommand(issuerAccredit())
+	issuerCmd.AddCommand(issuerRevokeAccreditation())
+	issuerCmd.AddCommand(issuerChain())
 	return issuerCmd
 }
 
 func issuerAdd() *cobra.Command {
-	var dids []string
+	var dids, templateIds []string
 	var sdkPath string
+	var delegable bool
+	var maxDepth int
 
 	issuerAddCmd := &cobra.Command{
 		Use:   "add",
@@ -42,7 +48,13 @@ func issuerAdd() *cobra.Command {
 Example:
 $ ./console issuer add \
 --dids=did:cm:test1,did:cm:test2 \
+--temp-id=template001,template002 \
+--delegable \
+--max-depth=2 \
 --sdk-path=./testdata/sdk_config.yml 
+
+If --temp-id is not specified, the issuers can issue the vc of all templates.
+If --delegable is specified, the issuers can accredit sub-issuers within --max-depth levels.
 `,
 		),
 
@@ -55,24 +67,30 @@ $ ./console issuer add \
 				return ParamsEmptyError(ParamsFlagDids)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
-			err = did.AddTrustIssuerListToChain(dids, c)
+			var receipt *invoke.TxReceipt
+			if delegable {
+				receipt, err = did.AddDelegableTrustIssuerListToChain(dids, maxDepth, c, templateIds...)
+			} else {
+				receipt, err = did.AddTrustIssuerListToChain(dids, c, templateIds...)
+			}
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
 	attachFlagString(issuerAddCmd, ParamsFlagCMSdkPath, &sdkPath)
 	attachFlagStringSlice(issuerAddCmd, ParamsFlagDids, &dids)
+	attachFlagStringSlice(issuerAddCmd, ParamsFlagTemplateId, &templateIds)
+	attachFlagBool(issuerAddCmd, ParamsFlagDelegable, &delegable)
+	attachFlagInt(issuerAddCmd, ParamsFlagMaxDepth, &maxDepth)
 
 	return issuerAddCmd
 }
@@ -101,7 +119,7 @@ $ ./console issuer list \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -125,6 +143,53 @@ $ ./console issuer list \
 	return issuerListCmd
 }
 
+func issuerGet() *cobra.Command {
+	var didStr, sdkPath string
+
+	issuerGetCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get issuer info",
+		Long: strings.TrimSpace(
+			`Get the trusted issuer info from blockchain, including the vc templates it can issue.
+Example:
+$ ./console issuer get \
+--did=did:cm:test1 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(didStr) == 0 {
+				return ParamsEmptyError(ParamsFlagDid)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			issuer, err := did.GetTrustIssuerFromChain(didStr, c)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%+v\n", issuer)
+
+			return nil
+		},
+	}
+
+	attachFlagString(issuerGetCmd, ParamsFlagDid, &didStr)
+	attachFlagString(issuerGetCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return issuerGetCmd
+}
+
 func issuerDelete() *cobra.Command {
 	var dids []string
 	var sdkPath string
@@ -151,19 +216,17 @@ $ ./console issuer delete \
 				return ParamsEmptyError(ParamsFlagDids)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
-			err = did.DeleteTrustIssuerListFromChain(dids, c)
+			receipt, err := did.DeleteTrustIssuerListFromChain(dids, c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
@@ -172,3 +235,154 @@ $ ./console issuer delete \
 
 	return issuerDeleteCmd
 }
+
+func issuerAccredit() *cobra.Command {
+	var didStr, sdkPath string
+	var templateIds []string
+	var delegable bool
+	var maxDepth int
+
+	issuerAccreditCmd := &cobra.Command{
+		Use:   "accredit",
+		Short: "Accredit a sub-issuer",
+		Long: strings.TrimSpace(
+			`Accredit a sub-issuer on blockchain by the trusted issuer with delegation rights.
+The accreditor is the DID of the user in the sdk config file.
+Example:
+$ ./console issuer accredit \
+--did=did:cm:test2 \
+--temp-id=template001 \
+--delegable \
+--max-depth=1 \
+--sdk-path=./testdata/sdk_config.yml
+
+If --temp-id is not specified, the sub-issuer inherits the templates of the accreditor.
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(didStr) == 0 {
+				return ParamsEmptyError(ParamsFlagDid)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := did.AccreditIssuerToChain(didStr, delegable, maxDepth, c, templateIds...)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(issuerAccreditCmd, ParamsFlagDid, &didStr)
+	attachFlagString(issuerAccreditCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagStringSlice(issuerAccreditCmd, ParamsFlagTemplateId, &templateIds)
+	attachFlagBool(issuerAccreditCmd, ParamsFlagDelegable, &delegable)
+	attachFlagInt(issuerAccreditCmd, ParamsFlagMaxDepth, &maxDepth)
+
+	return issuerAccreditCmd
+}
+
+func issuerRevokeAccreditation() *cobra.Command {
+	var didStr, sdkPath string
+
+	issuerRevokeCmd := &cobra.Command{
+		Use:   "revoke-accredit",
+		Short: "Revoke the accreditation of a sub-issuer",
+		Long: strings.TrimSpace(
+			`Revoke the accreditation of a sub-issuer on blockchain, only the accreditor or admin can do it.
+Example:
+$ ./console issuer revoke-accredit \
+--did=did:cm:test2 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(didStr) == 0 {
+				return ParamsEmptyError(ParamsFlagDid)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := did.RevokeIssuerAccreditationFromChain(didStr, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(issuerRevokeCmd, ParamsFlagDid, &didStr)
+	attachFlagString(issuerRevokeCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return issuerRevokeCmd
+}
+
+func issuerChain() *cobra.Command {
+	var didStr, sdkPath string
+
+	issuerChainCmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Get the accreditation chain of issuer",
+		Long: strings.TrimSpace(
+			`Get the accreditation chain of issuer from blockchain, from the issuer itself to the root issuer.
+Example:
+$ ./console issuer chain \
+--did=did:cm:test2 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(didStr) == 0 {
+				return ParamsEmptyError(ParamsFlagDid)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			chain, err := did.GetIssuerAccreditationChainFromChain(didStr, c)
+			if err != nil {
+				return err
+			}
+
+			for _, v := range chain {
+				fmt.Printf("%+v\n", v)
+			}
+
+			return nil
+		},
+	}
+
+	attachFlagString(issuerChainCmd, ParamsFlagDid, &didStr)
+	attachFlagString(issuerChainCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return issuerChainCmd
+}
@@ -14,6 +14,13 @@ import (
 )
 
 func main() {
+	err := newMainCmd().Execute()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newMainCmd() *cobra.Command {
 	mainCmd := &cobra.Command{
 		Use:   "console",
 		Short: "ChainMaker DID CLI",
@@ -31,10 +38,11 @@ func main() {
 	mainCmd.AddCommand(VcCMD())
 	mainCmd.AddCommand(VpCMD())
 	mainCmd.AddCommand(AdminCMD())
+	mainCmd.AddCommand(AuditCMD())
+	mainCmd.AddCommand(TxCMD())
+	mainCmd.AddCommand(IndexerCMD())
 
-	err := mainCmd.Execute()
-	if err != nil {
-		panic(err)
-	}
+	attachPersistentFlagString(mainCmd, ParamsFlagContractName, &contractName)
 
+	return mainCmd
 }
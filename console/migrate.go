@@ -0,0 +1,74 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/admin"
+	"did-sdk/invoke"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func adminMigrate() *cobra.Command {
+	var batch int
+	var sdkPath string
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the data of did contract",
+		Long: strings.TrimSpace(
+			`Continue the data migration after upgrading did contract, only admin can do it.
+Transactions are sent one by one until the migration is finished, each transaction processes
+at most --batch records for each migration step.
+Example:
+$ ./console admin migrate \
+--batch=500 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			status, err := admin.GetMigrationStatusOfDidContract(c)
+			if err != nil {
+				return err
+			}
+
+			for !status.IsDone() {
+				var receipt *invoke.TxReceipt
+				status, receipt, err = admin.MigrateDidContract(batch, c)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("schema version: [%d/%d], cursor: [%s], tx id: [%s], block height: [%d]\n",
+					status.SchemaVersion, status.LatestVersion, status.Cursor, receipt.TxId, receipt.BlockHeight)
+			}
+
+			fmt.Println(ConsoleOutputSuccessfulOperation)
+
+			return nil
+		},
+	}
+
+	attachFlagInt(migrateCmd, ParamsFlagBatch, &batch)
+	attachFlagString(migrateCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return migrateCmd
+}
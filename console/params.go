@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"did-sdk/invoke"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -55,6 +56,32 @@ const (
 	ParamsFlagMapKey          = "map-key"
 	ParamsFlagMapValue        = "map-value"
 	ParamsFlagAdminSdkPath    = "admin-sdk-path"
+	ParamsFlagReasonCode      = "reason-code"
+	ParamsFlagReason          = "reason"
+	ParamsFlagDelegable       = "delegable"
+	ParamsFlagMaxDepth        = "max-depth"
+	ParamsFlagRole            = "role"
+	ParamsFlagAction          = "action"
+	ParamsFlagActionParams    = "action-params"
+	ParamsFlagStatus          = "status"
+	ParamsFlagQuorum          = "quorum"
+	ParamsFlagMethod          = "method"
+	ParamsFlagOperator        = "operator"
+	ParamsFlagStartTime       = "start-time"
+	ParamsFlagEndTime         = "end-time"
+	ParamsFlagFormat          = "format"
+	ParamsFlagOutput          = "output"
+	ParamsFlagTrustIssuer     = "enable-trust-issuer"
+	ParamsFlagMaxDocSize      = "max-doc-size"
+	ParamsFlagPageSize        = "page-size"
+	ParamsFlagBatch           = "batch"
+	ParamsFlagTxId            = "tx-id"
+	ParamsFlagContractName    = "contract-name"
+	ParamsFlagDbPath          = "db-path"
+	ParamsFlagReport          = "report"
+	ParamsFlagSnapshotPath    = "snapshot-path"
+	ParamsFlagChallenge       = "challenge"
+	ParamsFlagDomain          = "domain"
 )
 
 var paramsList = map[string]struct {
@@ -85,7 +112,7 @@ var paramsList = map[string]struct {
 	ParamsFlagTemplatePath:    {"", "", "specify path of vc template"},
 	ParamsFlagKeyIndex:        {"", "", "specify the index of the key in the DID document, [1,n]"},
 	ParamsFlagSubjectPath:     {"", "", "specify the path of the vc's subject"},
-	ParamsFlagExpiration:      {"", "", "specify the validity period of the vc, format [yy-mm-dd]"},
+	ParamsFlagExpiration:      {"", "", "specify the expiration date, format [yyyy-mm-dd]"},
 	ParamsFlagVcPath:          {"", "", "specify the path of vc"},
 	ParamsFlagType:            {"", "", "specify the type of vc or vp"},
 	ParamsFlagIssuer:          {"", "", "specify the issuer's did of vc"},
@@ -95,6 +122,32 @@ var paramsList = map[string]struct {
 	ParamsFlagMapKey:          {"", "", "specify the key list of vc template"},
 	ParamsFlagMapValue:        {"", "", "specify the value list of vc template"},
 	ParamsFlagAdminSdkPath:    {"", "", "specify the path of admin's sdk config file"},
+	ParamsFlagReasonCode:      {"", "", "specify the reason code, see the help of the command for the codes"},
+	ParamsFlagReason:          {"", "", "specify the reason description"},
+	ParamsFlagDelegable:       {"", "", "specify whether the issuer can accredit sub-issuers"},
+	ParamsFlagMaxDepth:        {"", "", "specify the max depth of accreditation, 0 means unlimited"},
+	ParamsFlagRole:            {"", "", "specify the role, eg. template-manager,blacklist-manager,issuer-manager,did-operator,vc-manager"},
+	ParamsFlagAction:          {"", "", "specify the contract method executed by the proposal"},
+	ParamsFlagActionParams:    {"", "", "specify the parameters of the proposal action in json"},
+	ParamsFlagStatus:          {"", "", "specify the status, eg. pending,executed,canceled,expired"},
+	ParamsFlagQuorum:          {"", "", "specify the quorum of the governance proposal"},
+	ParamsFlagMethod:          {"", "", "specify the method of did contract"},
+	ParamsFlagOperator:        {"", "", "specify the operator, the ski of public key or did"},
+	ParamsFlagStartTime:       {"", "", "specify the start date, format [yyyy-mm-dd]"},
+	ParamsFlagEndTime:         {"", "", "specify the end date, format [yyyy-mm-dd]"},
+	ParamsFlagFormat:          {"", "", "specify the output format, eg. json,csv"},
+	ParamsFlagOutput:          {"o", "", "specify the path of output file"},
+	ParamsFlagTrustIssuer:     {"", "", "specify whether to enable the trust issuer"},
+	ParamsFlagMaxDocSize:      {"", "", "specify the max size of did document in bytes, 0 means unlimited"},
+	ParamsFlagPageSize:        {"", "", "specify the default size of query list"},
+	ParamsFlagBatch:           {"", "", "specify the max number of records processed in one transaction"},
+	ParamsFlagTxId:            {"", "", "specify the transaction ID"},
+	ParamsFlagContractName:    {"", invoke.DIDContractName, "specify the name of did contract"},
+	ParamsFlagDbPath:          {"", "", "specify the path of local index db"},
+	ParamsFlagReport:          {"", "", "specify whether to print the result of each verification check"},
+	ParamsFlagSnapshotPath:    {"", "", "specify the path of the snapshot of trust data used for local verification"},
+	ParamsFlagChallenge:       {"", "", "specify the challenge provided by the verifier of vp"},
+	ParamsFlagDomain:          {"", "", "specify the domain of the verifier of vp"},
 }
 
 func attachFlagString(cmd *cobra.Command, key string, params *string) {
@@ -108,6 +161,17 @@ func attachFlagString(cmd *cobra.Command, key string, params *string) {
 	flags.StringVarP(params, key, f.shorthand, f.stringValue, f.usage)
 }
 
+func attachPersistentFlagString(cmd *cobra.Command, key string, params *string) {
+	flags := cmd.PersistentFlags()
+
+	f, ok := paramsList[key]
+	if !ok {
+		panic("the flag was not found")
+	}
+
+	flags.StringVarP(params, key, f.shorthand, f.stringValue, f.usage)
+}
+
 func attachFlagStringSlice(cmd *cobra.Command, key string, params *[]string) {
 	flags := cmd.Flags()
 
@@ -129,3 +193,14 @@ func attachFlagInt(cmd *cobra.Command, key string, params *int) {
 
 	flags.IntVarP(params, key, f.shorthand, 0, f.usage)
 }
+
+func attachFlagBool(cmd *cobra.Command, key string, params *bool) {
+	flags := cmd.Flags()
+
+	f, ok := paramsList[key]
+	if !ok {
+		panic("the flag was not found")
+	}
+
+	flags.BoolVarP(params, key, f.shorthand, false, f.usage)
+}
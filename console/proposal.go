@@ -0,0 +1,381 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/admin"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func adminProposal() *cobra.Command {
+
+	proposalCmd := &cobra.Command{
+		Use:   "proposal",
+		Short: "ChainMaker DID governance proposal command",
+		Long:  "ChainMaker DID governance proposal command",
+	}
+
+	proposalCmd.AddCommand(proposalCreate())
+	proposalCmd.AddCommand(proposalApprove())
+	proposalCmd.AddCommand(proposalCancel())
+	proposalCmd.AddCommand(proposalGet())
+	proposalCmd.AddCommand(proposalList())
+	proposalCmd.AddCommand(proposalQuorum())
+	return proposalCmd
+}
+
+// parseActionParams 解析提案参数，非字符串的值（如DID列表）转换为JSON字符串
+func parseActionParams(actionParams string) (map[string]string, error) {
+	params := make(map[string]string)
+
+	if len(actionParams) == 0 {
+		return params, nil
+	}
+
+	var raw map[string]interface{}
+	err := json.Unmarshal([]byte(actionParams), &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range raw {
+		if str, ok := v.(string); ok {
+			params[k] = str
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		params[k] = string(b)
+	}
+
+	return params, nil
+}
+
+func proposalCreate() *cobra.Command {
+	var action, actionParams, expiration, sdkPath string
+
+	proposalCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create governance proposal",
+		Long: strings.TrimSpace(
+			`Create a governance proposal on blockchain, only admin can do it.
+Supported actions: AddBlackList, DeleteBlackList, AddTrustIssuer, DeleteTrustIssuer,
+SetAdmin, DeleteAdmin, SetVcTemplate, GrantRole, RevokeRole, SetProposalQuorum, SetContractConfig.
+Example:
+$ ./console admin proposal create \
+--action=AddBlackList \
+--action-params='{"dids":["did:cm:test1"],"reason":"fraud"}' \
+--expiration=2025-01-25 \
+--sdk-path=./testdata/sdk_config.yml
+
+The keys of --action-params are the same as the parameters of the contract method.
+If --expiration is not specified, the proposal expires after 7 days.
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			var expireTime int64
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(action) == 0 {
+				return ParamsEmptyError(ParamsFlagAction)
+			}
+
+			params, err := parseActionParams(actionParams)
+			if err != nil {
+				return err
+			}
+
+			if len(expiration) != 0 {
+				t, err := time.ParseInLocation("2006-01-02", expiration, time.Local)
+				if err != nil {
+					return err
+				}
+
+				expireTime = t.Unix()
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			id, receipt, err := admin.CreateProposalForDidContract(action, params, expireTime, c)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("proposal id: [%s]\n", id)
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(proposalCreateCmd, ParamsFlagAction, &action)
+	attachFlagString(proposalCreateCmd, ParamsFlagActionParams, &actionParams)
+	attachFlagString(proposalCreateCmd, ParamsFlagExpiration, &expiration)
+	attachFlagString(proposalCreateCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return proposalCreateCmd
+}
+
+func proposalApprove() *cobra.Command {
+	var id, sdkPath string
+
+	proposalApproveCmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Approve governance proposal",
+		Long: strings.TrimSpace(
+			`Approve a governance proposal on blockchain, only admin can do it.
+The proposal is executed once the quorum is reached.
+Example:
+$ ./console admin proposal approve \
+--id=17d0a3c5e2b4f1a8c9d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8 \
+--sdk-path=./testdata/sdk_config2.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(id) == 0 {
+				return ParamsEmptyError(ParamsFlagId)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := admin.ApproveProposalForDidContract(id, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(proposalApproveCmd, ParamsFlagId, &id)
+	attachFlagString(proposalApproveCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return proposalApproveCmd
+}
+
+func proposalCancel() *cobra.Command {
+	var id, sdkPath string
+
+	proposalCancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel governance proposal",
+		Long: strings.TrimSpace(
+			`Cancel a pending governance proposal on blockchain, only the proposer can do it.
+Example:
+$ ./console admin proposal cancel \
+--id=17d0a3c5e2b4f1a8c9d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(id) == 0 {
+				return ParamsEmptyError(ParamsFlagId)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := admin.CancelProposalForDidContract(id, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(proposalCancelCmd, ParamsFlagId, &id)
+	attachFlagString(proposalCancelCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return proposalCancelCmd
+}
+
+func proposalGet() *cobra.Command {
+	var id, sdkPath string
+
+	proposalGetCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get governance proposal",
+		Long: strings.TrimSpace(
+			`Get a governance proposal from blockchain.
+Example:
+$ ./console admin proposal get \
+--id=17d0a3c5e2b4f1a8c9d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(id) == 0 {
+				return ParamsEmptyError(ParamsFlagId)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			proposal, err := admin.GetProposalOfDidContract(id, c)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%+v\n", proposal)
+
+			return nil
+		},
+	}
+
+	attachFlagString(proposalGetCmd, ParamsFlagId, &id)
+	attachFlagString(proposalGetCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return proposalGetCmd
+}
+
+func proposalList() *cobra.Command {
+	var start, count int
+	var status, sdkPath string
+
+	proposalListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Get governance proposal list",
+		Long: strings.TrimSpace(
+			`Get the governance proposal list from blockchain.
+Example:
+$ ./console admin proposal list \
+--status=pending \
+--start=1 \
+--count=10 \
+--sdk-path=./testdata/sdk_config.yml
+
+If --status is not specified, all proposals are listed.
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			list, err := admin.GetProposalListOfDidContract(status, start, count, c)
+			if err != nil {
+				return err
+			}
+
+			for _, v := range list {
+				fmt.Printf("%+v\n", v)
+			}
+
+			return nil
+		},
+	}
+
+	attachFlagString(proposalListCmd, ParamsFlagStatus, &status)
+	attachFlagString(proposalListCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagInt(proposalListCmd, ParamsFlagListStart, &start)
+	attachFlagInt(proposalListCmd, ParamsFlagListCount, &count)
+
+	return proposalListCmd
+}
+
+func proposalQuorum() *cobra.Command {
+	var quorum int
+	var sdkPath string
+
+	proposalQuorumCmd := &cobra.Command{
+		Use:   "quorum",
+		Short: "Get or set the quorum of governance proposal",
+		Long: strings.TrimSpace(
+			`Get or set the quorum of governance proposal, only the creator of contract can set it.
+If the quorum is greater than 1, the governance operations must be executed through proposals.
+Example:
+$ ./console admin proposal quorum \
+--quorum=2 \
+--sdk-path=./testdata/sdk_config.yml
+
+If --quorum is not specified, the current quorum is printed.
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			if quorum == 0 {
+				num, err := admin.GetProposalQuorumOfDidContract(c)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("quorum: [%d]\n", num)
+				return nil
+			}
+
+			receipt, err := admin.SetProposalQuorumForDidContract(quorum, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagInt(proposalQuorumCmd, ParamsFlagQuorum, &quorum)
+	attachFlagString(proposalQuorumCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return proposalQuorumCmd
+}
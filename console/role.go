@@ -0,0 +1,216 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/admin"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func adminRole() *cobra.Command {
+
+	roleCmd := &cobra.Command{
+		Use:   "role",
+		Short: "ChainMaker DID role command",
+		Long: strings.TrimSpace(
+			`ChainMaker DID role command.
+Supported roles: template-manager, blacklist-manager, issuer-manager, did-operator, vc-manager.
+`,
+		),
+	}
+
+	roleCmd.AddCommand(roleGrant())
+	roleCmd.AddCommand(roleRevoke())
+	roleCmd.AddCommand(roleList())
+	return roleCmd
+}
+
+// getRoleMember 获取角色成员，优先使用DID，否则将公钥转换为SKI
+func getRoleMember(didStr, pkPath string) (string, error) {
+	if len(didStr) != 0 {
+		return didStr, nil
+	}
+
+	if len(pkPath) == 0 {
+		return "", fmt.Errorf("the parameter [%s] or [%s] cannot be null", ParamsFlagDid, ParamsFlagPkPath)
+	}
+
+	pkPem, err := os.ReadFile(pkPath)
+	if err != nil {
+		return "", err
+	}
+
+	return admin.PubKeyPemToSki(pkPem)
+}
+
+func roleGrant() *cobra.Command {
+	var role, didStr, pkPath, sdkPath string
+
+	roleGrantCmd := &cobra.Command{
+		Use:   "grant",
+		Short: "Grant role",
+		Long: strings.TrimSpace(
+			`Grant the role of did contract to a DID or public key, only admin can do it.
+Example:
+$ ./console admin role grant \
+--role=blacklist-manager \
+--did=did:cm:test1 \
+--sdk-path=./testdata/sdk_config.yml
+
+$ ./console admin role grant \
+--role=template-manager \
+--pk-path=./testdata/pk.pem \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(role) == 0 {
+				return ParamsEmptyError(ParamsFlagRole)
+			}
+
+			member, err := getRoleMember(didStr, pkPath)
+			if err != nil {
+				return err
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := admin.GrantRoleForDidContract(role, member, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(roleGrantCmd, ParamsFlagRole, &role)
+	attachFlagString(roleGrantCmd, ParamsFlagDid, &didStr)
+	attachFlagString(roleGrantCmd, ParamsFlagPkPath, &pkPath)
+	attachFlagString(roleGrantCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return roleGrantCmd
+}
+
+func roleRevoke() *cobra.Command {
+	var role, didStr, pkPath, sdkPath string
+
+	roleRevokeCmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke role",
+		Long: strings.TrimSpace(
+			`Revoke the role of did contract from a DID or public key, only admin can do it.
+Example:
+$ ./console admin role revoke \
+--role=blacklist-manager \
+--did=did:cm:test1 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			if len(role) == 0 {
+				return ParamsEmptyError(ParamsFlagRole)
+			}
+
+			member, err := getRoleMember(didStr, pkPath)
+			if err != nil {
+				return err
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := admin.RevokeRoleForDidContract(role, member, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(roleRevokeCmd, ParamsFlagRole, &role)
+	attachFlagString(roleRevokeCmd, ParamsFlagDid, &didStr)
+	attachFlagString(roleRevokeCmd, ParamsFlagPkPath, &pkPath)
+	attachFlagString(roleRevokeCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return roleRevokeCmd
+}
+
+func roleList() *cobra.Command {
+	var start, count int
+	var role, sdkPath string
+
+	roleListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Get role member list",
+		Long: strings.TrimSpace(
+			`Get the member list of role from blockchain.
+Example:
+$ ./console admin role list \
+--role=blacklist-manager \
+--start=1 \
+--count=10 \
+--sdk-path=./testdata/sdk_config.yml
+
+If --role is not specified, the members of all roles are listed.
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			list, err := admin.GetRoleListOfDidContract(role, start, count, c)
+			if err != nil {
+				return err
+			}
+
+			for _, v := range list {
+				fmt.Printf("%+v\n", v)
+			}
+
+			return nil
+		},
+	}
+
+	attachFlagString(roleListCmd, ParamsFlagRole, &role)
+	attachFlagString(roleListCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagInt(roleListCmd, ParamsFlagListStart, &start)
+	attachFlagInt(roleListCmd, ParamsFlagListCount, &count)
+
+	return roleListCmd
+}
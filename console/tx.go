@@ -0,0 +1,81 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"did-sdk/invoke"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func TxCMD() *cobra.Command {
+
+	txCmd := &cobra.Command{
+		Use:   "tx",
+		Short: "ChainMaker DID tx command",
+		Long:  "ChainMaker DID tx command",
+	}
+
+	txCmd.AddCommand(txReceiptCmd())
+
+	return txCmd
+}
+
+func txReceiptCmd() *cobra.Command {
+	var txId, sdkPath string
+
+	txReceiptCmd := &cobra.Command{
+		Use:   "receipt",
+		Short: "Get the receipt of transaction",
+		Long: strings.TrimSpace(
+			`Get the receipt of transaction on blockchain, including block height, block hash, contract result and events.
+Example:
+$ ./console tx receipt \
+--tx-id=17a8c6f1e2d3b4a5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9 \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(txId) == 0 {
+				return ParamsEmptyError(ParamsFlagTxId)
+			}
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := invoke.GetTxReceipt(txId, c)
+			if err != nil {
+				return err
+			}
+
+			data, err := json.MarshalIndent(receipt, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(data))
+
+			return nil
+		},
+	}
+
+	attachFlagString(txReceiptCmd, ParamsFlagTxId, &txId)
+	attachFlagString(txReceiptCmd, ParamsFlagCMSdkPath, &sdkPath)
+
+	return txReceiptCmd
+}
@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"context"
 	"did-sdk/vc"
 	"encoding/json"
 	"fmt"
@@ -16,7 +17,6 @@ import (
 	"time"
 
 	"chainmaker.org/chainmaker/did-contract/model"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +31,7 @@ func VcCMD() *cobra.Command {
 	vcCmd.AddCommand(vcIssueCmd())
 	vcCmd.AddCommand(vcIssueLocalCmd())
 	vcCmd.AddCommand(vcVerifyCmd())
+	vcCmd.AddCommand(vcVerifyLocalCmd())
 	vcCmd.AddCommand(vcLogCmd())
 	return vcCmd
 }
@@ -101,7 +102,7 @@ $ ./console vc issue \
 				timeUnix = time.Now().Add(time.Hour * 24 * 365).Unix()
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -128,7 +129,7 @@ $ ./console vc issue \
 				return err
 			}
 
-			vcBytes, err := vc.IssueVC(skPem, pkPem, keyIndex, sub, c, id, timeUnix, tid, vcType...)
+			vcBytes, receipt, err := vc.IssueVC(skPem, pkPem, keyIndex, sub, c, id, timeUnix, tid, vcType...)
 			if err != nil {
 				return err
 			}
@@ -138,9 +139,7 @@ $ ./console vc issue \
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
@@ -284,6 +283,7 @@ $ ./console vc issue-local \
 
 func vcVerifyCmd() *cobra.Command {
 	var sdkPath, vcPath string
+	var report bool
 
 	vcVerifyCmd := &cobra.Command{
 		Use:   "verify",
@@ -294,6 +294,12 @@ Example:
 $ ./console vc verify \
 --vc-path=./testdata/vc.json \
 --sdk-path=./testdata/sdk_config.yml
+
+Print the result of each check:
+$ ./console vc verify \
+--vc-path=./testdata/vc.json \
+--sdk-path=./testdata/sdk_config.yml \
+--report
 `,
 		),
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -305,7 +311,7 @@ $ ./console vc verify \
 				return ParamsEmptyError(ParamsFlagVcPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -315,6 +321,17 @@ $ ./console vc verify \
 				return err
 			}
 
+			if report {
+				r, err := vc.VerifyVCReportOnChain(string(vcJson), c)
+				if err != nil {
+					return err
+				}
+
+				fmt.Print(r.String())
+
+				return nil
+			}
+
 			ok, err := vc.VerifyVCOnChain(string(vcJson), c)
 			if err != nil {
 				return err
@@ -328,10 +345,85 @@ $ ./console vc verify \
 
 	attachFlagString(vcVerifyCmd, ParamsFlagCMSdkPath, &sdkPath)
 	attachFlagString(vcVerifyCmd, ParamsFlagVcPath, &vcPath)
+	attachFlagBool(vcVerifyCmd, ParamsFlagReport, &report)
 
 	return vcVerifyCmd
 }
 
+func vcVerifyLocalCmd() *cobra.Command {
+	var sdkPath, vcPath, snapshotPath string
+
+	vcVerifyLocalCmd := &cobra.Command{
+		Use:   "verify-local",
+		Short: "Verify the vc at local",
+		Long: strings.TrimSpace(
+			`Verify the vc at local with the snapshot of trust data, without connecting to the blockchain.
+Example:
+$ ./console vc verify-local \
+--vc-path=./testdata/vc.json \
+--snapshot-path=./testdata/snapshot.json
+
+Query the trust data on blockchain and verify the vc at local:
+$ ./console vc verify-local \
+--vc-path=./testdata/vc.json \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(vcPath) == 0 {
+				return ParamsEmptyError(ParamsFlagVcPath)
+			}
+
+			opts, err := localVerifyOptions(snapshotPath, sdkPath)
+			if err != nil {
+				return err
+			}
+
+			vcJson, err := os.ReadFile(vcPath)
+			if err != nil {
+				return err
+			}
+
+			r, err := vc.VerifyVCLocal(context.Background(), string(vcJson), opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(r.String())
+
+			return nil
+		},
+	}
+
+	attachFlagString(vcVerifyLocalCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagString(vcVerifyLocalCmd, ParamsFlagVcPath, &vcPath)
+	attachFlagString(vcVerifyLocalCmd, ParamsFlagSnapshotPath, &snapshotPath)
+
+	return vcVerifyLocalCmd
+}
+
+// localVerifyOptions 使用快照文件或链上查询作为本地验证的数据源，优先使用快照文件
+func localVerifyOptions(snapshotPath, sdkPath string) (vc.LocalVerifyOptions, error) {
+	if len(snapshotPath) != 0 {
+		s, err := vc.LoadSnapshot(snapshotPath)
+		if err != nil {
+			return vc.LocalVerifyOptions{}, err
+		}
+		return s.Options(), nil
+	}
+
+	if len(sdkPath) == 0 {
+		return vc.LocalVerifyOptions{}, ParamsEmptyError(ParamsFlagSnapshotPath)
+	}
+
+	c, err := newChainClient(sdkPath)
+	if err != nil {
+		return vc.LocalVerifyOptions{}, err
+	}
+
+	return vc.NewChainSource(c).Options(), nil
+}
+
 func vcLogCmd() *cobra.Command {
 	var start, count int
 	var search, sdkPath string
@@ -355,7 +447,7 @@ $ ./console vc log \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
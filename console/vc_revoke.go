@@ -9,10 +9,10 @@ package main
 
 import (
 	"did-sdk/vc"
+	"encoding/json"
 	"fmt"
 	"strings"
 
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -26,22 +26,30 @@ func VcRevokeCMD() *cobra.Command {
 
 	vcRevokeCmd.AddCommand(vcRevokeAddCmd())
 	vcRevokeCmd.AddCommand(vcRevokeList())
+	vcRevokeCmd.AddCommand(vcSuspendCmd())
+	vcRevokeCmd.AddCommand(vcResumeCmd())
 
 	return vcRevokeCmd
 }
 
 func vcRevokeAddCmd() *cobra.Command {
 
-	var idStr, sdkPath string
+	var idStr, issuer, reason, sdkPath string
+	var reasonCode int
 
 	vcRevokeAddCmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add vc revoke list",
 		Long: strings.TrimSpace(
-			`Add vc revoke list to blockchain.
+			`Add vc revoke list to blockchain, the revocation is recorded under the issuer of the vc.
+The issuer can be omitted when revoking the vc issued by yourself.
+Reason codes: 0:unspecified,1:key compromise,2:superseded,3:cessation,4:fraud
 Example:
 $ ./console vc-revoke add \
 --id=16516616 \
+--issuer=did:cm:admin \
+--reason-code=1 \
+--reason="key leaked" \
 --sdk-path=./testdata/sdk_config.yml
 `,
 		),
@@ -55,24 +63,25 @@ $ ./console vc-revoke add \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
 
-			err = vc.RevokeVCOnChain(idStr, c)
+			receipt, err := vc.RevokeVCWithReasonOnChain(issuer, idStr, reasonCode, reason, c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
 	attachFlagString(vcRevokeAddCmd, ParamsFlagCMSdkPath, &sdkPath)
 	attachFlagString(vcRevokeAddCmd, ParamsFlagId, &idStr)
+	attachFlagString(vcRevokeAddCmd, ParamsFlagIssuer, &issuer)
+	attachFlagString(vcRevokeAddCmd, ParamsFlagReason, &reason)
+	attachFlagInt(vcRevokeAddCmd, ParamsFlagReasonCode, &reasonCode)
 
 	return vcRevokeAddCmd
 }
@@ -101,7 +110,7 @@ $ ./console vc-revoke list \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -111,7 +120,12 @@ $ ./console vc-revoke list \
 				return err
 			}
 
-			fmt.Printf("get the vc revoke list: [%+v]\n", list)
+			listJson, err := json.Marshal(list)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("get the vc revoke list: [%s]\n", listJson)
 
 			return nil
 		},
@@ -124,3 +138,101 @@ $ ./console vc-revoke list \
 
 	return vcRevokeListCmd
 }
+
+func vcSuspendCmd() *cobra.Command {
+
+	var idStr, issuer, reason, sdkPath string
+
+	vcSuspendCmd := &cobra.Command{
+		Use:   "suspend",
+		Short: "Suspend vc",
+		Long: strings.TrimSpace(
+			`Suspend the vc on blockchain, the vc is invalid until it is resumed.
+The issuer can be omitted when suspending the vc issued by yourself.
+Example:
+$ ./console vc-revoke suspend \
+--id=16516616 \
+--reason="under investigation" \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(idStr) == 0 {
+				return ParamsEmptyError(ParamsFlagId)
+			}
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := vc.SuspendVCOnChain(issuer, idStr, reason, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(vcSuspendCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagString(vcSuspendCmd, ParamsFlagId, &idStr)
+	attachFlagString(vcSuspendCmd, ParamsFlagIssuer, &issuer)
+	attachFlagString(vcSuspendCmd, ParamsFlagReason, &reason)
+
+	return vcSuspendCmd
+}
+
+func vcResumeCmd() *cobra.Command {
+
+	var idStr, issuer, reason, sdkPath string
+
+	vcResumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume suspended vc",
+		Long: strings.TrimSpace(
+			`Resume the suspended vc on blockchain.
+The issuer can be omitted when resuming the vc issued by yourself.
+Example:
+$ ./console vc-revoke resume \
+--id=16516616 \
+--reason="investigation closed" \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(idStr) == 0 {
+				return ParamsEmptyError(ParamsFlagId)
+			}
+
+			if len(sdkPath) == 0 {
+				return ParamsEmptyError(ParamsFlagCMSdkPath)
+			}
+
+			c, err := newChainClient(sdkPath)
+			if err != nil {
+				return err
+			}
+
+			receipt, err := vc.UnsuspendVCOnChain(issuer, idStr, reason, c)
+			if err != nil {
+				return err
+			}
+
+			return printTxReceipt(receipt)
+		},
+	}
+
+	attachFlagString(vcResumeCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagString(vcResumeCmd, ParamsFlagId, &idStr)
+	attachFlagString(vcResumeCmd, ParamsFlagIssuer, &issuer)
+	attachFlagString(vcResumeCmd, ParamsFlagReason, &reason)
+
+	return vcResumeCmd
+}
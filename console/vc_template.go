@@ -14,7 +14,6 @@ import (
 	"os"
 	"strings"
 
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -76,7 +75,7 @@ $ ./console vc-template add \
 				return ParamsEmptyError(ParamsFlagTemplatePath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -86,14 +85,12 @@ $ ./console vc-template add \
 				return err
 			}
 
-			err = vc.AddVcTemplateToChain(tid, tname, tversion, temp, c)
+			receipt, err := vc.AddVcTemplateToChain(tid, tname, tversion, temp, c)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(ConsoleOutputSuccessfulOperation)
-
-			return nil
+			return printTxReceipt(receipt)
 		},
 	}
 
@@ -130,7 +127,7 @@ $ ./console vc-template list \
 				return ParamsEmptyError(ParamsFlagCMSdkPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -185,7 +182,7 @@ $ ./console vc-template get \
 				return ParamsEmptyError(ParamsFlagTemplatePath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
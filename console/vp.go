@@ -8,12 +8,13 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"context"
 	"did-sdk/vp"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -27,12 +28,13 @@ func VpCMD() *cobra.Command {
 
 	vpCmd.AddCommand(vpGenCmd())
 	vpCmd.AddCommand(vpVerifyCmd())
+	vpCmd.AddCommand(vpVerifyLocalCmd())
 
 	return vpCmd
 }
 
 func vpGenCmd() *cobra.Command {
-	var skPath, id, holder, vpPath string
+	var skPath, id, holder, vpPath, challenge, domain, expiration string
 	var keyIndex int
 	var vpType, vcListPath []string
 
@@ -49,6 +51,17 @@ $ ./console vp gen \
 --vc-list=./testdata/vc.json \
 --type=Identity \
 --vp-path=./testdata/vp.json
+
+Bind the challenge and domain provided by the verifier:
+$ ./console vp gen \
+--sk-path=./testdata/sk.pem \
+--holder=did:cm:admin \
+--id=vp001 \
+--vc-list=./testdata/vc.json \
+--challenge=4a2f8c \
+--domain=verifier.example.com \
+--expiration=2025-01-25 \
+--vp-path=./testdata/vp.json
 `,
 		),
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -85,7 +98,20 @@ $ ./console vp gen \
 				vcList = append(vcList, string(vc))
 			}
 
-			vp, err := vp.GenerateVP(skPem, keyIndex, holder, id, vcList, vpType...)
+			opts := vp.VpOptions{
+				Challenge: challenge,
+				Domain:    domain,
+			}
+
+			if len(expiration) != 0 {
+				t, err := time.ParseInLocation("2006-01-02", expiration, time.Local)
+				if err != nil {
+					return err
+				}
+				opts.Expiration = t.Unix()
+			}
+
+			vp, err := vp.GenerateVPWithOptions(skPem, keyIndex, holder, id, vcList, opts, vpType...)
 			if err != nil {
 				return err
 			}
@@ -105,6 +131,9 @@ $ ./console vp gen \
 	attachFlagString(vpGenCmd, ParamsFlagId, &id)
 	attachFlagString(vpGenCmd, ParamsFlagHolder, &holder)
 	attachFlagString(vpGenCmd, ParamsFlagVpPath, &vpPath)
+	attachFlagString(vpGenCmd, ParamsFlagChallenge, &challenge)
+	attachFlagString(vpGenCmd, ParamsFlagDomain, &domain)
+	attachFlagString(vpGenCmd, ParamsFlagExpiration, &expiration)
 
 	attachFlagStringSlice(vpGenCmd, ParamsFlagType, &vpType)
 	attachFlagStringSlice(vpGenCmd, ParamsFlagVcList, &vcListPath)
@@ -116,6 +145,7 @@ $ ./console vp gen \
 
 func vpVerifyCmd() *cobra.Command {
 	var sdkPath, vpPath string
+	var report bool
 
 	vpVerifyCmd := &cobra.Command{
 		Use:   "verify",
@@ -126,6 +156,12 @@ Example:
 $ ./console vp verify \
 --vp-path=./testdata/vp.json \
 --sdk-path=./testdata/sdk_config.yml
+
+Print the result of each check:
+$ ./console vp verify \
+--vp-path=./testdata/vp.json \
+--sdk-path=./testdata/sdk_config.yml \
+--report
 `,
 		),
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -137,7 +173,7 @@ $ ./console vp verify \
 				return ParamsEmptyError(ParamsFlagVpPath)
 			}
 
-			c, err := cmsdk.NewChainClient(cmsdk.WithConfPath(sdkPath))
+			c, err := newChainClient(sdkPath)
 			if err != nil {
 				return err
 			}
@@ -147,6 +183,17 @@ $ ./console vp verify \
 				return err
 			}
 
+			if report {
+				r, err := vp.VerifyVPReportOnChain(string(vpJson), c)
+				if err != nil {
+					return err
+				}
+
+				fmt.Print(r.String())
+
+				return nil
+			}
+
 			ok, err := vp.VerifyVPOnChain(string(vpJson), c)
 			if err != nil {
 				return err
@@ -160,6 +207,63 @@ $ ./console vp verify \
 
 	attachFlagString(vpVerifyCmd, ParamsFlagCMSdkPath, &sdkPath)
 	attachFlagString(vpVerifyCmd, ParamsFlagVpPath, &vpPath)
+	attachFlagBool(vpVerifyCmd, ParamsFlagReport, &report)
 
 	return vpVerifyCmd
 }
+
+func vpVerifyLocalCmd() *cobra.Command {
+	var sdkPath, vpPath, snapshotPath, challenge, domain string
+
+	vpVerifyLocalCmd := &cobra.Command{
+		Use:   "verify-local",
+		Short: "Verify the vp at local",
+		Long: strings.TrimSpace(
+			`Verify the vp at local with the snapshot of trust data, without connecting to the blockchain.
+Example:
+$ ./console vp verify-local \
+--vp-path=./testdata/vp.json \
+--snapshot-path=./testdata/snapshot.json \
+--challenge=4a2f8c \
+--domain=verifier.example.com
+
+Query the trust data on blockchain and verify the vp at local:
+$ ./console vp verify-local \
+--vp-path=./testdata/vp.json \
+--sdk-path=./testdata/sdk_config.yml
+`,
+		),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(vpPath) == 0 {
+				return ParamsEmptyError(ParamsFlagVpPath)
+			}
+
+			opts, err := localVerifyOptions(snapshotPath, sdkPath)
+			if err != nil {
+				return err
+			}
+
+			vpJson, err := os.ReadFile(vpPath)
+			if err != nil {
+				return err
+			}
+
+			r, err := vp.VerifyVPLocal(context.Background(), string(vpJson), challenge, domain, opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(r.String())
+
+			return nil
+		},
+	}
+
+	attachFlagString(vpVerifyLocalCmd, ParamsFlagCMSdkPath, &sdkPath)
+	attachFlagString(vpVerifyLocalCmd, ParamsFlagVpPath, &vpPath)
+	attachFlagString(vpVerifyLocalCmd, ParamsFlagSnapshotPath, &snapshotPath)
+	attachFlagString(vpVerifyLocalCmd, ParamsFlagChallenge, &challenge)
+	attachFlagString(vpVerifyLocalCmd, ParamsFlagDomain, &domain)
+
+	return vpVerifyLocalCmd
+}
@@ -0,0 +1,386 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// SetAdmin 设置管理员
+// @params ski 与GetSenderPk()保持一致，采用公钥ski的形式
+func (d *DidContract) SetAdmin(ski string) error {
+	err := d.requireAdminManager()
+	if err != nil {
+		return err
+	}
+
+	return d.setAdmin(ski)
+}
+
+func (d *DidContract) setAdmin(ski string) error {
+	_, err := hex.DecodeString(ski)
+	if err != nil || len(ski) == 0 {
+		return model.NewError(model.ErrCode_InvalidParameter, "the ski of admin must be hex encoded")
+	}
+
+	// 公钥没有对应的DID时记录为空
+	did, _ := d.dal.getDidBySki(ski)
+
+	return d.putAdmin(ski, did, false)
+}
+
+// SetAdminByDid 以DID设置管理员，DID Document中所有验证方法的公钥都将成为管理员
+// DID Document更新公钥后管理员公钥随之同步
+// @params did 管理员的DID
+func (d *DidContract) SetAdminByDid(did string) error {
+	err := d.requireAdminManager()
+	if err != nil {
+		return err
+	}
+
+	return d.setAdminByDid(did)
+}
+
+func (d *DidContract) setAdminByDid(did string) error {
+	skis, err := d.getDidSkiList(did)
+	if err != nil {
+		return err
+	}
+
+	for _, ski := range skis {
+		err = d.putAdmin(ski, did, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAdminSkisByDid 获取以DID注册的管理员公钥SKI，不是以DID注册的管理员时为空
+func (d *DidContract) getAdminSkisByDid(did string) ([]string, error) {
+	admins, err := d.dal.getAdminList()
+	if err != nil {
+		return nil, err
+	}
+
+	var skis []string
+	for _, admin := range admins {
+		if admin.ByDid && admin.Did == did {
+			skis = append(skis, admin.Ski)
+		}
+	}
+
+	return skis, nil
+}
+
+// syncAdminByDid DID Document更新后同步以DID注册的管理员公钥
+// 已移除的公钥不再是管理员；新增的验证方法公钥在未开启治理时成为管理员，
+// 开启治理后需要通过SetAdmin提案重新以DID设置管理员
+// @params did 更新的DID
+// @params pubKeys 新DID Document中验证方法的公钥
+func (d *DidContract) syncAdminByDid(did string, pubKeys []string) error {
+	oldSkis, err := d.getAdminSkisByDid(did)
+	if err != nil {
+		return err
+	}
+
+	// 不是以DID注册的管理员时不做处理
+	if len(oldSkis) == 0 {
+		return nil
+	}
+
+	skis := make([]string, 0, len(pubKeys))
+	for _, pk := range pubKeys {
+		ski, err := model.PubKeyPemToSki(pk)
+		if err != nil {
+			return err
+		}
+		skis = append(skis, ski)
+	}
+
+	// 先添加新公钥再删除旧公钥，保证管理员身份数量的检查不受轮换影响
+	if !d.isGovernanceEnabled() {
+		for _, ski := range skis {
+			if isInList(ski, oldSkis) {
+				continue
+			}
+
+			err = d.putAdmin(ski, did, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, ski := range oldSkis {
+		if isInList(ski, skis) {
+			continue
+		}
+
+		err = d.deleteAdmin(ski)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetAdmin 删除管理员
+// @params ski 与GetSenderPk()保持一致，采用公钥ski的形式
+func (d *DidContract) DeleteAdmin(ski string) error {
+	err := d.requireAdminManager()
+	if err != nil {
+		return err
+	}
+
+	return d.deleteAdmin(ski)
+}
+
+func (d *DidContract) deleteAdmin(ski string) error {
+	record, err := d.dal.getAdmin(ski)
+	if err != nil {
+		return err
+	}
+
+	// 不是管理员时不做处理
+	if record == nil {
+		return nil
+	}
+
+	// 管理员身份数量不能少于法定人数，否则提案无法通过，治理将无法继续
+	quorum, err := d.dal.getProposalQuorum()
+	if err != nil {
+		return err
+	}
+
+	admins, err := d.countAdminIdentityExcept(ski)
+	if err != nil {
+		return err
+	}
+
+	if admins < quorum {
+		return model.NewError(model.ErrCode_InvalidParameter,
+			"the number of admins can not be less than the quorum, quorum: [%d]", quorum)
+	}
+
+	err = d.dal.deleteAdmin(ski)
+	if err != nil {
+		return err
+	}
+
+	emitDeleteAdminEvent(record.Ski, record.Did)
+	return nil
+}
+
+// DeleteAdminByDid 删除DID对应的所有管理员公钥
+// @params did 管理员的DID
+func (d *DidContract) DeleteAdminByDid(did string) error {
+	err := d.requireAdminManager()
+	if err != nil {
+		return err
+	}
+
+	return d.deleteAdminByDid(did)
+}
+
+func (d *DidContract) deleteAdminByDid(did string) error {
+	admins, err := d.dal.getAdminList()
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, admin := range admins {
+		if admin.Did != did {
+			continue
+		}
+
+		found = true
+		err = d.deleteAdmin(admin.Ski)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return model.NewError(model.ErrCode_InvalidParameter, "the did is not an admin, did: [%s]", did)
+	}
+
+	return nil
+}
+
+// IsAdmin 判断是否是管理员
+// @params ski 与GetSenderPk()保持一致，采用公钥ski的形式
+func (d *DidContract) IsAdmin(ski string) bool {
+	return d.dal.isAdmin(ski)
+}
+
+// GetAdminList 获取管理员列表，第一个为合约创建者
+func (d *DidContract) GetAdminList() ([]*model.AdminRecord, error) {
+	creatorPk, err := sdk.Instance.GetCreatorPk()
+	if err != nil {
+		return nil, err
+	}
+
+	admins, err := d.dal.getAdminList()
+	if err != nil {
+		return nil, err
+	}
+
+	creatorDid, _ := d.dal.getDidBySki(creatorPk)
+
+	list := []*model.AdminRecord{{
+		Ski:       creatorPk,
+		Did:       creatorDid,
+		IsCreator: true,
+	}}
+
+	for _, admin := range admins {
+		if admin.Ski == creatorPk {
+			continue
+		}
+
+		// 添加管理员时公钥还没有对应的DID，查询时再尝试获取
+		if len(admin.Did) == 0 {
+			admin.Did, _ = d.dal.getDidBySki(admin.Ski)
+		}
+
+		list = append(list, admin)
+	}
+
+	return list, nil
+}
+
+// requireAdminManager 只有合约创建者可以直接设置管理员，启用提案治理后需要通过提案执行
+func (d *DidContract) requireAdminManager() error {
+	// 必须是合约创建者才能操作
+	ok, err := isSenderCreator()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return model.NewError(model.ErrCode_PermissionDenied, "only the creator of the contract has permission")
+	}
+
+	return d.requireNoGovernance()
+}
+
+func (d *DidContract) putAdmin(ski, did string, byDid bool) error {
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	recordBytes, err := json.Marshal(&model.AdminRecord{
+		Ski:     ski,
+		Did:     did,
+		ByDid:   byDid,
+		AddTime: myTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putAdmin(ski, recordBytes)
+	if err != nil {
+		return err
+	}
+
+	emitSetAdminEvent(ski, did)
+	return nil
+}
+
+// getDidSkiList 获取DID Document中所有验证方法公钥的SKI
+func (d *DidContract) getDidSkiList(did string) ([]string, error) {
+	docBytes, err := d.dal.getDidDocument(did)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docBytes) == 0 {
+		return nil, model.NewError(model.ErrCode_DidNotFound, "the did's doc not found on chain, did: [%s]", did)
+	}
+
+	doc, err := model.NewDIDDocument(string(docBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	_, pubKeys, _ := doc.ParsePubKeyAddress()
+
+	skis := make([]string, 0, len(pubKeys))
+	for _, pk := range pubKeys {
+		ski, err := model.PubKeyPemToSki(pk)
+		if err != nil {
+			return nil, err
+		}
+		skis = append(skis, ski)
+	}
+
+	return skis, nil
+}
+
+// getAdminIdentity 获取管理员的身份标识，以DID注册的管理员的多个公钥视为同一身份
+// @params ski 管理员公钥的SKI
+// @params creatorPk 合约创建者公钥的SKI
+func (d *DidContract) getAdminIdentity(ski, creatorPk string) (string, bool) {
+	if ski == creatorPk {
+		return ski, true
+	}
+
+	record, err := d.dal.getAdmin(ski)
+	if err != nil || record == nil {
+		return "", false
+	}
+
+	if len(record.Did) != 0 {
+		return record.Did, true
+	}
+
+	return ski, true
+}
+
+// countAdminIdentity 统计拥有管理员权限的身份数量（包含合约创建者）
+func (d *DidContract) countAdminIdentity() (int, error) {
+	return d.countAdminIdentityExcept("")
+}
+
+// countAdminIdentityExcept 统计删除指定管理员公钥后拥有管理员权限的身份数量（包含合约创建者）
+// @params ski 要删除的管理员公钥的SKI，为空时不排除
+func (d *DidContract) countAdminIdentityExcept(ski string) (int, error) {
+	creatorPk, err := sdk.Instance.GetCreatorPk()
+	if err != nil {
+		return 0, err
+	}
+
+	admins, err := d.dal.getAdminList()
+	if err != nil {
+		return 0, err
+	}
+
+	identities := map[string]struct{}{creatorPk: {}}
+	for _, admin := range admins {
+		if len(ski) != 0 && admin.Ski == ski {
+			continue
+		}
+
+		identity, ok := d.getAdminIdentity(admin.Ski, creatorPk)
+		if ok {
+			identities[identity] = struct{}{}
+		}
+	}
+
+	return len(identities), nil
+}
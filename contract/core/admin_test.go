@@ -0,0 +1,231 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+// testKey 测试用的DID公私钥
+type testKey struct {
+	priv *ecdsa.PrivateKey
+	pem  string
+	ski  string
+}
+
+// newTestKey 生成一个测试密钥
+func newTestKey(t *testing.T) *testKey {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.Nil(t, err)
+
+	pkPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	ski, err := model.PubKeyPemToSki(pkPem)
+	require.Nil(t, err)
+
+	return &testKey{priv: priv, pem: pkPem, ski: ski}
+}
+
+// newTestDidDocument 构造带有验证方法公钥的DID Document，使用第一个密钥签名
+func newTestDidDocument(t *testing.T, did string, keys ...*testKey) *model.DidDocument {
+	var methods []*model.VerificationMethod
+	for i, key := range keys {
+		addr, err := skiToAddress(key.ski)
+		require.Nil(t, err)
+
+		methods = append(methods, &model.VerificationMethod{
+			Id:           fmt.Sprintf("%s#key-%d", did, i+1),
+			Type:         "EcdsaSecp256r1VerificationKey2019",
+			Controller:   did,
+			PublicKeyPem: key.pem,
+			Address:      addr,
+		})
+	}
+
+	doc := map[string]interface{}{
+		"id":                 did,
+		"verificationMethod": methods,
+		"controller":         []string{did},
+	}
+
+	if len(keys) != 0 {
+		msg, err := json.Marshal(doc)
+		require.Nil(t, err)
+
+		digest := sha256.Sum256(msg)
+		sig, err := ecdsa.SignASN1(rand.Reader, keys[0].priv, digest[:])
+		require.Nil(t, err)
+
+		doc["proof"] = &model.Proof{
+			Type:               model.ECDSAWithSHA256,
+			ProofPurpose:       "assertionMethod",
+			VerificationMethod: methods[0].Id,
+			ProofValue:         base64.StdEncoding.EncodeToString(sig),
+		}
+	}
+
+	docBytes, err := json.Marshal(doc)
+	require.Nil(t, err)
+
+	didDoc, err := model.NewDIDDocument(string(docBytes))
+	require.Nil(t, err)
+
+	return didDoc
+}
+
+// putTestDidDocument 直接写入DID Document及公钥和地址索引，跳过证明验证
+func putTestDidDocument(t *testing.T, d *DidContract, m *mock.SDK, doc *model.DidDocument) {
+	m.BeginTx("", nil)
+	require.Nil(t, d.addDidDocument(doc))
+	m.Commit()
+}
+
+// updateTestDidDocument 直接更新DID Document，跳过权限和证明验证
+func updateTestDidDocument(t *testing.T, d *DidContract, m *mock.SDK, doc, oldDoc *model.DidDocument) error {
+	m.BeginTx("", nil)
+	err := d.updateDidDocument(doc, oldDoc)
+	if err != nil {
+		m.Rollback()
+		return err
+	}
+
+	m.Commit()
+	return nil
+}
+
+func TestAdminByDidFollowsDidDocument(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const adminDid = "did:cm:admin1"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+	k3 := newTestKey(t)
+
+	doc := newTestDidDocument(t, adminDid, k1, k2)
+	putTestDidDocument(t, d, m, doc)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	}))
+	require.True(t, d.IsAdmin(k1.ski))
+	require.True(t, d.IsAdmin(k2.ski))
+
+	// 轮换公钥：移除ski1，新增ski3
+	newDoc := newTestDidDocument(t, adminDid, k2, k3)
+	require.Nil(t, updateTestDidDocument(t, d, m, newDoc, doc))
+
+	require.False(t, d.IsAdmin(k1.ski))
+	require.True(t, d.IsAdmin(k2.ski))
+	require.True(t, d.IsAdmin(k3.ski))
+
+	record, err := d.dal.getAdmin(k3.ski)
+	require.Nil(t, err)
+	require.Equal(t, adminDid, record.Did)
+	require.True(t, record.ByDid)
+
+	// 被移除的公钥失去管理员权限，新公钥获得管理员权限
+	requireFailCode(t, invokeAs(d, m, k1.ski, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}), model.ErrCode_PermissionDenied)
+	requireOK(t, invokeAs(d, m, k3.ski, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}))
+
+	require.Len(t, m.Events(model.Topic_DeleteAdmin), 1)
+}
+
+func TestAdminBySkiNotSyncedWithDidDocument(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const did = "did:cm:user2"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+
+	doc := newTestDidDocument(t, did, k1)
+	putTestDidDocument(t, d, m, doc)
+
+	// 以公钥设置的管理员记录了DID，但不随DID Document同步
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: k1.ski,
+	}))
+
+	record, err := d.dal.getAdmin(k1.ski)
+	require.Nil(t, err)
+	require.Equal(t, did, record.Did)
+	require.False(t, record.ByDid)
+
+	newDoc := newTestDidDocument(t, did, k2)
+	require.Nil(t, updateTestDidDocument(t, d, m, newDoc, doc))
+
+	require.True(t, d.IsAdmin(k1.ski))
+	require.False(t, d.IsAdmin(k2.ski))
+}
+
+func TestAdminByDidRotationKeepsQuorum(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const adminDid = "did:cm:admin1"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+
+	doc := newTestDidDocument(t, adminDid, k1)
+	putTestDidDocument(t, d, m, doc)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "2",
+	}))
+
+	// 开启治理后新公钥不会直接成为管理员，直接轮换会使管理员身份数量少于法定人数
+	newDoc := newTestDidDocument(t, adminDid, k2)
+	err := updateTestDidDocument(t, d, m, newDoc, doc)
+	require.Equal(t, model.ErrCode_InvalidParameter, model.CodeOf(err))
+	require.True(t, d.IsAdmin(k1.ski))
+
+	// 先添加新公钥并通过提案设置为管理员，再移除旧公钥
+	bothDoc := newTestDidDocument(t, adminDid, k1, k2)
+	require.Nil(t, updateTestDidDocument(t, d, m, bothDoc, doc))
+	require.False(t, d.IsAdmin(k2.ski))
+
+	id := createProposal(t, d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	})
+	requireOK(t, approveProposal(d, m, k1.ski, id))
+	require.True(t, d.IsAdmin(k2.ski))
+
+	require.Nil(t, updateTestDidDocument(t, d, m, newDoc, bothDoc))
+	require.False(t, d.IsAdmin(k1.ski))
+	require.True(t, d.IsAdmin(k2.ski))
+
+	// 移除所有公钥会使管理员身份数量少于法定人数，更新失败
+	emptyDoc := newTestDidDocument(t, adminDid)
+	err = updateTestDidDocument(t, d, m, emptyDoc, newDoc)
+	require.Equal(t, model.ErrCode_InvalidParameter, model.CodeOf(err))
+	require.True(t, d.IsAdmin(k2.ski))
+}
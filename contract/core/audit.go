@@ -0,0 +1,69 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// GetAuditLog 获取审计日志列表，按交易顺序排列
+// @params method 合约方法，为空表示不限制
+// @params operator 操作者公钥的SKI或DID，为空表示不限制
+// @params startTime 开始时间，0表示不限制
+// @params endTime 结束时间，0表示不限制
+func (d *DidContract) GetAuditLog(method, operator string, startTime, endTime int64,
+	start int, count int) ([]*model.AuditRecord, error) {
+	return d.dal.searchAuditLog(method, operator, startTime, endTime, start, count)
+}
+
+// appendAuditLog 修改合约状态的方法执行成功后追加一条审计日志
+// @params method 合约方法
+func (d *DidContract) appendAuditLog(method string) error {
+	seq, err := d.dal.getAuditSeq()
+	if err != nil {
+		return err
+	}
+	seq++
+
+	txId, err := sdk.Instance.GetTxId()
+	if err != nil {
+		return err
+	}
+
+	operator, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return err
+	}
+
+	// 操作者没有DID时记录为空
+	operatorDid, _ := d.dal.getDidBySki(operator)
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	digest := model.ComputeParamsDigest(sdk.Instance.GetArgs())
+
+	record := model.NewAuditRecord(seq, txId, method, operator, operatorDid, digest, myTime)
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putAuditRecord(seq, recordBytes)
+	if err != nil {
+		return err
+	}
+
+	return d.dal.putAuditSeq(seq)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}))
+
+	// 执行失败的交易不记录审计日志
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test2",
+	}))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_DeleteBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}))
+
+	// 查询方法不记录审计日志
+	requireOK(t, invokeAs(d, m, testUserSki, model.Method_GetBlackList, nil))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetAuditLog, nil)
+	requireOK(t, resp)
+
+	var records []*model.AuditRecord
+	require.Nil(t, json.Unmarshal(resp.Payload, &records))
+	require.Len(t, records, 2)
+
+	require.Equal(t, uint64(1), records[0].Seq)
+	require.Equal(t, model.Method_AddBlackList, records[0].Method)
+	require.Equal(t, testCreatorSki, records[0].Operator)
+	require.Equal(t, model.Method_DeleteBlackList, records[1].Method)
+	require.Less(t, records[0].TxTime, records[1].TxTime)
+
+	resp = invokeAs(d, m, testUserSki, model.Method_GetAuditLog, map[string]string{
+		model.Params_AuditMethod: model.Method_DeleteBlackList,
+	})
+	requireOK(t, resp)
+	require.Nil(t, json.Unmarshal(resp.Payload, &records))
+	require.Len(t, records, 1)
+}
+
+func TestContractConfig(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_SetContractConfig, map[string]string{
+		model.Params_MaxDocumentSize: "64",
+	}))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetContractConfig, map[string]string{
+		model.Params_MaxDocumentSize: "64",
+		model.Params_DefaultPageSize: "1",
+	}))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetContractConfig, nil)
+	requireOK(t, resp)
+
+	var config model.ContractConfig
+	require.Nil(t, json.Unmarshal(resp.Payload, &config))
+	require.Equal(t, "cm", config.DidMethod)
+	require.Equal(t, 64, config.MaxDocumentSize)
+	require.Equal(t, 1, config.DefaultPageSize)
+
+	// 没有指定查询条数时使用默认分页大小
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_DidList: didListJson(t, "did:cm:test1", "did:cm:test2"),
+	}))
+
+	resp = invokeAs(d, m, testUserSki, model.Method_GetBlackList, nil)
+	requireOK(t, resp)
+
+	var records []*model.BlackListRecord
+	require.Nil(t, json.Unmarshal(resp.Payload, &records))
+	require.Len(t, records, 1)
+
+	// 超过大小限制的DID Document不能上链
+	doc := `{"id":"did:cm:test3","verificationMethod":[{"id":"did:cm:test3#key-1","publicKeyPem":"pem"}]}`
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_AddDidDocument, map[string]string{
+		model.Params_DidDocument: doc,
+	}))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// GetContractConfig 获取合约的运行时配置
+func (d *DidContract) GetContractConfig() (*model.ContractConfig, error) {
+	didMethod, err := d.dal.getDidMethod()
+	if err != nil {
+		return nil, err
+	}
+
+	enableTrustIssuer, err := d.dal.getEnableTrustIssuer()
+	if err != nil {
+		return nil, err
+	}
+
+	maxDocumentSize, err := d.dal.getMaxDocumentSize()
+	if err != nil {
+		return nil, err
+	}
+
+	quorum, err := d.dal.getProposalQuorum()
+	if err != nil {
+		return nil, err
+	}
+
+	schemaVersion, err := d.dal.getSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ContractConfig{
+		DidMethod:         didMethod,
+		EnableTrustIssuer: enableTrustIssuer == "true",
+		MaxDocumentSize:   maxDocumentSize,
+		DefaultPageSize:   d.dal.getDefaultPageSize(),
+		ProposalQuorum:    quorum,
+		SchemaVersion:     schemaVersion,
+	}, nil
+}
+
+// SetContractConfig 修改合约的运行时配置（仅管理员有权限），只修改参数中包含的配置项
+// 启用提案治理后需要通过提案执行
+// @params params 配置项，支持enableTrustIssuer、maxDocumentSize、defaultPageSize
+func (d *DidContract) SetContractConfig(params map[string]string) error {
+	ok, err := isSenderAdmin(d)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	err = d.requireNoGovernance()
+	if err != nil {
+		return err
+	}
+
+	return d.setContractConfig(params)
+}
+
+func (d *DidContract) setContractConfig(params map[string]string) error {
+	if len(params) == 0 {
+		return model.NewError(model.ErrCode_InvalidParameter, "no contract config to set")
+	}
+
+	// 先校验所有配置项，避免部分修改
+	for key, value := range params {
+		switch key {
+		case model.Params_EnableTrustIssuer:
+			_, err := strconv.ParseBool(value)
+			if err != nil {
+				return model.NewError(model.ErrCode_InvalidParameter,
+					"invalid config value, key: [%s], value: [%s]", key, value)
+			}
+		case model.Params_MaxDocumentSize, model.Params_DefaultPageSize:
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 0 {
+				return model.NewError(model.ErrCode_InvalidParameter,
+					"invalid config value, key: [%s], value: [%s]", key, value)
+			}
+		default:
+			return model.NewError(model.ErrCode_InvalidParameter, "unsupported contract config, key: [%s]", key)
+		}
+	}
+
+	for key, value := range params {
+		var err error
+		switch key {
+		case model.Params_EnableTrustIssuer:
+			enable, _ := strconv.ParseBool(value)
+			err = d.dal.putEnableTrustIssuer(strconv.FormatBool(enable))
+		case model.Params_MaxDocumentSize:
+			size, _ := strconv.Atoi(value)
+			err = d.dal.putMaxDocumentSize(size)
+		case model.Params_DefaultPageSize:
+			size, _ := strconv.Atoi(value)
+			err = d.dal.putDefaultPageSize(size)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	config, err := d.GetContractConfig()
+	if err != nil {
+		return err
+	}
+
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	emitSetContractConfigEvent(configBytes)
+	return nil
+}
+
+// checkDocumentSize 校验DID Document的大小是否超过配置的最大值
+// @params didDocument DID Document
+func (d *DidContract) checkDocumentSize(didDocument string) error {
+	maxSize, err := d.dal.getMaxDocumentSize()
+	if err != nil {
+		return err
+	}
+
+	if maxSize > 0 && len(didDocument) > maxSize {
+		return model.NewError(model.ErrCode_InvalidDidDocument,
+			"the size of did document exceeds the limit, size: [%d], limit: [%d]", len(didDocument), maxSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,549 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// DidContract 长安链DID合约
+type DidContract struct {
+	dal *Dal
+}
+
+func (d *DidContract) InitDidContract(didMethod string, enableTrustIssuer bool) error {
+	d.dal = NewDal()
+
+	// 需要存储，防止虚拟机重启内存丢失
+	err := d.dal.putDidMethod(didMethod)
+	if err != nil {
+		return err
+	}
+
+	if enableTrustIssuer {
+		err = d.dal.putEnableTrustIssuer("true")
+		if err != nil {
+			return err
+		}
+	} else {
+		err = d.dal.putEnableTrustIssuer("false")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InitContract install contract func
+func (d *DidContract) InitContract() protogo.Response {
+	method, err := RequireString(model.Params_DidMethod)
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	enableTrustIssuer, err := RequireBool(model.Params_EnableTrustIssuer)
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	err = d.InitDidContract(method, enableTrustIssuer)
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	// 新安装的合约不需要迁移数据
+	err = d.dal.putSchemaVersion(latestSchemaVersion())
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	return sdk.SuccessResponse
+}
+
+// UpgradeContract upgrade contract func
+// 升级时参数可选，未指定的配置项保持不变
+func (d *DidContract) UpgradeContract() protogo.Response {
+	d.dal = NewDal()
+
+	method, err := RequireString(model.Params_DidMethod)
+	if err != nil {
+		method, err = d.dal.getDidMethod()
+		if err != nil || len(method) == 0 {
+			return ReturnError(model.NewError(model.ErrCode_InvalidParameter,
+				"missing required parameters:'%s'", model.Params_DidMethod))
+		}
+	}
+
+	stored, err := d.dal.getEnableTrustIssuer()
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	enableTrustIssuer, err := OptionBool(model.Params_EnableTrustIssuer, stored == "true")
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	err = d.dal.putDidMethod(method)
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	// 配置变化时与SetContractConfig一样发送事件，订阅者据此更新缓存的配置
+	if value := strconv.FormatBool(enableTrustIssuer); value != stored {
+		err = d.setContractConfig(map[string]string{model.Params_EnableTrustIssuer: value})
+		if err != nil {
+			return ReturnError(err)
+		}
+	}
+
+	// 迁移旧版本的数据，数据量较大时未完成的部分需要通过Migrate方法继续执行
+	batch := OptionInt(model.Params_MigrationBatch, defaultMigrationBatch)
+	return ReturnJson(d.runMigrations(batch))
+}
+
+// InvokeContract the entry func of invoke contract func
+func (d *DidContract) InvokeContract(method string) (result protogo.Response) { //nolint
+	// 记录异常结果日志
+	defer func() {
+		if result.Status != 0 {
+			sdk.Instance.Warnf(result.Message)
+		}
+	}()
+
+	// 修改合约状态的方法执行成功后记录审计日志，记录失败时整个交易失败
+	defer func() {
+		if result.Status == 0 && model.IsAuditMethod(method) {
+			err := d.appendAuditLog(method)
+			if err != nil {
+				result = ReturnError(err)
+			}
+		}
+	}()
+
+	// 数据迁移未完成时不能修改合约状态
+	if model.IsAuditMethod(method) && method != model.Method_Migrate && d.isMigrating() {
+		return ReturnError(model.NewError(model.ErrCode_MigrationNotFinished,
+			"the contract data migration is not finished, please call Migrate first"))
+	}
+
+	switch method {
+	case model.Method_DidMethod:
+		return ReturnString(d.DidMethod())
+	case model.Method_IsValidDid:
+		did, err := RequireString(model.Params_Did)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnBool(d.IsValidDid(did))
+	case model.Method_AddDidDocument:
+		didDocument, err := RequireString(model.Params_DidDocument)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.AddDidDocument(didDocument))
+	case model.Method_GetDidDocument:
+		did, err := RequireString(model.Params_Did)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnString(d.GetDidDocument(did))
+	case model.Method_UpdateDidDocument:
+		didDocument, err := RequireString(model.Params_DidDocument)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.UpdateDidDocument(didDocument))
+	case model.Method_GetDidByPubKey:
+		pubKey, err := RequireString(model.Params_DidPubkey)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnString(d.GetDidByPubkey(pubKey))
+	case model.Method_GetDidByAddress:
+		address, err := RequireString(model.Params_DidAddress)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnString(d.GetDidByAddress(address))
+	case model.Method_AddBlackList:
+		dids, err := RequireStringList(model.Params_Did, model.Params_DidList)
+		if err != nil {
+			return ReturnError(err)
+		}
+		args := sdk.Instance.GetArgs()
+		reason := args[model.Params_Reason]
+		reasonCode := OptionInt(model.Params_ReasonCode, model.BlackListReasonUnspecified)
+		expireTime := OptionInt64(model.Params_ExpireTime, 0)
+		return Return(d.AddBlackList(dids, reasonCode, string(reason), expireTime))
+	case model.Method_DeleteBlackList:
+		dids, err := RequireStringList(model.Params_Did, model.Params_DidList)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.DeleteBlackList(dids))
+	case model.Method_GetBlackList:
+		args := sdk.Instance.GetArgs()
+		didSearch := args[model.Params_DidSearch]
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetBlackList(string(didSearch), start, count))
+	case model.Method_RevokeVc:
+		vcId, err := RequireString(model.Params_VcId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		args := sdk.Instance.GetArgs()
+		issuer := args[model.Params_Issuer]
+		reason := args[model.Params_Reason]
+		reasonCode := OptionInt(model.Params_ReasonCode, model.RevocationReasonUnspecified)
+		return Return(d.RevokeVc(string(issuer), vcId, reasonCode, string(reason)))
+	case model.Method_GetVcRevocation:
+		issuer, err := RequireString(model.Params_Issuer)
+		if err != nil {
+			return ReturnError(err)
+		}
+		vcId, err := RequireString(model.Params_VcId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnJson(d.GetVcRevocation(issuer, vcId))
+	case model.Method_SuspendVc:
+		vcId, err := RequireString(model.Params_VcId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		args := sdk.Instance.GetArgs()
+		return Return(d.SuspendVc(string(args[model.Params_Issuer]), vcId, string(args[model.Params_Reason])))
+	case model.Method_UnsuspendVc:
+		vcId, err := RequireString(model.Params_VcId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		args := sdk.Instance.GetArgs()
+		return Return(d.UnsuspendVc(string(args[model.Params_Issuer]), vcId, string(args[model.Params_Reason])))
+	case model.Method_GetVcSuspension:
+		issuer, err := RequireString(model.Params_Issuer)
+		if err != nil {
+			return ReturnError(err)
+		}
+		vcId, err := RequireString(model.Params_VcId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnJson(d.GetVcSuspension(issuer, vcId))
+	case model.Method_GetRevokedVcList:
+		args := sdk.Instance.GetArgs()
+		issuer := args[model.Params_Issuer]
+		vcIdSearch := args[model.Params_VcIdSearch]
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetRevokedVcList(string(issuer), string(vcIdSearch), start, count))
+	case model.Method_SetVcTemplate:
+		templateId, err := RequireString(model.Params_VcTemplateId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		templateName, err := RequireString(model.Params_VcTemplateName)
+		if err != nil {
+			return ReturnError(err)
+		}
+		vcTemplate, err := RequireString(model.Params_VcTemplate)
+		if err != nil {
+			return ReturnError(err)
+		}
+		version, err := RequireString(model.Params_VcTemplateVersion)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.SetVcTemplate(templateId, templateName, version, vcTemplate))
+	case model.Method_GetVcTemplate:
+		templateId, err := RequireString(model.Params_VcTemplateId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnBytes(d.GetVcTemplate(templateId))
+	case model.Method_GetVcTemplateList:
+		args := sdk.Instance.GetArgs()
+		nameSearch := args[model.Params_VcTemplateNameSearch]
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetVcTemplateList(string(nameSearch), start, count))
+	case model.Method_VerifyVc:
+		vcJson, err := RequireString(model.Params_VcJson)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnBool(d.VerifyVc(vcJson))
+	case model.Method_VerifyVp:
+		vpJson, err := RequireString(model.Params_VpJson)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnBool(d.VerifyVp(vpJson))
+	case model.Method_VerifyVcReport:
+		vcJson, err := RequireString(model.Params_VcJson)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnJson(d.VerifyVcReport(vcJson))
+	case model.Method_VerifyVpReport:
+		vpJson, err := RequireString(model.Params_VpJson)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnJson(d.VerifyVpReport(vpJson))
+	case model.Method_SetAdmin:
+		// 可以指定公钥SKI或者DID
+		if did, err := RequireString(model.Params_Did); err == nil {
+			return Return(d.SetAdminByDid(did))
+		}
+		ski, err := RequireString(model.Params_Ski)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.SetAdmin(ski))
+	case model.Method_DeleteAdmin:
+		if did, err := RequireString(model.Params_Did); err == nil {
+			return Return(d.DeleteAdminByDid(did))
+		}
+		ski, err := RequireString(model.Params_Ski)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.DeleteAdmin(ski))
+	case model.Method_IsAdmin:
+		ski, err := RequireString(model.Params_Ski)
+		if err != nil {
+			return ReturnError(err)
+		}
+		ok := d.IsAdmin(ski)
+		return ReturnBool(ok, nil)
+	case model.Method_GetAdminList:
+		return ReturnJson(d.GetAdminList())
+	case model.Method_GrantRole:
+		role, err := RequireString(model.Params_Role)
+		if err != nil {
+			return ReturnError(err)
+		}
+		member, err := RequireString(model.Params_Member)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.GrantRole(role, member))
+	case model.Method_RevokeRole:
+		role, err := RequireString(model.Params_Role)
+		if err != nil {
+			return ReturnError(err)
+		}
+		member, err := RequireString(model.Params_Member)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.RevokeRole(role, member))
+	case model.Method_GetRoleList:
+		args := sdk.Instance.GetArgs()
+		role := args[model.Params_Role]
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetRoleList(string(role), start, count))
+	case model.Method_CreateProposal:
+		action, err := RequireString(model.Params_ProposalAction)
+		if err != nil {
+			return ReturnError(err)
+		}
+		params, err := OptionStringMap(model.Params_ProposalParams)
+		if err != nil {
+			return ReturnError(err)
+		}
+		expireTime := OptionInt64(model.Params_ExpireTime, 0)
+		return ReturnString(d.CreateProposal(action, params, expireTime))
+	case model.Method_ApproveProposal:
+		id, err := RequireString(model.Params_ProposalId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.ApproveProposal(id))
+	case model.Method_CancelProposal:
+		id, err := RequireString(model.Params_ProposalId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.CancelProposal(id))
+	case model.Method_GetProposal:
+		id, err := RequireString(model.Params_ProposalId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnJson(d.GetProposal(id))
+	case model.Method_GetProposalList:
+		args := sdk.Instance.GetArgs()
+		status := args[model.Params_ProposalStatus]
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetProposalList(string(status), start, count))
+	case model.Method_SetProposalQuorum:
+		quorum, err := RequireString(model.Params_Quorum)
+		if err != nil {
+			return ReturnError(err)
+		}
+		num, err := strconv.Atoi(quorum)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.SetProposalQuorum(num))
+	case model.Method_GetProposalQuorum:
+		quorum, err := d.GetProposalQuorum()
+		return ReturnString(strconv.Itoa(quorum), err)
+	case model.Method_GetContractConfig:
+		return ReturnJson(d.GetContractConfig())
+	case model.Method_SetContractConfig:
+		// 只修改参数中包含的配置项
+		args := sdk.Instance.GetArgs()
+		params := make(map[string]string)
+		for _, key := range []string{model.Params_EnableTrustIssuer,
+			model.Params_MaxDocumentSize, model.Params_DefaultPageSize} {
+			if v, ok := args[key]; ok && len(v) != 0 {
+				params[key] = string(v)
+			}
+		}
+		return Return(d.SetContractConfig(params))
+	case model.Method_Migrate:
+		batch := OptionInt(model.Params_MigrationBatch, defaultMigrationBatch)
+		return ReturnJson(d.Migrate(batch))
+	case model.Method_GetMigrationStatus:
+		return ReturnJson(d.GetMigrationStatus())
+	case model.Method_VcIssueLog:
+		issuer, err := RequireString(model.Params_Issuer)
+		if err != nil {
+			return ReturnError(err)
+		}
+
+		did, err := RequireString(model.Params_Did)
+		if err != nil {
+			return ReturnError(err)
+		}
+
+		vcId, err := RequireString(model.Params_VcId)
+		if err != nil {
+			return ReturnError(err)
+		}
+
+		vcTemplateId, err := RequireString(model.Params_VcTemplateId)
+		if err != nil {
+			return ReturnError(err)
+		}
+
+		return Return(d.VcIssueLog(issuer, did, vcTemplateId, vcId))
+
+	case model.Method_SetStatusList:
+		listCredential, err := RequireString(model.Params_StatusListCredential)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return Return(d.SetStatusList(listCredential))
+	case model.Method_GetStatusList:
+		id, err := RequireString(model.Params_StatusListId)
+		if err != nil {
+			return ReturnError(err)
+		}
+		return ReturnBytes(d.GetStatusList(id))
+	case model.Method_GetVcIssueLogs:
+		args := sdk.Instance.GetArgs()
+		vcIdSearch := args[model.Params_VcIdSearch]
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetVcIssueLogs(string(vcIdSearch), start, count))
+	case model.Method_GetAuditLog:
+		args := sdk.Instance.GetArgs()
+		auditMethod := args[model.Params_AuditMethod]
+		operator := args[model.Params_Operator]
+		startTime := OptionInt64(model.Params_StartTime, 0)
+		endTime := OptionInt64(model.Params_EndTime, 0)
+		start := OptionInt(model.Params_SearchStart, 1)
+		count := OptionInt(model.Params_SearchCount, 0)
+		return ReturnJson(d.GetAuditLog(string(auditMethod), string(operator), startTime, endTime, start, count))
+	}
+
+	enableTrustIssuer, err := d.dal.getEnableTrustIssuer()
+	if err != nil {
+		return ReturnError(err)
+	}
+
+	if enableTrustIssuer == "true" {
+		switch method {
+		case model.Method_AddTrustIssuer:
+			dids, err := RequireStringList(model.Params_Did, model.Params_DidList)
+			if err != nil {
+				return ReturnError(err)
+			}
+			templateIds, err := OptionStringList(model.Params_VcTemplateIdList)
+			if err != nil {
+				return ReturnError(err)
+			}
+			delegable, err := OptionBool(model.Params_Delegable, false)
+			if err != nil {
+				return ReturnError(err)
+			}
+			maxDepth := OptionInt(model.Params_MaxDepth, 0)
+			return Return(d.AddTrustIssuerList(dids, templateIds, delegable, maxDepth))
+		case model.Method_DeleteTrustIssuer:
+			dids, err := RequireStringList(model.Params_Did, model.Params_DidList)
+			if err != nil {
+				return ReturnError(err)
+			}
+			return Return(d.DeleteTrustIssuer(dids))
+		case model.Method_GetTrustIssuer:
+			args := sdk.Instance.GetArgs()
+			didSearch := args[model.Params_DidSearch]
+			start := OptionInt(model.Params_SearchStart, 1)
+			count := OptionInt(model.Params_SearchCount, 0)
+			return ReturnJson(d.GetTrustIssuer(string(didSearch), start, count))
+		case model.Method_GetTrustIssuerInfo:
+			did, err := RequireString(model.Params_Did)
+			if err != nil {
+				return ReturnError(err)
+			}
+			return ReturnJson(d.GetTrustIssuerInfo(did))
+		case model.Method_AccreditIssuer:
+			did, err := RequireString(model.Params_Did)
+			if err != nil {
+				return ReturnError(err)
+			}
+			templateIds, err := OptionStringList(model.Params_VcTemplateIdList)
+			if err != nil {
+				return ReturnError(err)
+			}
+			delegable, err := OptionBool(model.Params_Delegable, false)
+			if err != nil {
+				return ReturnError(err)
+			}
+			maxDepth := OptionInt(model.Params_MaxDepth, 0)
+			return Return(d.AccreditIssuer(did, templateIds, delegable, maxDepth))
+		case model.Method_RevokeAccreditation:
+			did, err := RequireString(model.Params_Did)
+			if err != nil {
+				return ReturnError(err)
+			}
+			return Return(d.RevokeAccreditation(did))
+		case model.Method_GetAccreditationChain:
+			did, err := RequireString(model.Params_Did)
+			if err != nil {
+				return ReturnError(err)
+			}
+			return ReturnJson(d.GetAccreditationChain(did))
+		}
+	}
+
+	return ReturnError(model.NewError(model.ErrCode_InvalidParameter, "invalid method"))
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCreatorSki = "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+	testAdminSki   = "b1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+	testUserSki    = "c1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+	testIssuerSki  = "d1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+
+	testIssuerDid = "did:cm:issuer1"
+	testUserDid   = "did:cm:user1"
+)
+
+// newTestContract 使用内存合约SDK安装DID合约
+// @params enableTrustIssuer 是否启用信任签发者
+func newTestContract(t *testing.T, enableTrustIssuer bool) (*DidContract, *mock.SDK) {
+	m := mock.NewSDK(testCreatorSki)
+	sdk.Instance = m
+
+	d := new(DidContract)
+
+	enable := "false"
+	if enableTrustIssuer {
+		enable = "true"
+	}
+
+	m.BeginTx("", map[string][]byte{
+		model.Params_DidMethod:         []byte("cm"),
+		model.Params_EnableTrustIssuer: []byte(enable),
+	})
+	requireOK(t, d.InitContract())
+	m.Commit()
+
+	return d, m
+}
+
+// invokeAs 以指定发送者发送一笔交易，执行成功时提交，失败时回滚
+func invokeAs(d *DidContract, m *mock.SDK, sender, method string, args map[string]string) protogo.Response {
+	m.SetSender(sender)
+
+	params := make(map[string][]byte)
+	for k, v := range args {
+		params[k] = []byte(v)
+	}
+
+	m.BeginTx("", params)
+	resp := d.InvokeContract(method)
+	if resp.Status == sdk.OK {
+		m.Commit()
+	} else {
+		m.Rollback()
+	}
+
+	return resp
+}
+
+// seedDid 直接写入DID Document和地址索引，使公钥SKI对应该DID
+func seedDid(t *testing.T, d *DidContract, m *mock.SDK, did, ski string) {
+	addr, err := skiToAddress(ski)
+	require.Nil(t, err)
+
+	doc, err := json.Marshal(map[string]string{"id": did})
+	require.Nil(t, err)
+
+	m.BeginTx("", nil)
+	require.Nil(t, d.dal.putDidDocument(did, doc))
+	require.Nil(t, d.dal.putIndexAddress(addr, did))
+	m.Commit()
+}
+
+func requireOK(t *testing.T, resp protogo.Response) {
+	require.Equal(t, int32(sdk.OK), resp.Status, resp.Message)
+}
+
+func requireFail(t *testing.T, resp protogo.Response) {
+	require.NotEqual(t, int32(sdk.OK), resp.Status)
+}
+
+// requireFailCode 要求调用失败且返回消息带有指定的错误码
+func requireFailCode(t *testing.T, resp protogo.Response, code model.ErrorCode) {
+	requireFail(t, resp)
+	require.Equal(t, code, model.ParseErrorMessage(resp.Message).Code, resp.Message)
+}
+
+func didListJson(t *testing.T, dids ...string) string {
+	b, err := json.Marshal(dids)
+	require.Nil(t, err)
+	return string(b)
+}
+
+func TestErrorCode(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_GetDidDocument, map[string]string{
+		model.Params_Did: testUserDid,
+	}), model.ErrCode_DidNotFound)
+
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_GetDidDocument, nil),
+		model.ErrCode_InvalidParameter)
+
+	requireFailCode(t, invokeAs(d, m, testUserSki, "unknownMethod", nil), model.ErrCode_InvalidParameter)
+
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: testIssuerDid,
+	}), model.ErrCode_PermissionDenied)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: testUserDid,
+	}))
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_GetDidDocument, map[string]string{
+		model.Params_Did: testUserDid,
+	}), model.ErrCode_InBlackList)
+
+	// 没有错误码的消息解析为ErrCode_Unknown
+	e := model.ParseErrorMessage("some error")
+	require.Equal(t, model.ErrCode_Unknown, e.Code)
+	require.Equal(t, "some error", e.Message)
+
+	// 包装后的错误保留原错误码
+	err := fmt.Errorf("failed: [%w]", model.NewError(model.ErrCode_Revoked, "the VC is revoked"))
+	msg := model.FormatErrorMessage(err)
+	require.Equal(t, "[DID-4005] failed: [the VC is revoked]", msg)
+	require.True(t, errors.Is(model.ParseErrorMessage(msg), model.ErrRevoked))
+	require.False(t, errors.Is(model.ParseErrorMessage(msg), model.ErrDidNotFound))
+}
@@ -0,0 +1,1203 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/common/v2/evmutils"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// 此为存入数据库的世界状态key，故越短越好
+const (
+	keyDid           = "d"
+	keyIndexPubKey   = "p"
+	keyIndexAddress  = "a"
+	keyTrustIssuer   = "ti"
+	keyRevokeVc      = "r"
+	keyBlackList     = "b"
+	keyVcTemplate    = "vt"
+	keyContractAdmin = "admin"
+	keyVcIssueLog    = "l"
+	keyRole          = "ro"
+	keyProposal      = "pp"
+	keyAuditLog      = "au"
+	keyStatusList    = "sl"
+	keySuspendVc     = "sv"
+	keyVcRevocation  = "rv"
+	keyVcIssueRecord = "lr"
+	keyVcIssuerIndex = "li"
+
+	// 合约状态数据，只存出一次，不需要很短的key来节省空间
+	keyContractStatus       = "cs"
+	failedDidMethod         = "didMethod"
+	failedEnableTrustIssuer = "enableTrustIssuer"
+	failedProposalQuorum    = "proposalQuorum"
+	failedAuditSeq          = "auditSeq"
+	failedMaxDocumentSize   = "maxDocumentSize"
+	failedDefaultPageSize   = "defaultPageSize"
+	failedSchemaVersion     = "schemaVersion"
+	failedMigrationCursor   = "migrationCursor"
+)
+
+const (
+	defaultSearchCount = 1000
+	defaultSearchStart = 1
+)
+
+// maxStateField 世界状态的field只能由字母、数字、点、下划线和中划线组成，最长64个字符，作为迭代的上界
+// 迭代不包含上界，field恰好为该值的数据不会被遍历
+var maxStateField = strings.Repeat("z", 64)
+
+// Dal 数据库访问层
+type Dal struct {
+}
+
+func NewDal() *Dal {
+	return &Dal{}
+}
+
+// Db 获取数据库实例
+func (dal *Dal) Db() sdk.SDKInterface {
+	return sdk.Instance
+}
+
+// newIteratorFromField 从startField开始（包含startField）按field的字典序遍历key下的数据，startField为空时从头开始
+func (dal *Dal) newIteratorFromField(key, startField string) (sdk.ResultSetKV, error) {
+	return dal.Db().NewIteratorWithField(key, startField, maxStateField)
+}
+
+func (dal *Dal) putDidMethod(didMethod string) error {
+	// 将DID Method存入数据库
+	err := dal.Db().PutStateByte(keyContractStatus, failedDidMethod, []byte(didMethod))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getDidMethod() (string, error) {
+	// 从数据库中获取DID Method
+	didMethod, err := dal.Db().GetStateByte(keyContractStatus, failedDidMethod)
+	if err != nil {
+		return "", err
+	}
+	return string(didMethod), nil
+}
+
+func (dal *Dal) putEnableTrustIssuer(enableTrustIssuer string) error {
+	// 将EnableTrustIssuer 存入数据库
+	err := dal.Db().PutStateByte(keyContractStatus, failedEnableTrustIssuer, []byte(enableTrustIssuer))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getEnableTrustIssuer() (string, error) {
+	// 从数据库中获取 EnableTrustIssuer
+	enableTrustIssuer, err := dal.Db().GetStateByte(keyContractStatus, failedEnableTrustIssuer)
+	if err != nil {
+		return "", err
+	}
+	return string(enableTrustIssuer), nil
+}
+
+func (dal *Dal) putMaxDocumentSize(size int) error {
+	err := dal.Db().PutStateByte(keyContractStatus, failedMaxDocumentSize, []byte(strconv.Itoa(size)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getMaxDocumentSize() (int, error) {
+	// 未设置时不限制DID Document的大小
+	value, err := dal.Db().GetStateByte(keyContractStatus, failedMaxDocumentSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	return strconv.Atoi(string(value))
+}
+
+func (dal *Dal) putDefaultPageSize(size int) error {
+	err := dal.Db().PutStateByte(keyContractStatus, failedDefaultPageSize, []byte(strconv.Itoa(size)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getDefaultPageSize 获取列表查询的默认条数，未设置或读取失败时使用defaultSearchCount
+func (dal *Dal) getDefaultPageSize() int {
+	value, err := dal.Db().GetStateByte(keyContractStatus, failedDefaultPageSize)
+	if err != nil || len(value) == 0 {
+		return defaultSearchCount
+	}
+
+	size, err := strconv.Atoi(string(value))
+	if err != nil || size <= 0 {
+		return defaultSearchCount
+	}
+
+	return size
+}
+
+func (dal *Dal) putSchemaVersion(version int) error {
+	err := dal.Db().PutStateByte(keyContractStatus, failedSchemaVersion, []byte(strconv.Itoa(version)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getSchemaVersion() (int, error) {
+	// 早期版本的合约没有记录数据版本，视为0
+	value, err := dal.Db().GetStateByte(keyContractStatus, failedSchemaVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	return strconv.Atoi(string(value))
+}
+
+func (dal *Dal) putMigrationCursor(cursor string) error {
+	err := dal.Db().PutStateByte(keyContractStatus, failedMigrationCursor, []byte(cursor))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getMigrationCursor() (string, error) {
+	value, err := dal.Db().GetStateByte(keyContractStatus, failedMigrationCursor)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func (dal *Dal) putProposalQuorum(quorum int) error {
+	err := dal.Db().PutStateByte(keyContractStatus, failedProposalQuorum, []byte(strconv.Itoa(quorum)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getProposalQuorum() (int, error) {
+	// 未设置时法定人数为1，即不启用提案治理
+	value, err := dal.Db().GetStateByte(keyContractStatus, failedProposalQuorum)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(value) == 0 {
+		return 1, nil
+	}
+
+	return strconv.Atoi(string(value))
+}
+
+func (dal *Dal) putAuditSeq(seq uint64) error {
+	err := dal.Db().PutStateByte(keyContractStatus, failedAuditSeq, []byte(strconv.FormatUint(seq, 10)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getAuditSeq() (uint64, error) {
+	// 没有审计日志时序号为0
+	value, err := dal.Db().GetStateByte(keyContractStatus, failedAuditSeq)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(string(value), 10, 64)
+}
+
+func (dal *Dal) putAuditRecord(seq uint64, record []byte) error {
+	//将审计日志存入数据库
+	err := dal.Db().PutStateByte(keyAuditLog, auditSeqToKey(seq), record)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) searchAuditLog(method, operator string, startTime, endTime int64,
+	start, count int) ([]*model.AuditRecord, error) {
+	//从数据库中查询审计日志迭代器，按序号从小到大排列
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyAuditLog, "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var auditSlice []*model.AuditRecord
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var record model.AuditRecord
+		err = json.Unmarshal(value, &record)
+		if err != nil {
+			return nil, err
+		}
+
+		if !record.Match(method, operator, startTime, endTime) {
+			continue
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i >= start {
+			auditSlice = append(auditSlice, &record)
+		}
+		i++
+	}
+
+	return auditSlice, nil
+}
+
+func (dal *Dal) putAdmin(ski string, record []byte) error {
+	err := dal.Db().PutStateByte(keyContractAdmin, ski, record)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getAdmin(ski string) (*model.AdminRecord, error) {
+	value, err := dal.Db().GetStateByte(keyContractAdmin, ski)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	return model.ParseAdminRecord(value)
+}
+
+func (dal *Dal) deleteAdmin(ski string) error {
+	err := dal.Db().DelState(keyContractAdmin, ski)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) isAdmin(ski string) bool {
+	//从数据库中获取DID Document
+	v, err := dal.Db().GetStateByte(keyContractAdmin, ski)
+	if err != nil {
+		return false
+	}
+
+	if len(v) == 0 {
+		return false
+	}
+
+	return true
+}
+
+func (dal *Dal) getAdminList() ([]*model.AdminRecord, error) {
+	//从数据库中查询管理员迭代器
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyContractAdmin, "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var adminSlice []*model.AdminRecord
+
+	for iter.HasNext() {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := model.ParseAdminRecord(value)
+		if err != nil {
+			return nil, err
+		}
+
+		adminSlice = append(adminSlice, record)
+	}
+
+	return adminSlice, nil
+}
+
+func (dal *Dal) putProposal(id string, proposal []byte) error {
+	//将提案存入数据库
+	err := dal.Db().PutStateByte(keyProposal, id, proposal)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getProposal(id string) (*model.Proposal, error) {
+	//从数据库中获取提案
+	value, err := dal.Db().GetStateByte(keyProposal, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var proposal model.Proposal
+	err = json.Unmarshal(value, &proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proposal, nil
+}
+
+func (dal *Dal) searchProposal(status string, now int64, start int, count int) ([]*model.Proposal, error) {
+	//从数据库中查询提案迭代器，status为空时查询所有提案
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyProposal, "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var proposalSlice []*model.Proposal
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var proposal model.Proposal
+		err = json.Unmarshal(value, &proposal)
+		if err != nil {
+			return nil, err
+		}
+
+		if proposal.IsExpired(now) {
+			proposal.Status = model.ProposalStatus_Expired
+		}
+
+		if len(status) != 0 && proposal.Status != status {
+			continue
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i >= start {
+			proposalSlice = append(proposalSlice, &proposal)
+		}
+		i++
+	}
+
+	return proposalSlice, nil
+}
+
+func (dal *Dal) putRoleMember(role, member string, record []byte) error {
+	//将角色成员记录存入数据库
+	err := dal.Db().PutStateByte(keyRole, dal.roleMemberToDbKey(role, member), record)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getRoleMember(role, member string) (*model.RoleMember, error) {
+	//从数据库中获取角色成员记录
+	value, err := dal.Db().GetStateByte(keyRole, dal.roleMemberToDbKey(role, member))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var record model.RoleMember
+	err = json.Unmarshal(value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (dal *Dal) hasRole(role, member string) bool {
+	record, err := dal.getRoleMember(role, member)
+	if err != nil || record == nil {
+		return false
+	}
+	return true
+}
+
+func (dal *Dal) deleteRoleMember(role, member string) error {
+	//从数据库中删除角色成员记录
+	err := dal.Db().DelState(keyRole, dal.roleMemberToDbKey(role, member))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) searchRoleMember(role string, start int, count int) ([]*model.RoleMember, error) {
+	//从数据库中查询角色成员迭代器，role为空时查询所有角色
+	prefix := role
+	if len(role) != 0 {
+		prefix = role + "_"
+	}
+
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyRole, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var memberSlice []*model.RoleMember
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); i++ {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i < start {
+			continue
+		}
+
+		var record model.RoleMember
+		err = json.Unmarshal(value, &record)
+		if err != nil {
+			return nil, err
+		}
+
+		memberSlice = append(memberSlice, &record)
+	}
+
+	return memberSlice, nil
+}
+
+func (dal *Dal) putDidDocument(did string, didDocument []byte) error {
+	//将DID Document存入数据库
+	err := dal.Db().PutStateByte(keyDid, dal.didToDbKey(did), didDocument)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getDidDocument(did string) ([]byte, error) {
+	//从数据库中获取DID Document
+	didDocument, err := dal.Db().GetStateByte(keyDid, dal.didToDbKey(did))
+	if err != nil {
+		return nil, err
+	}
+	return didDocument, nil
+}
+
+func (dal *Dal) isDidDocExisting(did string) bool {
+	didDocument, err := dal.Db().GetStateByte(keyDid, dal.didToDbKey(did))
+	if err != nil {
+		return false
+	}
+
+	if len(didDocument) == 0 {
+		return false
+	}
+
+	return true
+}
+
+func (dal *Dal) putIndexPubKey(pubKey string, did string) error {
+	//将索引存入数据库
+	err := dal.Db().PutStateByte(keyIndexPubKey, pubKeyToDbKey([]byte(pubKey)), []byte(did))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) deleteIndexPubKey(pubKey string) error {
+	//从数据库中删除索引
+	err := dal.Db().DelState(keyIndexPubKey, pubKeyToDbKey([]byte(pubKey)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getDidByPubKey(pubKey string) (string, error) {
+	//从数据库中获取索引
+	did, err := dal.Db().GetStateByte(keyIndexPubKey, pubKeyToDbKey([]byte(pubKey)))
+	if err != nil {
+		return "", err
+	}
+	return string(did), nil
+}
+
+func (dal *Dal) putIndexAddress(address string, did string) error {
+	//将索引存入数据库
+	err := dal.Db().PutStateByte(keyIndexAddress, address, []byte(did))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) deleteIndexAddress(address string) error {
+	//从数据库中删除索引
+	err := dal.Db().DelState(keyIndexAddress, address)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getDidByAddress(address string) (string, error) {
+	//从数据库中获取索引
+	did, err := dal.Db().GetStateByte(keyIndexAddress, address)
+	if err != nil {
+		return "", err
+	}
+	return string(did), nil
+}
+
+func (dal *Dal) putBlackList(did string, record []byte) error {
+	//将BlackList记录存入数据库
+	err := dal.Db().PutStateByte(keyBlackList, dal.didToDbKey(did), record)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getBlackList(did string) (*model.BlackListRecord, error) {
+	//从数据库中获取BlackList记录
+	value, err := dal.Db().GetStateByte(keyBlackList, dal.didToDbKey(did))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	return model.ParseBlackListRecord(value)
+}
+
+func (dal *Dal) isInBlackList(did string) bool {
+	record, err := dal.getBlackList(did)
+	if err != nil || record == nil {
+		return false
+	}
+
+	// 过期的黑名单记录不再生效
+	if record.ExpireTime > 0 {
+		now, err := model.GetTxTime()
+		if err != nil {
+			return true
+		}
+		return !record.IsExpired(now)
+	}
+
+	return true
+}
+
+func (dal *Dal) deleteBlackList(did string) error {
+	//从数据库中删除BlackList
+	err := dal.Db().DelState(keyBlackList, dal.didToDbKey(did))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) searchBlackList(didSearch string, start int, count int) ([]*model.BlackListRecord, error) {
+	//从数据库中查询BlackList迭代器
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyBlackList, dal.didToDbKey(didSearch))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var recordSlice []*model.BlackListRecord
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); i++ {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i < start {
+			continue
+		}
+
+		record, err := model.ParseBlackListRecord(value)
+		if err != nil {
+			return nil, err
+		}
+
+		recordSlice = append(recordSlice, record)
+	}
+
+	return recordSlice, nil
+}
+
+func (dal *Dal) putTrustIssuer(did string, issuer []byte) error {
+	//将TrustIssuer记录存入数据库
+	err := dal.Db().PutStateByte(keyTrustIssuer, dal.didToDbKey(did), issuer)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getTrustIssuer(did string) (*model.TrustIssuer, error) {
+	//从数据库中获取TrustIssuer记录
+	value, err := dal.Db().GetStateByte(keyTrustIssuer, dal.didToDbKey(did))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	return model.ParseTrustIssuer(value)
+}
+
+func (dal *Dal) deleteTrustIssuer(did string) error {
+	//从数据库中删除TrustIssuer
+	err := dal.Db().DelState(keyTrustIssuer, dal.didToDbKey(did))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) searchTrustIssuer(didSearch string, start int, count int) ([]string, error) {
+	//从数据库中查询RevokeVc迭代器
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyTrustIssuer, dal.didToDbKey(didSearch))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var didSlice []string
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); i++ {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i < start {
+			continue
+		}
+
+		issuer, err := model.ParseTrustIssuer(value)
+		if err != nil {
+			return nil, err
+		}
+
+		didSlice = append(didSlice, issuer.Did)
+	}
+
+	return didSlice, nil
+}
+
+func (dal *Dal) putVcRevocation(issuer, vcId string, record []byte) error {
+	//将VC吊销记录存入数据库
+	err := dal.Db().PutStateByte(keyVcRevocation, vcRecordToDbKey(issuer, vcId), record)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getVcRevocation 获取签发者的VC的吊销记录，VC未被吊销时返回nil
+func (dal *Dal) getVcRevocation(issuer, vcId string) (*model.VcRevocation, error) {
+	value, err := dal.Db().GetStateByte(keyVcRevocation, vcRecordToDbKey(issuer, vcId))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	return model.ParseVcRevocation(value)
+}
+
+// searchVcRevocation 根据vcID前缀查询VC吊销记录，start为起始位置从1开始，count为查询数量
+// field为哈希值，无法按前缀迭代，需要遍历所有记录后过滤，只用于查询，交易中按vcID查找签发者时使用getVcIssuers
+// @params issuer 签发者DID，为空时查询所有签发者
+func (dal *Dal) searchVcRevocation(issuer, vcIdSearch string, start int,
+	count int) ([]*model.VcRevocation, error) {
+	//从数据库中查询VC吊销记录迭代器
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyVcRevocation, "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var recordSlice []*model.VcRevocation
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := model.ParseVcRevocation(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if (len(issuer) != 0 && record.Issuer != issuer) || !strings.HasPrefix(record.VcId, vcIdSearch) {
+			continue
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i >= start {
+			recordSlice = append(recordSlice, record)
+		}
+		i++
+	}
+
+	return recordSlice, nil
+}
+
+func (dal *Dal) putVcSuspension(issuer, vcId string, record []byte) error {
+	return dal.Db().PutStateByte(keySuspendVc, vcRecordToDbKey(issuer, vcId), record)
+}
+
+// getVcSuspension 获取签发者的VC的暂停记录，VC未被暂停时返回nil
+func (dal *Dal) getVcSuspension(issuer, vcId string) (*model.VcSuspension, error) {
+	value, err := dal.Db().GetStateByte(keySuspendVc, vcRecordToDbKey(issuer, vcId))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var record model.VcSuspension
+	err = json.Unmarshal(value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (dal *Dal) deleteVcSuspension(issuer, vcId string) error {
+	return dal.Db().DelState(keySuspendVc, vcRecordToDbKey(issuer, vcId))
+}
+
+func (dal *Dal) putVcTemplate(templateId string, template []byte) error {
+	err := dal.Db().PutStateByte(keyVcTemplate, templateId, template)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getVcTemplate(templateId string) ([]byte, error) {
+	//从数据库中获取VcTemplate
+	value, err := dal.Db().GetStateByte(keyVcTemplate, templateId)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (dal *Dal) putStatusList(id string, listCredential []byte) error {
+	err := dal.Db().PutStateByte(keyStatusList, statusListIdToKey(id), listCredential)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dal *Dal) getStatusList(id string) ([]byte, error) {
+	//从数据库中获取状态列表凭证
+	value, err := dal.Db().GetStateByte(keyStatusList, statusListIdToKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (dal *Dal) searchVcTemplate(templateNameSearch string, start int, count int) ([]*model.VcTemplate, error) {
+	//从数据库中查询VcTemplate迭代器
+	iter, err := dal.Db().NewIteratorPrefixWithKeyField(keyVcTemplate, templateNameSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	defer iter.Close()
+
+	var vcTemplateSlice []*model.VcTemplate
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	for i := 1; iter.HasNext(); i++ {
+		_, _, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if i >= start+count {
+			break
+		}
+
+		if i < start {
+			continue
+		}
+
+		var temp model.VcTemplate
+		err = json.Unmarshal(value, &temp)
+		if err != nil {
+			return nil, err
+		}
+
+		vcTemplateSlice = append(vcTemplateSlice, &temp)
+	}
+
+	return vcTemplateSlice, nil
+}
+
+// putVcIssueLog 保存签发者的VC的签发日志，并在vcID到签发者的索引中记录签发者
+func (dal *Dal) putVcIssueLog(issuer, vcId string, log []byte) error {
+	err := dal.Db().PutStateByte(keyVcIssueRecord, vcRecordToDbKey(issuer, vcId), log)
+	if err != nil {
+		return err
+	}
+
+	issuers, err := dal.getVcIssuers(vcId)
+	if err != nil {
+		return err
+	}
+
+	if isInList(issuer, issuers) {
+		return nil
+	}
+
+	issuersBytes, err := json.Marshal(append(issuers, issuer))
+	if err != nil {
+		return err
+	}
+
+	return dal.Db().PutStateByte(keyVcIssuerIndex, vcIdToHashKey(vcId), issuersBytes)
+}
+
+// getVcIssueLog 获取签发者的VC的签发日志，没有签发日志时返回nil
+func (dal *Dal) getVcIssueLog(issuer, vcId string) (*model.VcIssueLog, error) {
+	value, err := dal.Db().GetStateByte(keyVcIssueRecord, vcRecordToDbKey(issuer, vcId))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var issueLog model.VcIssueLog
+	err = json.Unmarshal(value, &issueLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issueLog, nil
+}
+
+// getLegacyVcIssueLog 获取早期版本合约只按vcID存储的签发日志，仅用于数据迁移和迁移完成前的查询
+func (dal *Dal) getLegacyVcIssueLog(vcId string) ([]byte, error) {
+	return dal.Db().GetStateByte(keyVcIssueLog, vcIdToKey(vcId))
+}
+
+// getLegacyRevokedVc 获取早期版本合约只存储vcID的吊销数据，仅用于迁移完成前的查询
+func (dal *Dal) getLegacyRevokedVc(vcId string) ([]byte, error) {
+	return dal.Db().GetStateByte(keyRevokeVc, vcIdToKey(vcId))
+}
+
+// getVcIssuers 从vcID到签发者的索引获取签发过指定vcID的所有签发者
+func (dal *Dal) getVcIssuers(vcId string) ([]string, error) {
+	value, err := dal.Db().GetStateByte(keyVcIssuerIndex, vcIdToHashKey(vcId))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var issuers []string
+	err = json.Unmarshal(value, &issuers)
+	if err != nil {
+		return nil, err
+	}
+
+	return issuers, nil
+}
+
+// searchVcIssueLogs 根据vcID前缀查询签发日志，start为起始位置从1开始，count为查询数量
+// field为哈希值，无法按前缀迭代，需要遍历所有记录后过滤，只用于查询，交易中按vcID查找签发者时使用getVcIssuers
+// @params includeLegacy 是否同时查询尚未迁移的早期版本签发日志，迁移时每条日志在同一笔交易中转换并删除，不会重复
+func (dal *Dal) searchVcIssueLogs(searchVcId string, start, count int,
+	includeLegacy bool) ([]*model.VcIssueLog, error) {
+	keys := []string{keyVcIssueRecord}
+	if includeLegacy {
+		keys = append(keys, keyVcIssueLog)
+	}
+
+	var issueLogSlice []*model.VcIssueLog
+
+	if count == 0 {
+		count = dal.getDefaultPageSize()
+	}
+
+	if start == 0 {
+		start = defaultSearchStart
+	}
+
+	i := 1
+	for _, key := range keys {
+		iter, err := dal.Db().NewIteratorPrefixWithKeyField(key, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for iter.HasNext() {
+			_, _, value, err := iter.Next()
+			if err != nil {
+				iter.Close()
+				return nil, err
+			}
+
+			var issueLog model.VcIssueLog
+			err = json.Unmarshal(value, &issueLog)
+			if err != nil {
+				iter.Close()
+				return nil, err
+			}
+
+			if !strings.HasPrefix(issueLog.VcId, searchVcId) {
+				continue
+			}
+
+			if i >= start+count {
+				iter.Close()
+				return issueLogSlice, nil
+			}
+
+			if i >= start {
+				issueLogSlice = append(issueLogSlice, &issueLog)
+			}
+			i++
+		}
+
+		iter.Close()
+	}
+
+	return issueLogSlice, nil
+}
+
+func (dal *Dal) didToDbKey(did string) string {
+	didMethod, _ := dal.getDidMethod()
+	didPrefix := "did:" + didMethod + ":"
+	return strings.TrimPrefix(did, didPrefix)
+}
+
+// roleMemberToDbKey 角色成员的数据库field，格式为 角色_成员类型_成员
+func (dal *Dal) roleMemberToDbKey(role, member string) string {
+	memberType := model.GetRoleMemberType(member)
+	if memberType == model.RoleMemberType_Did {
+		member = dal.didToDbKey(member)
+	}
+	return role + "_" + memberType + "_" + member
+}
+
+// auditSeqToKey 审计日志序号补齐为定长字符串，保证迭代顺序与序号顺序一致
+func auditSeqToKey(seq uint64) string {
+	return fmt.Sprintf("%020d", seq)
+}
+
+func pubKeyToDbKey(pubKey []byte) string {
+	hash := sha256.Sum256(pubKey)
+	return hex.EncodeToString(hash[:])
+}
+
+// statusListIdToKey 状态列表凭证的ID通常是URL，使用哈希值作为数据库field
+func statusListIdToKey(id string) string {
+	hash := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(hash[:])
+}
+
+// vcRecordToDbKey 签发者和vcID都可能包含任意字符，使用两者的哈希值作为数据库field，不同签发者的同名VC互不影响
+func vcRecordToDbKey(issuer, vcId string) string {
+	value, _ := json.Marshal([]string{issuer, vcId})
+	hash := sha256.Sum256(value)
+	return hex.EncodeToString(hash[:])
+}
+
+// vcIdToHashKey vcID可能包含任意字符，使用其哈希值作为数据库field
+func vcIdToHashKey(vcId string) string {
+	hash := sha256.Sum256([]byte(vcId))
+	return hex.EncodeToString(hash[:])
+}
+
+func vcIdToKey(vcID string) string {
+	//vcid 是一个http url，为了存入数据库，需要将其转换为一个只有字母大小写、数字、下划线的字符串
+	if len(vcID) == 0 {
+		return vcID
+	}
+
+	vcID = strings.ReplaceAll(vcID, ":", "_")
+	vcID = strings.ReplaceAll(vcID, "/", "_")
+	vcID = strings.ReplaceAll(vcID, ".", "_")
+	vcID = strings.ReplaceAll(vcID, "-", "_")
+	return vcID
+}
+
+func (dal *Dal) getSenderDid() (string, error) {
+	ski, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return "", err
+	}
+
+	return dal.getDidBySki(ski)
+}
+
+// getDidBySki 根据公钥SKI计算地址，查找对应的DID
+func (dal *Dal) getDidBySki(ski string) (string, error) {
+	addr, err := skiToAddress(ski)
+	if err != nil {
+		return "", err
+	}
+
+	return dal.getDidByAddress(addr)
+}
+
+// skiToAddress 根据公钥SKI计算链上地址
+func skiToAddress(ski string) (string, error) {
+	skiBytes, err := hex.DecodeString(ski)
+	if err != nil {
+		return "", err
+	}
+
+	bytesAddr := evmutils.Keccak256(skiBytes)
+	return hex.EncodeToString(bytesAddr)[24:], nil
+}
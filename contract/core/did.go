@@ -5,13 +5,14 @@ Copyright (C) DCPS. All rights reserved.
 SPDX-License-Identifier: Apache-2.0
 */
 
-package main
+package core
 
 import (
-	"did-contract/model"
-	"errors"
-	"fmt"
+	"encoding/json"
 	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
 )
 
 // DidMethod 获取DID Method
@@ -31,12 +32,12 @@ func (d *DidContract) IsValidDid(did string) (bool, error) {
 
 	ok := strings.HasPrefix(did, didPrefix)
 	if !ok {
-		return false, errors.New("invalid did method")
+		return false, model.NewError(model.ErrCode_InvalidParameter, "invalid did method")
 	}
 
 	ok = d.dal.isInBlackList(did)
 	if ok {
-		return false, errors.New("the did in the black list")
+		return false, model.NewError(model.ErrCode_InBlackList, "the did in the black list")
 	}
 
 	return true, nil
@@ -44,19 +45,25 @@ func (d *DidContract) IsValidDid(did string) (bool, error) {
 
 // AddDidDocument 添加DID Document
 func (d *DidContract) AddDidDocument(didDocument string) error {
+	err := d.checkDocumentSize(didDocument)
+	if err != nil {
+		return err
+	}
+
 	didDoc, err := model.NewDIDDocument(didDocument)
 	if err != nil {
-		return errors.New("invalid did document")
+		return model.NewError(model.ErrCode_InvalidDidDocument, "invalid did document")
 	}
 
 	ok, err := d.IsValidDid(didDoc.Id)
 	if !ok {
-		return fmt.Errorf("invalid DID, err: [%s]", err.Error())
+		return model.NewError(model.ErrCode_InvalidDidDocument, "invalid DID, err: [%s]", err.Error())
 	}
 
 	ok, err = didDoc.VerifyProof()
 	if !ok {
-		return fmt.Errorf("the DID doc proof verify failed, err: [%s]", err.Error())
+		return model.NewError(model.ErrCode_InvalidDidDocument,
+			"the DID doc proof verify failed, err: [%s]", err.Error())
 	}
 
 	//存储DID Document
@@ -67,7 +74,7 @@ func (d *DidContract) addDidDocument(didDoc *model.DidDocument) error {
 	//检查DID Document是否存在
 	dbDidDoc, _ := d.dal.getDidDocument(didDoc.Id)
 	if len(dbDidDoc) != 0 {
-		return errors.New("did document already exists")
+		return model.NewError(model.ErrCode_DidAlreadyExists, "did document already exists")
 	}
 
 	did, pubKeys, addresses := didDoc.ParsePubKeyAddress()
@@ -111,7 +118,7 @@ func (d *DidContract) GetDidDocument(did string) (string, error) {
 	}
 
 	if !valid {
-		return "", errors.New("invalid did")
+		return "", model.NewError(model.ErrCode_InvalidParameter, "invalid did")
 	}
 
 	didDoc, err := d.dal.getDidDocument(did)
@@ -119,26 +126,34 @@ func (d *DidContract) GetDidDocument(did string) (string, error) {
 		return "", err
 	}
 
+	if len(didDoc) == 0 {
+		return "", model.NewError(model.ErrCode_DidNotFound, "the did's doc not found on chain, did: [%s]", did)
+	}
+
 	return string(didDoc), nil
 }
 
 // UpdateDidDocument 更新DID Document
 func (d *DidContract) UpdateDidDocument(didDocument string) error {
+	err := d.checkDocumentSize(didDocument)
+	if err != nil {
+		return err
+	}
 
 	didDoc, err := model.NewDIDDocument(didDocument)
 	if err != nil {
-		return errors.New("invalid did document")
+		return model.NewError(model.ErrCode_InvalidDidDocument, "invalid did document")
 	}
 
 	// 检查old DID Document是否存在
 	oldDocBytes, err := d.dal.getDidDocument(didDoc.Id)
 	if err != nil || oldDocBytes == nil {
-		return errors.New("did does not exist")
+		return model.NewError(model.ErrCode_DidNotFound, "did does not exist")
 	}
 
 	oldDoc, err := model.NewDIDDocument(string(oldDocBytes))
 	if err != nil {
-		return errors.New("invalid old did document")
+		return model.NewError(model.ErrCode_InvalidDidDocument, "invalid old did document")
 	}
 
 	senderDid, err := d.dal.getSenderDid()
@@ -158,20 +173,31 @@ func (d *DidContract) UpdateDidDocument(didDocument string) error {
 	}
 
 	if !hasPermission {
-		ok, _ := isSenderAdmin(d)
+		// 以DID注册的管理员公钥随DID Document同步，只能由DID的控制者更新
+		adminSkis, err := d.getAdminSkisByDid(didDoc.Id)
+		if err != nil {
+			return err
+		}
+		if len(adminSkis) != 0 {
+			return model.NewError(model.ErrCode_PermissionDenied,
+				"the DID is registered as admin, only its controller can update the did document")
+		}
+
+		ok, _ := d.hasSenderRole(model.Role_DidOperator)
 		if !ok {
-			return errors.New("no operation permission")
+			return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
 		}
 	}
 
 	ok, err := d.IsValidDid(didDoc.Id)
 	if !ok {
-		return fmt.Errorf("invalid DID, err: [%s]", err.Error())
+		return model.NewError(model.ErrCode_InvalidDidDocument, "invalid DID, err: [%s]", err.Error())
 	}
 
 	ok, err = didDoc.VerifyProof()
 	if !ok {
-		return fmt.Errorf("the DID doc proof verify failed, err: [%s]", err.Error())
+		return model.NewError(model.ErrCode_InvalidDidDocument,
+			"the DID doc proof verify failed, err: [%s]", err.Error())
 	}
 
 	return d.updateDidDocument(didDoc, oldDoc)
@@ -229,6 +255,12 @@ func (d *DidContract) updateDidDocument(didDoc, oldDoc *model.DidDocument) error
 		}
 	}
 
+	// 以DID注册的管理员公钥随DID Document同步
+	err = d.syncAdminByDid(did, pubKeys)
+	if err != nil {
+		return err
+	}
+
 	// 发送事件
 	emitSetDidDocumentEvent(did, string(didDoc.JsonRaw()))
 	return nil
@@ -245,19 +277,48 @@ func (d *DidContract) GetDidByAddress(address string) (string, error) {
 }
 
 // AddBlackList 添加黑名单
-func (d *DidContract) AddBlackList(dids []string) error {
+// @params dids 要加入黑名单的DID列表
+// @params reasonCode 原因编码
+// @params reason 原因描述
+// @params expireTime 过期时间，0表示永久有效
+func (d *DidContract) AddBlackList(dids []string, reasonCode int, reason string, expireTime int64) error {
 
-	ok, err := isSenderAdmin(d)
+	ok, err := d.hasSenderRole(model.Role_BlackListManager)
 	if err != nil {
 		return err
 	}
 
 	if !ok {
-		return errors.New("no operation permission")
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	return d.addBlackList(dids, reasonCode, reason, expireTime)
+}
+
+func (d *DidContract) addBlackList(dids []string, reasonCode int, reason string, expireTime int64) error {
+	operator, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return err
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	if expireTime != 0 && expireTime <= myTime {
+		return model.NewError(model.ErrCode_InvalidParameter, "the expiration time must be later than the current time")
 	}
 
 	for _, did := range dids {
-		err := d.dal.putBlackList(did)
+		record := model.NewBlackListRecord(did, reasonCode, reason, operator, myTime, expireTime)
+
+		recordBytes, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		err = d.dal.putBlackList(did, recordBytes)
 		if err != nil {
 			return err
 		}
@@ -269,14 +330,18 @@ func (d *DidContract) AddBlackList(dids []string) error {
 
 // DeleteBlackList 删除黑名单
 func (d *DidContract) DeleteBlackList(dids []string) error {
-	ok, err := isSenderAdmin(d)
+	ok, err := d.hasSenderRole(model.Role_BlackListManager)
 	if err != nil {
 		return err
 	}
 	if !ok {
-		return errors.New("no operation permission")
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
 	}
 
+	return d.deleteBlackList(dids)
+}
+
+func (d *DidContract) deleteBlackList(dids []string) error {
 	for _, did := range dids {
 		err := d.dal.deleteBlackList(did)
 		if err != nil {
@@ -288,29 +353,64 @@ func (d *DidContract) DeleteBlackList(dids []string) error {
 	return nil
 }
 
-// GetBlackList 获取黑名单
-func (d *DidContract) GetBlackList(didSearch string, start int, count int) ([]string, error) {
+// GetBlackList 获取黑名单记录
+func (d *DidContract) GetBlackList(didSearch string, start int, count int) ([]*model.BlackListRecord, error) {
 	return d.dal.searchBlackList(didSearch, start, count)
 }
 
 // AddTrustIssuerList 添加信任发行者
-func (d *DidContract) AddTrustIssuerList(dids []string) error {
-	ok, err := isSenderAdmin(d)
+// @params dids 签发者DID列表
+// @params templateIds 允许签发的VC模板ID列表，为空表示可以签发所有模板
+// @params delegable 是否有权认证下级签发者
+// @params maxDepth 可以向下认证的最大层数，0表示不限制
+func (d *DidContract) AddTrustIssuerList(dids []string, templateIds []string, delegable bool, maxDepth int) error {
+	ok, err := d.hasSenderRole(model.Role_IssuerManager)
 	if err != nil {
 		return err
 	}
 	if !ok {
-		return errors.New("no operation permission")
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	return d.addTrustIssuerList(dids, templateIds, delegable, maxDepth)
+}
+
+func (d *DidContract) addTrustIssuerList(dids []string, templateIds []string, delegable bool, maxDepth int) error {
+	if maxDepth < 0 {
+		return model.NewError(model.ErrCode_InvalidParameter, "the max depth of accreditation can not be negative")
+	}
+
+	// 判断模板是否已经上链
+	for _, id := range templateIds {
+		temp, err := d.dal.getVcTemplate(id)
+		if err != nil {
+			return err
+		}
+
+		if len(temp) == 0 {
+			return model.NewError(model.ErrCode_VcTemplateNotFound, "the vc template not found on chain, id: [%s]", id)
+		}
 	}
 
 	for _, did := range dids {
 		// 判断DID Doc是否已经上链
 		ok := d.dal.isDidDocExisting(did)
 		if !ok {
-			return fmt.Errorf("the did's doc not found on chain, did: [%s]", did)
+			return model.NewError(model.ErrCode_DidNotFound, "the did's doc not found on chain, did: [%s]", did)
+		}
+
+		issuer := model.NewTrustIssuer(did, templateIds)
+		issuer.Delegable = delegable
+		if delegable {
+			issuer.MaxDepth = maxDepth
+		}
+
+		issuerBytes, err := json.Marshal(issuer)
+		if err != nil {
+			return err
 		}
 
-		err := d.dal.putTrustIssuer(did)
+		err = d.dal.putTrustIssuer(did, issuerBytes)
 		if err != nil {
 			return err
 		}
@@ -321,14 +421,18 @@ func (d *DidContract) AddTrustIssuerList(dids []string) error {
 
 // DeleteTrustIssuer 删除信任发行者
 func (d *DidContract) DeleteTrustIssuer(dids []string) error {
-	ok, err := isSenderAdmin(d)
+	ok, err := d.hasSenderRole(model.Role_IssuerManager)
 	if err != nil {
 		return err
 	}
 	if !ok {
-		return errors.New("no operation permission")
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
 	}
 
+	return d.deleteTrustIssuer(dids)
+}
+
+func (d *DidContract) deleteTrustIssuer(dids []string) error {
 	for _, did := range dids {
 		err := d.dal.deleteTrustIssuer(did)
 		if err != nil {
@@ -344,3 +448,17 @@ func (d *DidContract) DeleteTrustIssuer(dids []string) error {
 func (e *DidContract) GetTrustIssuer(didSearch string, start int, count int) ([]string, error) {
 	return e.dal.searchTrustIssuer(didSearch, start, count)
 }
+
+// GetTrustIssuerInfo 获取信任发行者的记录（包含可签发的模板范围）
+func (d *DidContract) GetTrustIssuerInfo(did string) (*model.TrustIssuer, error) {
+	issuer, err := d.dal.getTrustIssuer(did)
+	if err != nil {
+		return nil, err
+	}
+
+	if issuer == nil {
+		return nil, model.NewError(model.ErrCode_NotTrustedIssuer, "the did is not a trusted issuer")
+	}
+
+	return issuer, nil
+}
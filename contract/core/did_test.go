@@ -0,0 +1,226 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlackList(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_DidList:    didListJson(t, "did:cm:test1", "did:cm:test2"),
+		model.Params_ReasonCode: strconv.Itoa(model.BlackListReasonFraud),
+		model.Params_Reason:     "fraud",
+	}))
+
+	// 过期时间不能早于当前时间
+	requireFail(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did:        "did:cm:test3",
+		model.Params_ExpireTime: strconv.FormatInt(mock.DefaultTxTime, 10),
+	}))
+
+	expireTime := int64(mock.DefaultTxTime + 100)
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did:        "did:cm:test3",
+		model.Params_ExpireTime: strconv.FormatInt(expireTime, 10),
+	}))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetBlackList, nil)
+	requireOK(t, resp)
+
+	var records []*model.BlackListRecord
+	require.Nil(t, json.Unmarshal(resp.Payload, &records))
+	require.Len(t, records, 3)
+	require.Equal(t, "did:cm:test1", records[0].Did)
+	require.Equal(t, model.BlackListReasonFraud, records[0].ReasonCode)
+	require.Equal(t, "fraud", records[0].Reason)
+	require.Equal(t, testCreatorSki, records[0].Operator)
+
+	m.BeginTx("", nil)
+	require.True(t, d.dal.isInBlackList("did:cm:test3"))
+	m.Rollback()
+
+	// 过期的黑名单记录不再生效
+	m.SetTxTime(expireTime)
+	m.BeginTx("", nil)
+	require.False(t, d.dal.isInBlackList("did:cm:test3"))
+	require.True(t, d.dal.isInBlackList("did:cm:test1"))
+	m.Rollback()
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_DeleteBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}))
+
+	resp = invokeAs(d, m, testUserSki, model.Method_GetBlackList, map[string]string{
+		model.Params_DidSearch: "did:cm:test1",
+	})
+	requireOK(t, resp)
+	require.Equal(t, "null", string(resp.Payload))
+
+	require.Len(t, m.Events(model.Topic_AddBlackList), 2)
+	require.Len(t, m.Events(model.Topic_DeleteBlackList), 1)
+}
+
+func TestUpdateDidDocument(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const did = "did:cm:user2"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+
+	doc := newTestDidDocument(t, did, k1)
+	requireOK(t, invokeAs(d, m, k1.ski, model.Method_AddDidDocument, map[string]string{
+		model.Params_DidDocument: string(doc.JsonRaw()),
+	}))
+
+	newDoc := newTestDidDocument(t, did, k2)
+	updateArgs := map[string]string{model.Params_DidDocument: string(newDoc.JsonRaw())}
+
+	// 不是DID的控制者不能更新
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_UpdateDidDocument, updateArgs),
+		model.ErrCode_PermissionDenied)
+
+	// 篡改后证明无效的DID Document不能更新
+	tampered := strings.Replace(string(newDoc.JsonRaw()), `"controller":["`+did+`"]`,
+		`"controller":["`+did+`","did:cm:other"]`, 1)
+	requireFailCode(t, invokeAs(d, m, k1.ski, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: tampered,
+	}), model.ErrCode_InvalidDidDocument)
+
+	// DID不存在时不能更新
+	requireFailCode(t, invokeAs(d, m, k1.ski, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: string(newTestDidDocument(t, "did:cm:user3", k2).JsonRaw()),
+	}), model.ErrCode_DidNotFound)
+
+	requireOK(t, invokeAs(d, m, k1.ski, model.Method_UpdateDidDocument, updateArgs))
+
+	// 旧公钥的索引被删除，新公钥指向该DID
+	oldDid, err := d.GetDidByPubkey(k1.pem)
+	require.Nil(t, err)
+	require.Empty(t, oldDid)
+
+	newDid, err := d.GetDidByPubkey(k2.pem)
+	require.Nil(t, err)
+	require.Equal(t, did, newDid)
+
+	// 旧公钥不再对应该DID，不能继续更新
+	requireFailCode(t, invokeAs(d, m, k1.ski, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: string(doc.JsonRaw()),
+	}), model.ErrCode_PermissionDenied)
+
+	// DID操作员可以更新其他DID的文档
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   model.Role_DidOperator,
+		model.Params_Member: testUserSki,
+	}))
+	requireOK(t, invokeAs(d, m, testUserSki, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: string(doc.JsonRaw()),
+	}))
+
+	oldDid, err = d.GetDidByPubkey(k1.pem)
+	require.Nil(t, err)
+	require.Equal(t, did, oldDid)
+
+	require.Len(t, m.Events(model.Topic_SetDidDocument), 3)
+}
+
+func TestUpdateDidDocumentSyncsAdminByDid(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const adminDid = "did:cm:admin1"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+
+	doc := newTestDidDocument(t, adminDid, k1)
+	requireOK(t, invokeAs(d, m, k1.ski, model.Method_AddDidDocument, map[string]string{
+		model.Params_DidDocument: string(doc.JsonRaw()),
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	}))
+
+	// 以DID注册的管理员轮换公钥后，旧公钥失去管理员权限，新公钥获得管理员权限
+	requireOK(t, invokeAs(d, m, k1.ski, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: string(newTestDidDocument(t, adminDid, k2).JsonRaw()),
+	}))
+
+	requireFailCode(t, invokeAs(d, m, k1.ski, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}), model.ErrCode_PermissionDenied)
+	requireOK(t, invokeAs(d, m, k2.ski, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetAdminList, nil)
+	requireOK(t, resp)
+
+	var admins []*model.AdminRecord
+	require.Nil(t, json.Unmarshal(resp.Payload, &admins))
+	require.Len(t, admins, 2)
+	require.Equal(t, k2.ski, admins[1].Ski)
+	require.Equal(t, adminDid, admins[1].Did)
+}
+
+func TestUpdateAdminDidDocumentPermission(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const adminDid = "did:cm:admin1"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+
+	doc := newTestDidDocument(t, adminDid, k1)
+	requireOK(t, invokeAs(d, m, k1.ski, model.Method_AddDidDocument, map[string]string{
+		model.Params_DidDocument: string(doc.JsonRaw()),
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	}))
+
+	// DID操作员不能更新以DID注册的管理员的文档，新公钥不会成为管理员
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   model.Role_DidOperator,
+		model.Params_Member: testUserSki,
+	}))
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: string(newTestDidDocument(t, adminDid, k2).JsonRaw()),
+	}), model.ErrCode_PermissionDenied)
+	require.True(t, d.IsAdmin(k1.ski))
+	require.False(t, d.IsAdmin(k2.ski))
+
+	// 开启治理后控制者新增的公钥不会直接成为管理员
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "2",
+	}))
+	requireOK(t, invokeAs(d, m, k1.ski, model.Method_UpdateDidDocument, map[string]string{
+		model.Params_DidDocument: string(newTestDidDocument(t, adminDid, k1, k2).JsonRaw()),
+	}))
+	require.True(t, d.IsAdmin(k1.ski))
+	require.False(t, d.IsAdmin(k2.ski))
+
+	// 通过提案重新以DID设置管理员后新公钥成为管理员
+	id := createProposal(t, d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	})
+	requireOK(t, approveProposal(d, m, testAdminSki, id))
+	require.True(t, d.IsAdmin(k2.ski))
+}
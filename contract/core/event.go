@@ -0,0 +1,128 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// 发送设置DID Document事件
+func emitSetDidDocumentEvent(did string, didDocument string) {
+	sdk.Instance.EmitEvent(model.Topic_SetDidDocument, []string{did, didDocument})
+}
+
+// 发送添加黑名单事件
+func emitAddBlackListEvent(dids []string) {
+	sdk.Instance.EmitEvent(model.Topic_AddBlackList, dids)
+}
+
+// 发送删除黑名单事件
+func emitDeleteBlackListEvent(dids []string) {
+	sdk.Instance.EmitEvent(model.Topic_DeleteBlackList, dids)
+}
+
+// 发送添加信任发行者事件
+func emitAddTrustIssuerListEvent(dids []string) {
+	sdk.Instance.EmitEvent(model.Topic_AddTrustIssuer, dids)
+}
+
+// 发送删除信任发行者事件
+func emitDeleteTrustIssuerEvent(dids []string) {
+	sdk.Instance.EmitEvent(model.Topic_DeleteTrustIssuer, dids)
+}
+
+// 发送认证下级签发者事件
+func emitAccreditIssuerEvent(did, accreditor string, record []byte) {
+	sdk.Instance.EmitEvent(model.Topic_AccreditIssuer, []string{did, accreditor, string(record)})
+}
+
+// 发送撤销下级签发者认证事件
+func emitRevokeAccreditationEvent(did, accreditor string) {
+	sdk.Instance.EmitEvent(model.Topic_RevokeAccreditation, []string{did, accreditor})
+}
+
+// 发送数据迁移事件
+func emitMigrateEvent(status []byte) {
+	sdk.Instance.EmitEvent(model.Topic_Migrate, []string{string(status)})
+}
+
+// 发送修改合约配置事件
+func emitSetContractConfigEvent(config []byte) {
+	sdk.Instance.EmitEvent(model.Topic_SetContractConfig, []string{string(config)})
+}
+
+// 发送设置管理员事件
+func emitSetAdminEvent(ski, did string) {
+	sdk.Instance.EmitEvent(model.Topic_SetAdmin, []string{ski, did})
+}
+
+// 发送删除管理员事件
+func emitDeleteAdminEvent(ski, did string) {
+	sdk.Instance.EmitEvent(model.Topic_DeleteAdmin, []string{ski, did})
+}
+
+// 发送授予角色事件
+func emitGrantRoleEvent(role, member string) {
+	sdk.Instance.EmitEvent(model.Topic_GrantRole, []string{role, member})
+}
+
+// 发送撤销角色事件
+func emitRevokeRoleEvent(role, member string) {
+	sdk.Instance.EmitEvent(model.Topic_RevokeRole, []string{role, member})
+}
+
+// 发送创建提案事件
+func emitCreateProposalEvent(id, action, proposer string) {
+	sdk.Instance.EmitEvent(model.Topic_CreateProposal, []string{id, action, proposer})
+}
+
+// 发送同意提案事件
+func emitApproveProposalEvent(id, approver string) {
+	sdk.Instance.EmitEvent(model.Topic_ApproveProposal, []string{id, approver})
+}
+
+// 发送执行提案事件
+func emitExecuteProposalEvent(id, action string) {
+	sdk.Instance.EmitEvent(model.Topic_ExecuteProposal, []string{id, action})
+}
+
+// 发送取消提案事件
+func emitCancelProposalEvent(id string) {
+	sdk.Instance.EmitEvent(model.Topic_CancelProposal, []string{id})
+}
+
+// 发送撤销VC事件
+func emitRevokeVcEvent(vcID string, record []byte) {
+	sdk.Instance.EmitEvent(model.Topic_RevokeVc, []string{vcID, string(record)})
+}
+
+// 发送暂停VC事件
+func emitSuspendVcEvent(vcId string, record []byte) {
+	sdk.Instance.EmitEvent(model.Topic_SuspendVc, []string{vcId, string(record)})
+}
+
+// 发送恢复VC事件
+func emitUnsuspendVcEvent(vcId string, record []byte) {
+	sdk.Instance.EmitEvent(model.Topic_UnsuspendVc, []string{vcId, string(record)})
+}
+
+// 发送设置VC模板事件
+func emitSetVcTemplateEvent(templateId string, vcTemplate []byte) {
+	sdk.Instance.EmitEvent(model.Topic_SetVcTemplate, []string{templateId, string(vcTemplate)})
+}
+
+// 发送记录VC签发日志事件
+func emitVcIssueLogEvent(vcId string, log []byte) {
+	sdk.Instance.EmitEvent(model.Topic_VcIssueLog, []string{vcId, string(log)})
+}
+
+// 发送设置状态列表事件
+func emitSetStatusListEvent(id, issuer string) {
+	sdk.Instance.EmitEvent(model.Topic_SetStatusList, []string{id, issuer})
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// AccreditIssuer 由具有认证权限的信任签发者认证下级签发者
+// @params did 被认证的签发者DID
+// @params templateIds 允许签发的VC模板ID列表，为空表示继承认证者的模板范围
+// @params delegable 被认证的签发者是否有权继续认证下级签发者
+// @params maxDepth 被认证的签发者可以向下认证的最大层数，0表示不限制（仍受上级限制）
+func (d *DidContract) AccreditIssuer(did string, templateIds []string, delegable bool, maxDepth int) error {
+	if maxDepth < 0 {
+		return model.NewError(model.ErrCode_InvalidParameter, "the max depth of accreditation can not be negative")
+	}
+
+	senderDid, err := d.dal.getSenderDid()
+	if err != nil {
+		return err
+	}
+
+	if senderDid == did {
+		return model.NewError(model.ErrCode_InvalidAccreditation, "the issuer can not accredit itself")
+	}
+
+	chain, err := d.getAccreditationChain(senderDid)
+	if err != nil {
+		return err
+	}
+
+	err = d.verifyAccreditationChain(chain)
+	if err != nil {
+		return err
+	}
+
+	accreditor := chain[0]
+	if !accreditor.Delegable {
+		return model.NewError(model.ErrCode_PermissionDenied, "the sender has no right to accredit issuers")
+	}
+
+	// 计算新签发者在认证链上还可以向下认证的层数，-1表示不限制
+	limit := -1
+	for i, issuer := range chain {
+		if issuer.Did == did {
+			return model.NewError(model.ErrCode_InvalidAccreditation,
+				"the did is already in the accreditation chain of the sender, did: [%s]", did)
+		}
+
+		if issuer.MaxDepth == 0 {
+			continue
+		}
+
+		remain := issuer.MaxDepth - (i + 1)
+		if remain < 0 {
+			return model.NewError(model.ErrCode_InvalidAccreditation, "exceeds the accreditation depth limit")
+		}
+
+		if limit < 0 || remain < limit {
+			limit = remain
+		}
+	}
+
+	if !delegable {
+		maxDepth = 0
+	} else if limit == 0 {
+		return model.NewError(model.ErrCode_InvalidAccreditation,
+			"the accreditation depth limit has been reached, the issuer can not be delegable")
+	} else if limit > 0 {
+		if maxDepth == 0 {
+			maxDepth = limit
+		}
+		if maxDepth > limit {
+			return model.NewError(model.ErrCode_InvalidAccreditation,
+				"the max depth of accreditation can not exceed %d", limit)
+		}
+	}
+
+	// 模板范围不能超出认证链上任何一级签发者的范围
+	if len(templateIds) == 0 {
+		templateIds = accreditor.TemplateIds
+	}
+
+	for _, id := range templateIds {
+		temp, err := d.dal.getVcTemplate(id)
+		if err != nil {
+			return err
+		}
+
+		if len(temp) == 0 {
+			return model.NewError(model.ErrCode_VcTemplateNotFound, "the vc template not found on chain, id: [%s]", id)
+		}
+
+		for _, issuer := range chain {
+			if !issuer.IsTrustedFor(id) {
+				return model.NewError(model.ErrCode_InvalidAccreditation,
+					"the vc template is out of the accreditor's scope, id: [%s]", id)
+			}
+		}
+	}
+
+	// 判断DID Doc是否已经上链
+	ok := d.dal.isDidDocExisting(did)
+	if !ok {
+		return model.NewError(model.ErrCode_DidNotFound, "the did's doc not found on chain, did: [%s]", did)
+	}
+
+	if d.dal.isInBlackList(did) {
+		return model.NewError(model.ErrCode_InBlackList, "the did is in the blacklist, did: [%s]", did)
+	}
+
+	// 已经是信任签发者的DID只能由原认证者更新
+	existing, err := d.dal.getTrustIssuer(did)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.Accreditor != senderDid {
+		return model.NewError(model.ErrCode_InvalidAccreditation, "the did is already a trusted issuer, did: [%s]", did)
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	record := model.NewAccreditedIssuer(did, senderDid, templateIds, delegable, maxDepth, myTime)
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putTrustIssuer(did, recordBytes)
+	if err != nil {
+		return err
+	}
+
+	emitAccreditIssuerEvent(did, senderDid, recordBytes)
+	return nil
+}
+
+// RevokeAccreditation 撤销对下级签发者的认证，只有认证者或签发者管理员可以操作
+// 撤销后该签发者认证的下级签发者的认证链也将失效
+// @params did 被撤销认证的签发者DID
+func (d *DidContract) RevokeAccreditation(did string) error {
+	issuer, err := d.dal.getTrustIssuer(did)
+	if err != nil {
+		return err
+	}
+
+	if issuer == nil {
+		return model.NewError(model.ErrCode_NotTrustedIssuer, "the did is not a trusted issuer")
+	}
+
+	if len(issuer.Accreditor) == 0 {
+		return model.NewError(model.ErrCode_InvalidAccreditation, "the issuer is not accredited by another issuer")
+	}
+
+	ok, err := d.hasSenderRole(model.Role_IssuerManager)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		senderDid, err := d.dal.getSenderDid()
+		if err != nil {
+			return err
+		}
+
+		if senderDid != issuer.Accreditor {
+			return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+		}
+	}
+
+	err = d.dal.deleteTrustIssuer(did)
+	if err != nil {
+		return err
+	}
+
+	emitRevokeAccreditationEvent(did, issuer.Accreditor)
+	return nil
+}
+
+// GetAccreditationChain 获取签发者的认证链
+// 返回的列表从签发者本身开始，依次为上级签发者，最后一个为管理员添加的信任签发者
+// @params did 签发者DID
+func (d *DidContract) GetAccreditationChain(did string) ([]*model.TrustIssuer, error) {
+	return d.getAccreditationChain(did)
+}
+
+// getAccreditationChain 从数据库中逐级查找签发者的认证链，任何一级缺失都会返回错误
+func (d *DidContract) getAccreditationChain(did string) ([]*model.TrustIssuer, error) {
+	return model.BuildAccreditationChain(did, d.dal.getTrustIssuer)
+}
+
+// verifyAccreditationChain 校验认证链上的每一级上级签发者：
+// 必须具有认证权限、下级所在层数不超过其限制，并且不在黑名单中
+func (d *DidContract) verifyAccreditationChain(chain []*model.TrustIssuer) error {
+	return model.VerifyAccreditationChain(chain, func(did string) (bool, error) {
+		return d.dal.isInBlackList(did), nil
+	})
+}
@@ -0,0 +1,190 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMidIssuerDid  = "did:cm:issuer2"
+	testMidIssuerSki  = "e1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+	testLeafIssuerDid = "did:cm:issuer3"
+	testLeafIssuerSki = "f1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+)
+
+// accreditIssuer 以指定发送者认证下级签发者
+func accreditIssuer(t *testing.T, d *DidContract, m *mock.SDK, sender, did string, templateIds []string,
+	delegable bool) protogo.Response {
+	args := map[string]string{model.Params_Did: did}
+	if len(templateIds) != 0 {
+		args[model.Params_VcTemplateIdList] = didListJson(t, templateIds...)
+	}
+	if delegable {
+		args[model.Params_Delegable] = "true"
+	}
+
+	return invokeAs(d, m, sender, model.Method_AccreditIssuer, args)
+}
+
+// getAccreditationChain 查询签发者的认证链
+func getAccreditationChain(t *testing.T, d *DidContract, m *mock.SDK, did string) []*model.TrustIssuer {
+	resp := invokeAs(d, m, testUserSki, model.Method_GetAccreditationChain, map[string]string{
+		model.Params_Did: did,
+	})
+	requireOK(t, resp)
+
+	var chain []*model.TrustIssuer
+	require.Nil(t, json.Unmarshal(resp.Payload, &chain))
+	return chain
+}
+
+// issueLogArgs 签发日志的调用参数
+func issueLogArgs(issuer, templateId, vcId string) map[string]string {
+	return map[string]string{
+		model.Params_Issuer:       issuer,
+		model.Params_Did:          testUserDid,
+		model.Params_VcTemplateId: templateId,
+		model.Params_VcId:         vcId,
+	}
+}
+
+// setupAccreditation 添加可以向下认证两层的根信任签发者，可以签发模板1和2
+func setupAccreditation(t *testing.T) (*DidContract, *mock.SDK) {
+	d, m := newTestContract(t, true)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, testMidIssuerDid, testMidIssuerSki)
+	seedDid(t, d, m, testLeafIssuerDid, testLeafIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	for _, id := range []string{"1", "2"} {
+		requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetVcTemplate,
+			setVcTemplateArgs(id, testVcTemplate)))
+	}
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddTrustIssuer, map[string]string{
+		model.Params_Did:              testIssuerDid,
+		model.Params_VcTemplateIdList: didListJson(t, "1", "2"),
+		model.Params_Delegable:        "true",
+		model.Params_MaxDepth:         "2",
+	}))
+
+	return d, m
+}
+
+func TestAccreditIssuerDepthAndScope(t *testing.T) {
+	d, m := setupAccreditation(t)
+
+	// 不是信任签发者不能认证
+	requireFail(t, accreditIssuer(t, d, m, testUserSki, testLeafIssuerDid, nil, false))
+	// 不能认证自己
+	requireFailCode(t, accreditIssuer(t, d, m, testIssuerSki, testIssuerDid, nil, false),
+		model.ErrCode_InvalidAccreditation)
+	// 不存在的模板和没有上链的DID不能认证
+	requireFailCode(t, accreditIssuer(t, d, m, testIssuerSki, testMidIssuerDid, []string{"3"}, true),
+		model.ErrCode_VcTemplateNotFound)
+	requireFailCode(t, accreditIssuer(t, d, m, testIssuerSki, "did:cm:unknown", nil, false),
+		model.ErrCode_DidNotFound)
+
+	// 被认证的签发者的层数限制继承自上级
+	requireOK(t, accreditIssuer(t, d, m, testIssuerSki, testMidIssuerDid, []string{"1"}, true))
+
+	chain := getAccreditationChain(t, d, m, testMidIssuerDid)
+	require.Len(t, chain, 2)
+	require.Equal(t, testMidIssuerDid, chain[0].Did)
+	require.Equal(t, testIssuerDid, chain[0].Accreditor)
+	require.Equal(t, 1, chain[0].MaxDepth)
+	require.Equal(t, testIssuerDid, chain[1].Did)
+
+	// 模板范围不能超出上级的范围
+	requireFailCode(t, accreditIssuer(t, d, m, testMidIssuerSki, testLeafIssuerDid, []string{"2"}, false),
+		model.ErrCode_InvalidAccreditation)
+	// 已达到层数限制，不能再认证有认证权限的下级
+	requireFailCode(t, accreditIssuer(t, d, m, testMidIssuerSki, testLeafIssuerDid, nil, true),
+		model.ErrCode_InvalidAccreditation)
+	// 认证链上已有的DID不能再被认证
+	requireFailCode(t, accreditIssuer(t, d, m, testMidIssuerSki, testIssuerDid, nil, false),
+		model.ErrCode_InvalidAccreditation)
+
+	// 未指定模板时继承认证者的模板范围
+	requireOK(t, accreditIssuer(t, d, m, testMidIssuerSki, testLeafIssuerDid, nil, false))
+
+	chain = getAccreditationChain(t, d, m, testLeafIssuerDid)
+	require.Len(t, chain, 3)
+	require.Equal(t, []string{"1"}, chain[0].TemplateIds)
+	require.False(t, chain[0].Delegable)
+
+	// 没有认证权限的签发者不能认证下级
+	requireFailCode(t, accreditIssuer(t, d, m, testLeafIssuerSki, testUserDid, nil, false),
+		model.ErrCode_PermissionDenied)
+
+	// 只能签发认证链上每一级都可以签发的模板
+	requireOK(t, invokeAs(d, m, testLeafIssuerSki, model.Method_VcIssueLog,
+		issueLogArgs(testLeafIssuerDid, "1", "vc1")))
+	requireFailCode(t, invokeAs(d, m, testLeafIssuerSki, model.Method_VcIssueLog,
+		issueLogArgs(testLeafIssuerDid, "2", "vc2")), model.ErrCode_NotTrustedIssuer)
+
+	// 上级签发者加入黑名单后下级的认证链失效
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: testMidIssuerDid,
+	}))
+	requireFailCode(t, invokeAs(d, m, testLeafIssuerSki, model.Method_VcIssueLog,
+		issueLogArgs(testLeafIssuerDid, "1", "vc3")), model.ErrCode_NotTrustedIssuer)
+
+	require.Len(t, m.Events(model.Topic_AccreditIssuer), 2)
+}
+
+func TestRevokeAccreditation(t *testing.T) {
+	d, m := setupAccreditation(t)
+
+	requireOK(t, accreditIssuer(t, d, m, testIssuerSki, testMidIssuerDid, nil, true))
+	requireOK(t, accreditIssuer(t, d, m, testMidIssuerSki, testLeafIssuerDid, nil, false))
+
+	// 已被认证的签发者只能由原认证者更新
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddTrustIssuer, map[string]string{
+		model.Params_Did:       testUserDid,
+		model.Params_Delegable: "true",
+	}))
+	requireFailCode(t, accreditIssuer(t, d, m, testUserSki, testLeafIssuerDid, nil, false),
+		model.ErrCode_InvalidAccreditation)
+
+	// 只有认证者或签发者管理员可以撤销认证
+	revokeArgs := map[string]string{model.Params_Did: testMidIssuerDid}
+	requireFailCode(t, invokeAs(d, m, testLeafIssuerSki, model.Method_RevokeAccreditation, revokeArgs),
+		model.ErrCode_PermissionDenied)
+
+	// 管理员直接添加的信任签发者不能撤销认证
+	requireFailCode(t, invokeAs(d, m, testCreatorSki, model.Method_RevokeAccreditation, map[string]string{
+		model.Params_Did: testIssuerDid,
+	}), model.ErrCode_InvalidAccreditation)
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeAccreditation, revokeArgs))
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeAccreditation, revokeArgs),
+		model.ErrCode_NotTrustedIssuer)
+
+	// 撤销后下级签发者的认证链断开，不能再签发
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_GetAccreditationChain, map[string]string{
+		model.Params_Did: testLeafIssuerDid,
+	}))
+	requireFailCode(t, invokeAs(d, m, testLeafIssuerSki, model.Method_VcIssueLog,
+		issueLogArgs(testLeafIssuerDid, "1", "vc1")), model.ErrCode_NotTrustedIssuer)
+
+	// 签发者管理员可以撤销任何上级认证的签发者
+	requireOK(t, accreditIssuer(t, d, m, testIssuerSki, testMidIssuerDid, nil, true))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_RevokeAccreditation, revokeArgs))
+
+	events := m.Events(model.Topic_RevokeAccreditation)
+	require.Len(t, events, 2)
+	require.Equal(t, []string{testMidIssuerDid, testIssuerDid}, events[0].Data)
+}
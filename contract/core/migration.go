@@ -0,0 +1,376 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// defaultMigrationBatch 每笔交易中每个迁移步骤最多处理的数据条数
+const defaultMigrationBatch = 500
+
+const (
+	// schemaVersionVcRevocation 吊销记录按签发者区分的数据版本，之前的版本只存储吊销的vcID
+	schemaVersionVcRevocation = 2
+	// schemaVersionVcIssueRecord 签发日志按签发者区分的数据版本，之前的版本只按vcID存储签发日志
+	schemaVersionVcIssueRecord = 3
+)
+
+// migration 合约数据迁移步骤
+type migration struct {
+	// version 迁移完成后的数据版本
+	version     int
+	description string
+	// run 从cursor处继续迁移，最多处理batch条数据，返回新的cursor，全部迁移完成时done为true
+	run func(d *DidContract, cursor string, batch int) (next string, done bool, err error)
+}
+
+// migrations 按版本从小到大排列的迁移步骤，新增存储格式时在末尾追加
+var migrations = []*migration{
+	{
+		version:     1,
+		description: "convert legacy blacklist, trust issuer and admin values to json records",
+		run:         migrateLegacyRecords,
+	},
+	{
+		version:     schemaVersionVcRevocation,
+		description: "move revoked vc ids to issuer-scoped revocation records",
+		run:         migrateRevokedVcs,
+	},
+	{
+		version:     schemaVersionVcIssueRecord,
+		description: "move vc issue logs to issuer-scoped issue records",
+		run:         migrateVcIssueLogs,
+	},
+}
+
+// latestSchemaVersion 当前合约代码对应的数据版本
+func latestSchemaVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].version
+}
+
+// Migrate 继续执行未完成的数据迁移（仅管理员有权限），数据量较大时需要多次调用
+// @params batch 每个迁移步骤本次最多处理的数据条数
+func (d *DidContract) Migrate(batch int) (*model.MigrationStatus, error) {
+	ok, err := isSenderAdmin(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	return d.runMigrations(batch)
+}
+
+// GetMigrationStatus 获取数据迁移状态
+func (d *DidContract) GetMigrationStatus() (*model.MigrationStatus, error) {
+	version, err := d.dal.getSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := d.dal.getMigrationCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MigrationStatus{
+		SchemaVersion: version,
+		LatestVersion: latestSchemaVersion(),
+		Cursor:        cursor,
+	}, nil
+}
+
+// isMigrating 判断是否有未完成的数据迁移，无法确定时按未完成处理
+func (d *DidContract) isMigrating() bool {
+	status, err := d.GetMigrationStatus()
+	if err != nil {
+		return true
+	}
+	return !status.IsDone()
+}
+
+// runMigrations 按顺序执行数据版本之后的迁移步骤，某一步骤未完成时保存进度并返回
+// @params batch 每个迁移步骤本次最多处理的数据条数，0表示使用默认值
+func (d *DidContract) runMigrations(batch int) (*model.MigrationStatus, error) {
+	if batch <= 0 {
+		batch = defaultMigrationBatch
+	}
+
+	status, err := d.GetMigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range migrations {
+		if m.version <= status.SchemaVersion {
+			continue
+		}
+
+		next, done, err := m.run(d, status.Cursor, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		if !done {
+			status.Cursor = next
+			err = d.dal.putMigrationCursor(next)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		status.SchemaVersion = m.version
+		status.Cursor = ""
+
+		err = d.dal.putSchemaVersion(m.version)
+		if err != nil {
+			return nil, err
+		}
+
+		err = d.dal.putMigrationCursor("")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+
+	emitMigrateEvent(statusBytes)
+	return status, nil
+}
+
+// legacyRecordStage 早期版本只存储字符串的数据，迁移时转换为JSON记录
+type legacyRecordStage struct {
+	key   string
+	parse func(value []byte) (interface{}, error)
+}
+
+var legacyRecordStages = []*legacyRecordStage{
+	{
+		key: keyBlackList,
+		parse: func(value []byte) (interface{}, error) {
+			return model.ParseBlackListRecord(value)
+		},
+	},
+	{
+		key: keyTrustIssuer,
+		parse: func(value []byte) (interface{}, error) {
+			return model.ParseTrustIssuer(value)
+		},
+	},
+	{
+		key: keyContractAdmin,
+		parse: func(value []byte) (interface{}, error) {
+			return model.ParseAdminRecord(value)
+		},
+	},
+}
+
+// migrateLegacyRecords 将黑名单、信任签发者和管理员的字符串数据转换为JSON记录
+// cursor的格式为 阶段序号/最后处理的field
+func migrateLegacyRecords(d *DidContract, cursor string, batch int) (string, bool, error) {
+	stage, lastField, err := parseMigrationCursor(cursor)
+	if err != nil {
+		return "", false, err
+	}
+
+	var processed int
+	for ; stage < len(legacyRecordStages); stage++ {
+		s := legacyRecordStages[stage]
+
+		// 从上一笔交易最后处理的field开始迭代，不重复扫描已经处理过的数据
+		iter, err := d.dal.newIteratorFromField(s.key, lastField)
+		if err != nil {
+			return "", false, err
+		}
+
+		for iter.HasNext() {
+			_, field, value, err := iter.Next()
+			if err != nil {
+				iter.Close()
+				return "", false, err
+			}
+
+			// 跳过上一笔交易最后处理的数据
+			if len(lastField) != 0 && field == lastField {
+				continue
+			}
+
+			if processed >= batch {
+				iter.Close()
+				return strconv.Itoa(stage) + "/" + lastField, false, nil
+			}
+
+			if len(value) != 0 && value[0] != '{' {
+				record, err := s.parse(value)
+				if err != nil {
+					iter.Close()
+					return "", false, err
+				}
+
+				recordBytes, err := json.Marshal(record)
+				if err != nil {
+					iter.Close()
+					return "", false, err
+				}
+
+				err = d.dal.Db().PutStateByte(s.key, field, recordBytes)
+				if err != nil {
+					iter.Close()
+					return "", false, err
+				}
+			}
+
+			lastField = field
+			processed++
+		}
+
+		iter.Close()
+		lastField = ""
+	}
+
+	return "", true, nil
+}
+
+// migrateRevokedVcs 将早期版本只存储vcID的吊销数据转换为按签发者区分的吊销记录
+// 签发者从VC签发日志中获取，没有签发日志的VC记录的签发者为空，对所有签发者的同名VC生效
+// cursor为最后处理的field
+func migrateRevokedVcs(d *DidContract, cursor string, batch int) (string, bool, error) {
+	// 从上一笔交易最后处理的field开始迭代，不重复扫描已经处理过的数据
+	iter, err := d.dal.newIteratorFromField(keyRevokeVc, cursor)
+	if err != nil {
+		return "", false, err
+	}
+	defer iter.Close()
+
+	var processed int
+	for iter.HasNext() {
+		_, field, value, err := iter.Next()
+		if err != nil {
+			return "", false, err
+		}
+
+		// 跳过上一笔交易最后处理的数据
+		if len(cursor) != 0 && field == cursor {
+			continue
+		}
+
+		if processed >= batch {
+			return cursor, false, nil
+		}
+
+		if len(value) != 0 {
+			vcId := string(value)
+			issuer, err := d.getLegacyVcIssuer(vcId)
+			if err != nil {
+				return "", false, err
+			}
+
+			recordBytes, err := json.Marshal(model.NewVcRevocation(issuer, vcId,
+				model.RevocationReasonUnspecified, "", "", 0))
+			if err != nil {
+				return "", false, err
+			}
+
+			err = d.dal.putVcRevocation(issuer, vcId, recordBytes)
+			if err != nil {
+				return "", false, err
+			}
+		}
+
+		err = d.dal.Db().DelState(keyRevokeVc, field)
+		if err != nil {
+			return "", false, err
+		}
+
+		cursor = field
+		processed++
+	}
+
+	return "", true, nil
+}
+
+// migrateVcIssueLogs 将早期版本只按vcID存储的签发日志转换为按签发者区分的签发日志
+// 吊销记录的迁移依赖早期版本的签发日志，需要在其之后执行
+// cursor为最后处理的field
+func migrateVcIssueLogs(d *DidContract, cursor string, batch int) (string, bool, error) {
+	// 从上一笔交易最后处理的field开始迭代，不重复扫描已经处理过的数据
+	iter, err := d.dal.newIteratorFromField(keyVcIssueLog, cursor)
+	if err != nil {
+		return "", false, err
+	}
+	defer iter.Close()
+
+	var processed int
+	for iter.HasNext() {
+		_, field, value, err := iter.Next()
+		if err != nil {
+			return "", false, err
+		}
+
+		// 跳过上一笔交易最后处理的数据
+		if len(cursor) != 0 && field == cursor {
+			continue
+		}
+
+		if processed >= batch {
+			return cursor, false, nil
+		}
+
+		var issueLog model.VcIssueLog
+		if len(value) != 0 && json.Unmarshal(value, &issueLog) == nil {
+			err = d.dal.putVcIssueLog(issueLog.Issuer, issueLog.VcId, value)
+			if err != nil {
+				return "", false, err
+			}
+		}
+
+		err = d.dal.Db().DelState(keyVcIssueLog, field)
+		if err != nil {
+			return "", false, err
+		}
+
+		cursor = field
+		processed++
+	}
+
+	return "", true, nil
+}
+
+// parseMigrationCursor 解析 阶段序号/field 格式的迁移进度，为空表示从头开始
+func parseMigrationCursor(cursor string) (int, string, error) {
+	if len(cursor) == 0 {
+		return 0, "", nil
+	}
+
+	parts := strings.SplitN(cursor, "/", 2)
+	if len(parts) != 2 {
+		return 0, "", model.NewError(model.ErrCode_InvalidParameter, "invalid migration cursor")
+	}
+
+	stage, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", model.NewError(model.ErrCode_InvalidParameter, "invalid migration cursor")
+	}
+
+	return stage, parts[1], nil
+}
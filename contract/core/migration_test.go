@@ -0,0 +1,238 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+// seedLegacyState 写入早期版本合约的数据：没有数据版本，黑名单、信任签发者、管理员和吊销的VC只存储字符串
+func seedLegacyState(m *mock.SDK) {
+	m.SetState(keyContractStatus, failedDidMethod, []byte("cm"))
+	m.SetState(keyContractStatus, failedEnableTrustIssuer, []byte("true"))
+
+	for _, id := range []string{"test1", "test2", "test3"} {
+		m.SetState(keyBlackList, id, []byte("did:cm:"+id))
+	}
+
+	record, _ := json.Marshal(model.NewBlackListRecord("did:cm:test4", model.BlackListReasonFraud,
+		"fraud", testCreatorSki, 1600000000, 0))
+	m.SetState(keyBlackList, "test4", record)
+
+	m.SetState(keyTrustIssuer, "issuer1", []byte(testIssuerDid))
+	m.SetState(keyContractAdmin, testAdminSki, []byte(testAdminSki))
+
+	for _, vcId := range []string{"vc-1", "vc-2", "vc-3"} {
+		m.SetState(keyRevokeVc, vcIdToKey(vcId), []byte(vcId))
+	}
+
+	for _, vcId := range []string{"vc-1", "vc-4"} {
+		log, _ := json.Marshal(model.NewVcIssueLog(testIssuerDid, testUserDid, "1", vcId, 1600000000))
+		m.SetState(keyVcIssueLog, vcIdToKey(vcId), log)
+	}
+}
+
+func migrationStatus(t *testing.T, payload []byte) *model.MigrationStatus {
+	var status model.MigrationStatus
+	require.Nil(t, json.Unmarshal(payload, &status))
+	return &status
+}
+
+func TestMigrateLegacyState(t *testing.T) {
+	m := mock.NewSDK(testCreatorSki)
+	sdk.Instance = m
+	seedLegacyState(m)
+
+	d := new(DidContract)
+
+	// 升级合约时每个迁移步骤只处理2条数据，迁移未完成
+	m.BeginTx("", map[string][]byte{model.Params_MigrationBatch: []byte("2")})
+	resp := d.UpgradeContract()
+	requireOK(t, resp)
+	m.Commit()
+
+	status := migrationStatus(t, resp.Payload)
+	require.Equal(t, 0, status.SchemaVersion)
+	require.Equal(t, latestSchemaVersion(), status.LatestVersion)
+	require.NotEmpty(t, status.Cursor)
+
+	// 升级时没有指定的配置保持不变
+	require.Equal(t, "cm", string(m.State(keyContractStatus, failedDidMethod)))
+
+	// 迁移未完成时不能修改合约状态
+	resp = invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test5",
+	})
+	requireFail(t, resp)
+
+	// 分多笔交易继续迁移
+	for i := 0; !status.IsDone(); i++ {
+		require.Less(t, i, 10)
+
+		resp = invokeAs(d, m, testCreatorSki, model.Method_Migrate, map[string]string{
+			model.Params_MigrationBatch: "2",
+		})
+		requireOK(t, resp)
+
+		status = migrationStatus(t, resp.Payload)
+	}
+
+	require.Equal(t, latestSchemaVersion(), status.SchemaVersion)
+	require.Empty(t, status.Cursor)
+
+	// 所有数据都已经转换为JSON记录，原有的结构化记录保持不变
+	for _, key := range []string{keyBlackList, keyTrustIssuer, keyContractAdmin} {
+		for _, field := range m.Fields(key) {
+			require.Equal(t, byte('{'), m.State(key, field)[0], "key: %s, field: %s", key, field)
+		}
+	}
+
+	record, err := d.dal.getBlackList("did:cm:test1")
+	require.Nil(t, err)
+	require.Equal(t, "did:cm:test1", record.Did)
+
+	record, err = d.dal.getBlackList("did:cm:test4")
+	require.Nil(t, err)
+	require.Equal(t, model.BlackListReasonFraud, record.ReasonCode)
+
+	issuer, err := d.dal.getTrustIssuer(testIssuerDid)
+	require.Nil(t, err)
+	require.Equal(t, testIssuerDid, issuer.Did)
+
+	admin, err := d.dal.getAdmin(testAdminSki)
+	require.Nil(t, err)
+	require.Equal(t, testAdminSki, admin.Ski)
+
+	// 吊销的VC转换为签发者未知的吊销记录，对所有签发者生效
+	require.Empty(t, m.Fields(keyRevokeVc))
+	revocation, err := d.GetVcRevocation(testIssuerDid, "vc-2")
+	require.Nil(t, err)
+	require.Equal(t, model.NewVcRevocation("", "vc-2", model.RevocationReasonUnspecified, "", "", 0), revocation)
+
+	// 有签发日志的吊销VC转换为该签发者的吊销记录
+	revocation, err = d.GetVcRevocation(testIssuerDid, "vc-1")
+	require.Nil(t, err)
+	require.Equal(t, testIssuerDid, revocation.Issuer)
+
+	// 签发日志转换为按签发者区分的签发日志
+	require.Empty(t, m.Fields(keyVcIssueLog))
+	issueLog, err := d.dal.getVcIssueLog(testIssuerDid, "vc-4")
+	require.Nil(t, err)
+	require.Equal(t, "vc-4", issueLog.VcId)
+
+	issuers, err := d.dal.getVcIssuers("vc-4")
+	require.Nil(t, err)
+	require.Equal(t, []string{testIssuerDid}, issuers)
+
+	// 迁移完成后可以正常修改合约状态
+	resp = invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test5",
+	})
+	requireOK(t, resp)
+
+	require.NotEmpty(t, m.Events(model.Topic_Migrate))
+}
+
+func TestInstallSetsLatestSchemaVersion(t *testing.T) {
+	d, _ := newTestContract(t, true)
+
+	status, err := d.GetMigrationStatus()
+	require.Nil(t, err)
+	require.True(t, status.IsDone())
+	require.Equal(t, latestSchemaVersion(), status.SchemaVersion)
+}
+
+func TestReadLegacyStateDuringMigration(t *testing.T) {
+	m := mock.NewSDK(testCreatorSki)
+	sdk.Instance = m
+	seedLegacyState(m)
+
+	d := new(DidContract)
+
+	m.BeginTx("", map[string][]byte{model.Params_MigrationBatch: []byte("1")})
+	resp := d.UpgradeContract()
+	requireOK(t, resp)
+	m.Commit()
+
+	vcJson := `{"@context":["https://www.w3.org/2018/credentials/v1"],"id":"vc-3",` +
+		`"type":["VerifiableCredential"],"credentialSubject":{"id":"` + testUserDid + `","name":"test"},` +
+		`"issuer":"` + testIssuerDid + `","issuanceDate":"2023-01-01T00:00:00Z",` +
+		`"expirationDate":"2033-01-01T00:00:00Z",` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"` + testIssuerDid + `#key-1","proofValue":"MEQ="}}`
+
+	// 每笔交易只迁移一条数据，迁移过程中早期版本的吊销数据和签发日志仍然有效
+	var sawLegacyRevocation, sawLegacyIssueLog bool
+	for status := migrationStatus(t, resp.Payload); !status.IsDone(); {
+		sawLegacyRevocation = sawLegacyRevocation || len(m.Fields(keyRevokeVc)) != 0
+		sawLegacyIssueLog = sawLegacyIssueLog || len(m.Fields(keyVcIssueLog)) != 0
+
+		requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_VerifyVc, map[string]string{
+			model.Params_VcJson: vcJson,
+		}), model.ErrCode_Revoked)
+
+		revocation, err := d.GetVcRevocation(testIssuerDid, "vc-1")
+		require.Nil(t, err)
+		require.NotNil(t, revocation)
+
+		// 有签发日志的吊销VC只对该签发者生效
+		revocation, err = d.GetVcRevocation("did:cm:other", "vc-1")
+		require.Nil(t, err)
+		require.Nil(t, revocation)
+
+		resp = invokeAs(d, m, testUserSki, model.Method_GetVcIssueLogs, nil)
+		requireOK(t, resp)
+
+		var logs []*model.VcIssueLog
+		require.Nil(t, json.Unmarshal(resp.Payload, &logs))
+		require.Len(t, logs, 2)
+
+		resp = invokeAs(d, m, testCreatorSki, model.Method_Migrate, map[string]string{
+			model.Params_MigrationBatch: "1",
+		})
+		requireOK(t, resp)
+		status = migrationStatus(t, resp.Payload)
+	}
+
+	require.True(t, sawLegacyRevocation)
+	require.True(t, sawLegacyIssueLog)
+}
+
+func TestUpgradeContractEmitsConfigEvent(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	upgrade := func(args map[string][]byte) {
+		m.BeginTx("", args)
+		requireOK(t, d.UpgradeContract())
+		m.Commit()
+	}
+
+	// 没有修改配置时不发送事件
+	upgrade(nil)
+	upgrade(map[string][]byte{model.Params_EnableTrustIssuer: []byte("false")})
+	require.Empty(t, m.Events(model.Topic_SetContractConfig))
+
+	// 修改是否启用信任签发者时与SetContractConfig一样发送事件
+	upgrade(map[string][]byte{model.Params_EnableTrustIssuer: []byte("true")})
+
+	events := m.Events(model.Topic_SetContractConfig)
+	require.Len(t, events, 1)
+
+	var config model.ContractConfig
+	require.Nil(t, json.Unmarshal([]byte(events[0].Data[0]), &config))
+	require.True(t, config.EnableTrustIssuer)
+	require.Equal(t, "cm", config.DidMethod)
+
+	upgrade(nil)
+	require.Len(t, m.Events(model.Topic_SetContractConfig), 1)
+}
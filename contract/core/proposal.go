@@ -0,0 +1,462 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// CreateProposal 管理员创建提案，提案者默认同意，同意人数达到法定人数时立即执行
+// @params action 提案要执行的合约方法
+// @params params 合约方法的参数
+// @params expireTime 过期时间，0表示使用默认有效期
+func (d *DidContract) CreateProposal(action string, params map[string]string, expireTime int64) (string, error) {
+	ok, err := isSenderAdmin(d)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	if !model.IsValidProposalAction(action) {
+		return "", model.NewError(model.ErrCode_InvalidParameter,
+			"the action can not be executed by proposal, action: [%s]", action)
+	}
+
+	proposer, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return "", err
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return "", err
+	}
+
+	if expireTime == 0 {
+		expireTime = myTime + model.DefaultProposalExpiration
+	}
+
+	if expireTime <= myTime {
+		return "", model.NewError(model.ErrCode_InvalidParameter,
+			"the expiration time must be later than the current time")
+	}
+
+	id, err := sdk.Instance.GetTxId()
+	if err != nil {
+		return "", err
+	}
+
+	if params == nil {
+		params = make(map[string]string)
+	}
+
+	proposal := model.NewProposal(id, action, params, proposer, myTime, expireTime)
+
+	emitCreateProposalEvent(id, action, proposer)
+
+	err = d.tryExecuteProposal(proposal, myTime)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// ApproveProposal 管理员同意提案，同意人数达到法定人数时执行提案
+// @params id 提案ID
+func (d *DidContract) ApproveProposal(id string) error {
+	ok, err := isSenderAdmin(d)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	proposal, err := d.dal.getProposal(id)
+	if err != nil {
+		return err
+	}
+
+	if proposal == nil {
+		return model.NewError(model.ErrCode_ProposalNotFound, "the proposal not found")
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	if proposal.IsExpired(myTime) {
+		return model.NewError(model.ErrCode_InvalidProposalStatus, "the proposal has expired")
+	}
+
+	if proposal.Status != model.ProposalStatus_Pending {
+		return model.NewError(model.ErrCode_InvalidProposalStatus,
+			"the proposal is not pending, status: [%s]", proposal.Status)
+	}
+
+	approver, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return err
+	}
+
+	if proposal.HasApproved(approver) {
+		return model.NewError(model.ErrCode_InvalidProposalStatus, "the proposal has been approved by the sender")
+	}
+
+	proposal.Approvals = append(proposal.Approvals, approver)
+
+	emitApproveProposalEvent(id, approver)
+
+	return d.tryExecuteProposal(proposal, myTime)
+}
+
+// CancelProposal 提案者取消未执行的提案
+// @params id 提案ID
+func (d *DidContract) CancelProposal(id string) error {
+	proposal, err := d.dal.getProposal(id)
+	if err != nil {
+		return err
+	}
+
+	if proposal == nil {
+		return model.NewError(model.ErrCode_ProposalNotFound, "the proposal not found")
+	}
+
+	senderPk, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return err
+	}
+
+	if senderPk != proposal.Proposer {
+		return model.NewError(model.ErrCode_PermissionDenied, "only the proposer can cancel the proposal")
+	}
+
+	if proposal.Status != model.ProposalStatus_Pending {
+		return model.NewError(model.ErrCode_InvalidProposalStatus,
+			"the proposal is not pending, status: [%s]", proposal.Status)
+	}
+
+	proposal.Status = model.ProposalStatus_Canceled
+
+	proposalBytes, err := json.Marshal(proposal)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putProposal(id, proposalBytes)
+	if err != nil {
+		return err
+	}
+
+	emitCancelProposalEvent(id)
+	return nil
+}
+
+// GetProposal 获取提案，过期未执行的提案状态为expired
+// @params id 提案ID
+func (d *DidContract) GetProposal(id string) (*model.Proposal, error) {
+	proposal, err := d.dal.getProposal(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if proposal == nil {
+		return nil, model.NewError(model.ErrCode_ProposalNotFound, "the proposal not found")
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if proposal.IsExpired(myTime) {
+		proposal.Status = model.ProposalStatus_Expired
+	}
+
+	return proposal, nil
+}
+
+// GetProposalList 获取提案列表
+// @params status 提案状态，为空表示查询所有提案
+func (d *DidContract) GetProposalList(status string, start int, count int) ([]*model.Proposal, error) {
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.dal.searchProposal(status, myTime, start, count)
+}
+
+// SetProposalQuorum 设置提案的法定人数（仅合约创建者有权限）
+// 法定人数大于1时启用提案治理，此后法定人数只能通过提案修改
+// @params quorum 法定人数
+func (d *DidContract) SetProposalQuorum(quorum int) error {
+	ok, err := isSenderCreator()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return model.NewError(model.ErrCode_PermissionDenied, "only the creator of the contract has permission")
+	}
+
+	err = d.requireNoGovernance()
+	if err != nil {
+		return err
+	}
+
+	return d.setProposalQuorum(quorum)
+}
+
+func (d *DidContract) setProposalQuorum(quorum int) error {
+	if quorum < 1 {
+		return model.NewError(model.ErrCode_InvalidParameter, "the quorum must be greater than 0")
+	}
+
+	// 合约创建者也拥有管理员权限
+	admins, err := d.countAdminIdentity()
+	if err != nil {
+		return err
+	}
+
+	if quorum > admins {
+		return model.NewError(model.ErrCode_InvalidParameter,
+			"the quorum can not exceed the number of admins, admins: [%d]", admins)
+	}
+
+	return d.dal.putProposalQuorum(quorum)
+}
+
+// GetProposalQuorum 获取提案的法定人数
+func (d *DidContract) GetProposalQuorum() (int, error) {
+	return d.dal.getProposalQuorum()
+}
+
+// isGovernanceEnabled 判断是否启用了提案治理
+func (d *DidContract) isGovernanceEnabled() bool {
+	quorum, err := d.dal.getProposalQuorum()
+	if err != nil {
+		// 无法确定时按启用处理
+		return true
+	}
+
+	return quorum > 1
+}
+
+// requireNoGovernance 启用提案治理后，管理员不能直接执行治理操作
+func (d *DidContract) requireNoGovernance() error {
+	if d.isGovernanceEnabled() {
+		return model.NewError(model.ErrCode_GovernanceRequired,
+			"the operation must be executed through a proposal when governance is enabled")
+	}
+	return nil
+}
+
+// tryExecuteProposal 统计仍然拥有管理员权限的同意者身份，达到法定人数时执行提案，最后保存提案
+func (d *DidContract) tryExecuteProposal(proposal *model.Proposal, now int64) error {
+	quorum, err := d.dal.getProposalQuorum()
+	if err != nil {
+		return err
+	}
+
+	creatorPk, err := sdk.Instance.GetCreatorPk()
+	if err != nil {
+		return err
+	}
+
+	// 同一DID的多个管理员公钥只计一次
+	approvals := make(map[string]struct{})
+	for _, ski := range proposal.Approvals {
+		identity, ok := d.getAdminIdentity(ski, creatorPk)
+		if ok {
+			approvals[identity] = struct{}{}
+		}
+	}
+
+	if len(approvals) >= quorum {
+		err = d.executeProposal(proposal)
+		if err != nil {
+			return fmt.Errorf("failed to execute the proposal, err: [%w]", err)
+		}
+
+		proposal.Status = model.ProposalStatus_Executed
+		proposal.ExecuteTime = now
+
+		emitExecuteProposalEvent(proposal.Id, proposal.Action)
+	}
+
+	proposalBytes, err := json.Marshal(proposal)
+	if err != nil {
+		return err
+	}
+
+	return d.dal.putProposal(proposal.Id, proposalBytes)
+}
+
+// executeProposal 按提案的参数执行合约方法，权限已由提案的法定人数保证
+func (d *DidContract) executeProposal(proposal *model.Proposal) error {
+	params := proposal.Params
+
+	switch proposal.Action {
+	case model.Method_AddBlackList:
+		dids, err := proposalStringList(params, model.Params_Did, model.Params_DidList)
+		if err != nil {
+			return err
+		}
+		reasonCode := proposalInt(params, model.Params_ReasonCode, model.BlackListReasonUnspecified)
+		expireTime, _ := strconv.ParseInt(params[model.Params_ExpireTime], 10, 64)
+		return d.addBlackList(dids, reasonCode, params[model.Params_Reason], expireTime)
+	case model.Method_DeleteBlackList:
+		dids, err := proposalStringList(params, model.Params_Did, model.Params_DidList)
+		if err != nil {
+			return err
+		}
+		return d.deleteBlackList(dids)
+	case model.Method_AddTrustIssuer:
+		dids, err := proposalStringList(params, model.Params_Did, model.Params_DidList)
+		if err != nil {
+			return err
+		}
+		var templateIds []string
+		if len(params[model.Params_VcTemplateIdList]) != 0 {
+			err = json.Unmarshal([]byte(params[model.Params_VcTemplateIdList]), &templateIds)
+			if err != nil {
+				return err
+			}
+		}
+		delegable := strings.ToLower(params[model.Params_Delegable]) == "true"
+		maxDepth := proposalInt(params, model.Params_MaxDepth, 0)
+		return d.addTrustIssuerList(dids, templateIds, delegable, maxDepth)
+	case model.Method_DeleteTrustIssuer:
+		dids, err := proposalStringList(params, model.Params_Did, model.Params_DidList)
+		if err != nil {
+			return err
+		}
+		return d.deleteTrustIssuer(dids)
+	case model.Method_SetAdmin:
+		if did, ok := params[model.Params_Did]; ok && len(did) != 0 {
+			return d.setAdminByDid(did)
+		}
+		ski, err := proposalString(params, model.Params_Ski)
+		if err != nil {
+			return err
+		}
+		return d.setAdmin(ski)
+	case model.Method_DeleteAdmin:
+		if did, ok := params[model.Params_Did]; ok && len(did) != 0 {
+			return d.deleteAdminByDid(did)
+		}
+		ski, err := proposalString(params, model.Params_Ski)
+		if err != nil {
+			return err
+		}
+		return d.deleteAdmin(ski)
+	case model.Method_SetVcTemplate:
+		id, err := proposalString(params, model.Params_VcTemplateId)
+		if err != nil {
+			return err
+		}
+		name, err := proposalString(params, model.Params_VcTemplateName)
+		if err != nil {
+			return err
+		}
+		version, err := proposalString(params, model.Params_VcTemplateVersion)
+		if err != nil {
+			return err
+		}
+		template, err := proposalString(params, model.Params_VcTemplate)
+		if err != nil {
+			return err
+		}
+		return d.setVcTemplate(id, name, version, template)
+	case model.Method_GrantRole:
+		role, err := proposalString(params, model.Params_Role)
+		if err != nil {
+			return err
+		}
+		member, err := proposalString(params, model.Params_Member)
+		if err != nil {
+			return err
+		}
+		return d.grantRole(role, member)
+	case model.Method_RevokeRole:
+		role, err := proposalString(params, model.Params_Role)
+		if err != nil {
+			return err
+		}
+		member, err := proposalString(params, model.Params_Member)
+		if err != nil {
+			return err
+		}
+		return d.revokeRole(role, member)
+	case model.Method_SetProposalQuorum:
+		quorum, err := proposalString(params, model.Params_Quorum)
+		if err != nil {
+			return err
+		}
+		num, err := strconv.Atoi(quorum)
+		if err != nil {
+			return err
+		}
+		return d.setProposalQuorum(num)
+	case model.Method_SetContractConfig:
+		return d.setContractConfig(params)
+	}
+
+	return model.NewError(model.ErrCode_InvalidParameter,
+		"the action can not be executed by proposal, action: [%s]", proposal.Action)
+}
+
+// proposalString 获取提案中的必填参数
+func proposalString(params map[string]string, key string) (string, error) {
+	v, ok := params[key]
+	if !ok || len(v) == 0 {
+		return "", model.NewError(model.ErrCode_InvalidParameter, "missing required parameters of proposal:'%s'", key)
+	}
+	return v, nil
+}
+
+// proposalStringList 获取提案中key1 单个string或者key2 []string类型的参数
+func proposalStringList(params map[string]string, key1, key2 string) ([]string, error) {
+	v, ok := params[key2]
+	if !ok || len(v) == 0 {
+		v1, err := proposalString(params, key1)
+		if err != nil {
+			return nil, model.NewError(model.ErrCode_InvalidParameter,
+				"missing required parameters of proposal:'%s' or '%s'", key1, key2)
+		}
+		return []string{v1}, nil
+	}
+
+	var list []string
+	err := json.Unmarshal([]byte(v), &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// proposalInt 获取提案中的可选参数 int类型，没有则返回defaultValue
+func proposalInt(params map[string]string, key string, defaultValue int) int {
+	num, err := strconv.Atoi(params[key])
+	if err != nil {
+		return defaultValue
+	}
+	return num
+}
@@ -0,0 +1,275 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+// createProposal 以指定管理员创建提案，返回提案ID
+func createProposal(t *testing.T, d *DidContract, m *mock.SDK, sender, action string,
+	params map[string]string) string {
+	paramsJson, err := json.Marshal(params)
+	require.Nil(t, err)
+
+	resp := invokeAs(d, m, sender, model.Method_CreateProposal, map[string]string{
+		model.Params_ProposalAction: action,
+		model.Params_ProposalParams: string(paramsJson),
+	})
+	requireOK(t, resp)
+
+	return string(resp.Payload)
+}
+
+// approveProposal 以指定管理员同意提案
+func approveProposal(d *DidContract, m *mock.SDK, sender, id string) protogo.Response {
+	return invokeAs(d, m, sender, model.Method_ApproveProposal, map[string]string{
+		model.Params_ProposalId: id,
+	})
+}
+
+// setupGovernance 设置两个管理员并将法定人数设置为3，即合约创建者和两个管理员都同意才能执行提案
+func setupGovernance(t *testing.T) (*DidContract, *mock.SDK) {
+	d, m := newTestContract(t, false)
+
+	for _, ski := range []string{testAdminSki, testUserSki} {
+		requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+			model.Params_Ski: ski,
+		}))
+	}
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "3",
+	}))
+
+	return d, m
+}
+
+func TestDeleteAdminKeepsQuorum(t *testing.T) {
+	d, m := setupGovernance(t)
+
+	// 删除管理员后管理员数量少于法定人数，提案执行失败
+	id := createProposal(t, d, m, testCreatorSki, model.Method_DeleteAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	})
+	requireOK(t, approveProposal(d, m, testAdminSki, id))
+	requireFailCode(t, approveProposal(d, m, testUserSki, id), model.ErrCode_InvalidParameter)
+	require.True(t, d.IsAdmin(testAdminSki))
+
+	// 先降低法定人数，再删除管理员
+	quorumId := createProposal(t, d, m, testCreatorSki, model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "2",
+	})
+	requireOK(t, approveProposal(d, m, testAdminSki, quorumId))
+	requireOK(t, approveProposal(d, m, testUserSki, quorumId))
+
+	quorum, err := d.GetProposalQuorum()
+	require.Nil(t, err)
+	require.Equal(t, 2, quorum)
+
+	requireOK(t, approveProposal(d, m, testUserSki, id))
+	require.False(t, d.IsAdmin(testAdminSki))
+
+	// 只剩合约创建者和一个管理员，不能再删除
+	id = createProposal(t, d, m, testCreatorSki, model.Method_DeleteAdmin, map[string]string{
+		model.Params_Ski: testUserSki,
+	})
+	requireFailCode(t, approveProposal(d, m, testUserSki, id), model.ErrCode_InvalidParameter)
+	require.True(t, d.IsAdmin(testUserSki))
+
+	// 管理员仍然可以继续治理
+	id = createProposal(t, d, m, testUserSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	})
+	requireOK(t, approveProposal(d, m, testCreatorSki, id))
+	require.True(t, d.dal.isInBlackList("did:cm:test1"))
+}
+
+// getProposal 查询提案
+func getProposal(t *testing.T, d *DidContract, m *mock.SDK, id string) *model.Proposal {
+	resp := invokeAs(d, m, testUserSki, model.Method_GetProposal, map[string]string{
+		model.Params_ProposalId: id,
+	})
+	requireOK(t, resp)
+
+	var proposal model.Proposal
+	require.Nil(t, json.Unmarshal(resp.Payload, &proposal))
+	return &proposal
+}
+
+func TestProposalQuorum(t *testing.T) {
+	d, m := setupGovernance(t)
+
+	// 启用提案治理后不能直接执行管理操作
+	requireFailCode(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: testIssuerSki,
+	}), model.ErrCode_GovernanceRequired)
+
+	// 只有管理员可以创建提案，提案的操作必须可以通过提案执行
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_CreateProposal, map[string]string{
+		model.Params_ProposalAction: model.Method_AddBlackList,
+	}), model.ErrCode_PermissionDenied)
+	requireFailCode(t, invokeAs(d, m, testCreatorSki, model.Method_CreateProposal, map[string]string{
+		model.Params_ProposalAction: model.Method_VcIssueLog,
+	}), model.ErrCode_InvalidParameter)
+
+	id := createProposal(t, d, m, testAdminSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: testIssuerSki,
+	})
+
+	// 提案者默认同意，不能重复同意
+	requireFailCode(t, approveProposal(d, m, testAdminSki, id), model.ErrCode_InvalidProposalStatus)
+	requireFailCode(t, approveProposal(d, m, testIssuerSki, id), model.ErrCode_PermissionDenied)
+	requireFailCode(t, approveProposal(d, m, testAdminSki, "unknown"), model.ErrCode_ProposalNotFound)
+
+	// 未达到法定人数时不执行
+	requireOK(t, approveProposal(d, m, testUserSki, id))
+	require.Equal(t, model.ProposalStatus_Pending, getProposal(t, d, m, id).Status)
+	require.False(t, d.IsAdmin(testIssuerSki))
+
+	requireOK(t, approveProposal(d, m, testCreatorSki, id))
+	proposal := getProposal(t, d, m, id)
+	require.Equal(t, model.ProposalStatus_Executed, proposal.Status)
+	require.Equal(t, []string{testAdminSki, testUserSki, testCreatorSki}, proposal.Approvals)
+	require.True(t, d.IsAdmin(testIssuerSki))
+
+	// 已执行的提案不能再同意或取消
+	requireFailCode(t, approveProposal(d, m, testIssuerSki, id), model.ErrCode_InvalidProposalStatus)
+	requireFailCode(t, invokeAs(d, m, testAdminSki, model.Method_CancelProposal, map[string]string{
+		model.Params_ProposalId: id,
+	}), model.ErrCode_InvalidProposalStatus)
+
+	require.Len(t, m.Events(model.Topic_ExecuteProposal), 1)
+}
+
+func TestProposalExpiration(t *testing.T) {
+	d, m := setupGovernance(t)
+
+	// 过期时间不能早于当前时间
+	requireFailCode(t, invokeAs(d, m, testCreatorSki, model.Method_CreateProposal, map[string]string{
+		model.Params_ProposalAction: model.Method_AddBlackList,
+		model.Params_ExpireTime:     strconv.FormatInt(mock.DefaultTxTime, 10),
+	}), model.ErrCode_InvalidParameter)
+
+	paramsJson, err := json.Marshal(map[string]string{model.Params_Did: "did:cm:test1"})
+	require.Nil(t, err)
+
+	expireTime := int64(mock.DefaultTxTime + 100)
+	resp := invokeAs(d, m, testCreatorSki, model.Method_CreateProposal, map[string]string{
+		model.Params_ProposalAction: model.Method_AddBlackList,
+		model.Params_ProposalParams: string(paramsJson),
+		model.Params_ExpireTime:     strconv.FormatInt(expireTime, 10),
+	})
+	requireOK(t, resp)
+	id := string(resp.Payload)
+
+	requireOK(t, approveProposal(d, m, testAdminSki, id))
+
+	// 过期后不能再同意，查询时状态为已过期
+	m.SetTxTime(expireTime)
+	requireFailCode(t, approveProposal(d, m, testUserSki, id), model.ErrCode_InvalidProposalStatus)
+	require.Equal(t, model.ProposalStatus_Expired, getProposal(t, d, m, id).Status)
+	require.False(t, d.dal.isInBlackList("did:cm:test1"))
+}
+
+func TestCancelProposal(t *testing.T) {
+	d, m := setupGovernance(t)
+
+	id := createProposal(t, d, m, testAdminSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	})
+
+	cancelArgs := map[string]string{model.Params_ProposalId: id}
+
+	// 只有提案者可以取消提案
+	requireFailCode(t, invokeAs(d, m, testCreatorSki, model.Method_CancelProposal, cancelArgs),
+		model.ErrCode_PermissionDenied)
+	requireOK(t, invokeAs(d, m, testAdminSki, model.Method_CancelProposal, cancelArgs))
+	require.Equal(t, model.ProposalStatus_Canceled, getProposal(t, d, m, id).Status)
+
+	// 取消后不能再同意或重复取消
+	requireFailCode(t, approveProposal(d, m, testUserSki, id), model.ErrCode_InvalidProposalStatus)
+	requireFailCode(t, invokeAs(d, m, testAdminSki, model.Method_CancelProposal, cancelArgs),
+		model.ErrCode_InvalidProposalStatus)
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetProposalList, map[string]string{
+		model.Params_ProposalStatus: model.ProposalStatus_Canceled,
+	})
+	requireOK(t, resp)
+
+	var proposals []*model.Proposal
+	require.Nil(t, json.Unmarshal(resp.Payload, &proposals))
+	require.Len(t, proposals, 1)
+	require.Equal(t, id, proposals[0].Id)
+
+	require.Len(t, m.Events(model.Topic_CancelProposal), 1)
+}
+
+func TestProposalApprovalByDidAdmin(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	const adminDid = "did:cm:admin1"
+
+	k1 := newTestKey(t)
+	k2 := newTestKey(t)
+	putTestDidDocument(t, d, m, newTestDidDocument(t, adminDid, k1, k2))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "3",
+	}))
+
+	// 以DID注册的管理员的两个公钥视为同一身份，只计一次同意
+	id := createProposal(t, d, m, k1.ski, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	})
+	requireOK(t, approveProposal(d, m, k2.ski, id))
+	requireOK(t, approveProposal(d, m, testAdminSki, id))
+	require.Equal(t, model.ProposalStatus_Pending, getProposal(t, d, m, id).Status)
+
+	requireOK(t, approveProposal(d, m, testCreatorSki, id))
+	require.Equal(t, model.ProposalStatus_Executed, getProposal(t, d, m, id).Status)
+	require.True(t, d.dal.isInBlackList("did:cm:test1"))
+
+	// 删除以DID注册的管理员后，该DID的所有公钥都不能再同意提案
+	id = createProposal(t, d, m, k1.ski, model.Method_DeleteBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	})
+	requireOK(t, approveProposal(d, m, testAdminSki, id))
+
+	adminId := createProposal(t, d, m, testCreatorSki, model.Method_SetProposalQuorum, map[string]string{
+		model.Params_Quorum: "2",
+	})
+	requireOK(t, approveProposal(d, m, testAdminSki, adminId))
+	requireOK(t, approveProposal(d, m, k2.ski, adminId))
+
+	adminId = createProposal(t, d, m, testCreatorSki, model.Method_DeleteAdmin, map[string]string{
+		model.Params_Did: adminDid,
+	})
+	requireOK(t, approveProposal(d, m, testAdminSki, adminId))
+	require.False(t, d.IsAdmin(k1.ski))
+	require.False(t, d.IsAdmin(k2.ski))
+
+	requireFailCode(t, approveProposal(d, m, k2.ski, id), model.ErrCode_PermissionDenied)
+	require.Equal(t, model.ProposalStatus_Pending, getProposal(t, d, m, id).Status)
+
+	requireOK(t, approveProposal(d, m, testCreatorSki, id))
+	require.False(t, d.dal.isInBlackList("did:cm:test1"))
+}
@@ -0,0 +1,388 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// RequireString 必须要有参数 string 类型
+// @param key
+// @return string
+// @return error
+func RequireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return "", model.NewError(model.ErrCode_InvalidParameter, "missing required parameters:'%s'", key)
+	}
+	return string(b), nil
+}
+
+// RequireBytes 必须要有参数 []bytes 类型
+// @param key
+// @return []byte
+// @return error
+func RequireBytes(key string) ([]byte, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return nil, model.NewError(model.ErrCode_InvalidParameter, "missing required parameters:'%s'", key)
+	}
+	return b, nil
+}
+
+// RequireBool 必须要有参数 Bool类型
+// @param key
+// @return string
+// @return error
+func RequireBool(key string) (bool, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return false, model.NewError(model.ErrCode_InvalidParameter, "missing required parameters:'%s'", key)
+	}
+
+	str := string(b)
+
+	switch strings.ToLower(str) {
+	case "false":
+		return false, nil
+	case "true":
+		return true, nil
+	default:
+		return false, model.NewError(model.ErrCode_InvalidParameter, "parameter error of Boolean type")
+	}
+}
+
+// OptionBool 获取可选参数 Bool类型，没有则返回defaultValue
+func OptionBool(key string, defaultValue bool) (bool, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return defaultValue, nil
+	}
+
+	return RequireBool(key)
+}
+
+// RequireStringList 必须要有参数key1 单个string或者key2 []string类型
+// @param key
+// @return []string
+// @return error
+func RequireStringList(key1, key2 string) ([]string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key2]
+	if !ok || len(b) == 0 {
+		b1, ok1 := args[key1]
+		if !ok1 || len(b1) == 0 {
+			return nil, model.NewError(model.ErrCode_InvalidParameter,
+				"missing required parameters:'%s' or '%s'", key1, key2)
+		}
+		return []string{string(b1)}, nil
+	}
+
+	var didSlice []string
+	err := json.Unmarshal(b, &didSlice)
+	if err != nil {
+		return nil, err
+	}
+
+	return didSlice, nil
+}
+
+// OptionStringList 获取可选参数 []string类型（json数组），没有则返回nil
+func OptionStringList(key string) ([]string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return nil, nil
+	}
+
+	var list []string
+	err := json.Unmarshal(b, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// OptionStringMap 获取可选参数 map[string]string类型（json对象），没有则返回nil
+func OptionStringMap(key string) (map[string]string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return nil, nil
+	}
+
+	var m map[string]string
+	err := json.Unmarshal(b, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ReturnError 封装error为Response，error带有错误码时在消息前加上错误码，见model.FormatErrorMessage
+// @param err
+// @return Response
+func ReturnError(err error) protogo.Response {
+	return sdk.Error(model.FormatErrorMessage(err))
+}
+
+// Return 封装返回Bool类型为Response，如果有error则忽略bool，封装error
+// @param err
+// @return Response
+func Return(err error) protogo.Response {
+	if err != nil {
+		return ReturnError(err)
+	}
+	return sdk.SuccessResponse
+}
+
+// ReturnString 封装返回string类型为Response，如果有error则忽略str，封装error
+// @param str
+// @param err
+// @return Response
+func ReturnString(str string, err error) protogo.Response {
+	if err != nil {
+		return ReturnError(err)
+	}
+	return sdk.Success([]byte(str))
+}
+
+// ReturnBytes 封装返回[]byte类型为Response，如果有error则忽略str，封装error
+func ReturnBytes(str []byte, err error) protogo.Response {
+	if err != nil {
+		return ReturnError(err)
+	}
+	return sdk.Success(str)
+}
+
+// ReturnBool 封装返回bool类型为Response，如果有error则忽略bool，封装error
+func ReturnBool(b bool, e error) protogo.Response {
+	if e != nil {
+		return ReturnError(e)
+	}
+	if b {
+		return sdk.Success([]byte("true"))
+	}
+	return sdk.Success([]byte("false"))
+}
+
+// ReturnJson 封装返回interface类型为json string Response
+// @param data
+// @return Response
+func ReturnJson(data interface{}, err error) protogo.Response {
+	if err != nil {
+		return ReturnError(err)
+	}
+	standardsBytes, err := json.Marshal(data)
+	if err != nil {
+		return ReturnError(err)
+	}
+	return sdk.Success(standardsBytes)
+}
+
+// OptionInt 获取可选参数 int类型，没有则返回defaultValue
+func OptionInt(key string, defaultValue int) int {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return defaultValue
+	}
+	num, err := strconv.Atoi(string(b))
+	if err != nil {
+		return defaultValue
+	}
+	return num
+}
+
+// OptionInt64 获取可选参数 int64类型，没有则返回defaultValue
+func OptionInt64(key string, defaultValue int64) int64 {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return defaultValue
+	}
+	num, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return num
+}
+
+func isSenderCreator() (bool, error) {
+	createrPk, err := sdk.Instance.GetCreatorPk()
+	if err != nil {
+		return false, err
+	}
+
+	senderPk, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return false, err
+	}
+
+	if createrPk == senderPk {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func isSenderAdmin(d *DidContract) (bool, error) {
+	senderPk, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return false, err
+	}
+
+	createrPk, err := sdk.Instance.GetCreatorPk()
+	if err != nil {
+		return false, err
+	}
+
+	return (d.IsAdmin(senderPk)) || (senderPk == createrPk), nil
+}
+
+// hasSenderRole 判断交易发送者是否拥有指定角色
+// 未启用提案治理时，管理员和合约创建者拥有所有角色；其余发送者按公钥SKI或其对应的DID查找授权记录
+// @params role 角色名称
+func (d *DidContract) hasSenderRole(role string) (bool, error) {
+	if !d.isGovernanceEnabled() {
+		ok, err := isSenderAdmin(d)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	senderPk, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return false, err
+	}
+
+	if d.dal.hasRole(role, senderPk) {
+		return true, nil
+	}
+
+	senderDid, err := d.dal.getSenderDid()
+	if err != nil || len(senderDid) == 0 {
+		return false, nil
+	}
+
+	return d.dal.hasRole(role, senderDid), nil
+}
+
+func (d *DidContract) isSenderTrustIssuer() bool {
+	enableTrustIssuer, _ := d.dal.getEnableTrustIssuer()
+	if enableTrustIssuer != "true" {
+		return true
+	}
+
+	did, err := d.dal.getSenderDid()
+	if err != nil {
+		return false
+	}
+
+	chain, err := d.getAccreditationChain(did)
+	if err != nil {
+		return false
+	}
+
+	return d.verifyAccreditationChain(chain) == nil
+}
+
+// isTrustIssuer 判断签发者是否可以签发指定模板的VC
+// 由上级签发者认证的签发者需要整条认证链有效，且链上每一级都可以签发该模板
+// @params did 签发者DID
+// @params templateId VC模板ID，受模板范围限制的签发者只能签发范围内的模板
+func (d *DidContract) isTrustIssuer(did, templateId string) bool {
+
+	enableTrustIssuer, _ := d.dal.getEnableTrustIssuer()
+	if enableTrustIssuer != "true" {
+		return true
+	}
+
+	chain, err := d.getAccreditationChain(did)
+	if err != nil {
+		return false
+	}
+
+	if d.verifyAccreditationChain(chain) != nil {
+		return false
+	}
+
+	return model.IsChainTrustedFor(chain, templateId)
+}
+
+func isInList(str string, list []string) bool {
+	for _, k := range list {
+		if k == str {
+			return true
+		}
+	}
+	return false
+}
+
+// getLegacyVcRevocation 吊销数据迁移完成前，从早期版本只存储vcID的吊销数据获取吊销记录，VC未被吊销时返回nil
+// 与迁移后的结果一致：有签发日志时只对该签发者生效，没有签发日志时对所有签发者生效
+func (d *DidContract) getLegacyVcRevocation(issuer, vcId string) (*model.VcRevocation, error) {
+	version, err := d.dal.getSchemaVersion()
+	if err != nil || version >= schemaVersionVcRevocation {
+		return nil, err
+	}
+
+	// 吊销数据的field不区分部分字符，需要核对VC编号
+	value, err := d.dal.getLegacyRevokedVc(vcId)
+	if err != nil || string(value) != vcId {
+		return nil, err
+	}
+
+	legacyIssuer, err := d.getLegacyVcIssuer(vcId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(legacyIssuer) != 0 && legacyIssuer != issuer {
+		return nil, nil
+	}
+
+	return model.NewVcRevocation(legacyIssuer, vcId, model.RevocationReasonUnspecified, "", "", 0), nil
+}
+
+// getLegacyVcIssuer 从早期版本合约的签发日志获取VC的签发者，没有签发日志时返回空字符串，仅用于数据迁移和迁移完成前的查询
+func (d *DidContract) getLegacyVcIssuer(vcId string) (string, error) {
+	// 查找颁发记录
+	log, err := d.dal.getLegacyVcIssueLog(vcId)
+	if err != nil || len(log) == 0 {
+		return "", err
+	}
+
+	var issueLog model.VcIssueLog
+	err = json.Unmarshal(log, &issueLog)
+	if err != nil {
+		return "", err
+	}
+
+	// 签发日志的field不区分部分字符，需要核对VC编号
+	if issueLog.VcId != vcId {
+		return "", nil
+	}
+
+	return issueLog.Issuer, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// GrantRole 授予角色（仅管理员有权限，启用提案治理后需要通过提案执行）
+// @params role 角色名称
+// @params member 成员的公钥SKI（与GetSenderPk()保持一致）或DID
+func (d *DidContract) GrantRole(role, member string) error {
+	ok, err := isSenderAdmin(d)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	err = d.requireNoGovernance()
+	if err != nil {
+		return err
+	}
+
+	return d.grantRole(role, member)
+}
+
+func (d *DidContract) grantRole(role, member string) error {
+	err := d.checkRoleMember(role, member)
+	if err != nil {
+		return err
+	}
+
+	operator, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return err
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	recordBytes, err := json.Marshal(model.NewRoleMember(role, member, operator, myTime))
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putRoleMember(role, member, recordBytes)
+	if err != nil {
+		return err
+	}
+
+	emitGrantRoleEvent(role, member)
+	return nil
+}
+
+// RevokeRole 撤销角色（仅管理员有权限，启用提案治理后需要通过提案执行）
+// @params role 角色名称
+// @params member 成员的公钥SKI或DID
+func (d *DidContract) RevokeRole(role, member string) error {
+	ok, err := isSenderAdmin(d)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	err = d.requireNoGovernance()
+	if err != nil {
+		return err
+	}
+
+	return d.revokeRole(role, member)
+}
+
+func (d *DidContract) revokeRole(role, member string) error {
+	if !model.IsValidRole(role) {
+		return model.NewError(model.ErrCode_InvalidParameter, "invalid role: [%s]", role)
+	}
+
+	if !d.dal.hasRole(role, member) {
+		return model.NewError(model.ErrCode_InvalidParameter, "the member does not have the role")
+	}
+
+	err := d.dal.deleteRoleMember(role, member)
+	if err != nil {
+		return err
+	}
+
+	emitRevokeRoleEvent(role, member)
+	return nil
+}
+
+// GetRoleList 获取角色成员列表
+// @params role 角色名称，为空表示查询所有角色
+func (d *DidContract) GetRoleList(role string, start int, count int) ([]*model.RoleMember, error) {
+	if len(role) != 0 && !model.IsValidRole(role) {
+		return nil, model.NewError(model.ErrCode_InvalidParameter, "invalid role: [%s]", role)
+	}
+
+	return d.dal.searchRoleMember(role, start, count)
+}
+
+// checkRoleMember 校验角色名称和成员，DID成员必须已经上链，SKI成员必须是十六进制编码
+func (d *DidContract) checkRoleMember(role, member string) error {
+	if !model.IsValidRole(role) {
+		return model.NewError(model.ErrCode_InvalidParameter, "invalid role: [%s]", role)
+	}
+
+	if len(member) == 0 {
+		return model.NewError(model.ErrCode_InvalidParameter, "the member of role can not be empty")
+	}
+
+	if model.GetRoleMemberType(member) == model.RoleMemberType_Did {
+		if !d.dal.isDidDocExisting(member) {
+			return model.NewError(model.ErrCode_DidNotFound, "the did's doc not found on chain, did: [%s]", member)
+		}
+		return nil
+	}
+
+	_, err := hex.DecodeString(member)
+	if err != nil {
+		return model.NewError(model.ErrCode_InvalidParameter, "the ski of member must be hex encoded")
+	}
+
+	return nil
+}
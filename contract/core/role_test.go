@@ -0,0 +1,106 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolePermission(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	addBlackList := map[string]string{model.Params_Did: "did:cm:test1"}
+
+	// 普通用户没有黑名单管理权限
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_AddBlackList, addBlackList))
+
+	// 普通用户不能授予角色
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   model.Role_BlackListManager,
+		model.Params_Member: testUserSki,
+	}))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   model.Role_BlackListManager,
+		model.Params_Member: testUserSki,
+	}))
+
+	requireOK(t, invokeAs(d, m, testUserSki, model.Method_AddBlackList, addBlackList))
+
+	// 角色只授予对应的权限
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetRoleList, map[string]string{
+		model.Params_Role: model.Role_BlackListManager,
+	})
+	requireOK(t, resp)
+
+	var members []*model.RoleMember
+	require.Nil(t, json.Unmarshal(resp.Payload, &members))
+	require.Len(t, members, 1)
+	require.Equal(t, testUserSki, members[0].Member)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_RevokeRole, map[string]string{
+		model.Params_Role:   model.Role_BlackListManager,
+		model.Params_Member: testUserSki,
+	}))
+
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_DeleteBlackList, addBlackList))
+
+	// 不存在的角色
+	requireFail(t, invokeAs(d, m, testCreatorSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   "unknown",
+		model.Params_Member: testUserSki,
+	}))
+}
+
+func TestAdminPermission(t *testing.T) {
+	d, m := newTestContract(t, false)
+
+	// 只有合约创建者可以设置管理员
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	}))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SetAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	}))
+
+	// 未启用提案治理时管理员拥有所有角色
+	requireOK(t, invokeAs(d, m, testAdminSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test1",
+	}))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetAdminList, nil)
+	requireOK(t, resp)
+
+	var admins []*model.AdminRecord
+	require.Nil(t, json.Unmarshal(resp.Payload, &admins))
+	// 合约创建者也在管理员列表中
+	require.Len(t, admins, 2)
+	require.True(t, admins[0].IsCreator)
+	require.Equal(t, testAdminSki, admins[1].Ski)
+
+	requireFail(t, invokeAs(d, m, testAdminSki, model.Method_DeleteAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	}))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_DeleteAdmin, map[string]string{
+		model.Params_Ski: testAdminSki,
+	}))
+
+	requireFail(t, invokeAs(d, m, testAdminSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: "did:cm:test2",
+	}))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// SetStatusList 发布或更新状态列表凭证，只有状态列表凭证的签发者可以发布，已发布的列表只能由原签发者更新，吊销列表只能新增置位
+// @params listJson 状态列表凭证的json字符串
+func (d *DidContract) SetStatusList(listJson string) error {
+	listVc, err := model.NewVerifiableCredential(listJson)
+	if err != nil {
+		return model.NewError(model.ErrCode_InvalidParameter, "invalid status list credential: [%s]", err.Error())
+	}
+
+	if len(listVc.Id) == 0 {
+		return model.NewError(model.ErrCode_InvalidParameter, "the id of status list credential is empty")
+	}
+
+	senderDid, err := d.dal.getSenderDid()
+	if err != nil {
+		return err
+	}
+
+	if senderDid != listVc.Issuer {
+		return model.NewError(model.ErrCode_PermissionDenied, "only the issuer can publish the status list")
+	}
+
+	old, err := d.dal.getStatusList(listVc.Id)
+	if err != nil {
+		return err
+	}
+
+	if len(old) != 0 {
+		oldVc, err := model.NewVerifiableCredential(string(old))
+		if err != nil {
+			return err
+		}
+
+		if oldVc.Issuer != listVc.Issuer {
+			return model.NewError(model.ErrCode_PermissionDenied,
+				"the status list has been published by another issuer")
+		}
+
+		err = checkStatusListUpdate(oldVc, listVc)
+		if err != nil {
+			return err
+		}
+	}
+
+	now, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	_, err = model.VerifyStatusListCredential(listVc, "", now, &verifySource{d: d})
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putStatusList(listVc.Id, []byte(listJson))
+	if err != nil {
+		return err
+	}
+
+	emitSetStatusListEvent(listVc.Id, listVc.Issuer)
+	return nil
+}
+
+// checkStatusListUpdate 检查状态列表的更新，不能修改列表用途，吊销列表已置位的位不能恢复，列表也不能缩短
+// @params oldVc 链上已发布的状态列表凭证
+// @params listVc 新的状态列表凭证
+func checkStatusListUpdate(oldVc, listVc *model.VerifiableCredential) error {
+	oldList, err := oldVc.GetStatusList("")
+	if err != nil {
+		return err
+	}
+
+	list, err := listVc.GetStatusList("")
+	if err != nil {
+		return err
+	}
+
+	oldPurpose, _ := oldVc.CredentialSubject["statusPurpose"].(string)
+	purpose, _ := listVc.CredentialSubject["statusPurpose"].(string)
+	if purpose != oldPurpose {
+		return model.NewError(model.ErrCode_InvalidParameter,
+			"the status purpose of the list can not be changed, published: [%s]", oldPurpose)
+	}
+
+	if oldPurpose == model.StatusPurpose_Revocation && !list.Covers(oldList) {
+		return model.NewError(model.ErrCode_InvalidParameter,
+			"the revoked status can not be cleared and the status list can not be shortened")
+	}
+
+	return nil
+}
+
+// GetStatusList 获取状态列表凭证
+// @params id 状态列表凭证的ID
+func (d *DidContract) GetStatusList(id string) ([]byte, error) {
+	value, err := d.dal.getStatusList(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, model.NewError(model.ErrCode_StatusListNotFound, "the status list was not found, id: [%s]", id)
+	}
+
+	return value, nil
+}
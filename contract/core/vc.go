@@ -0,0 +1,428 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// VerifyVc 验证VC的有效性
+// @params vcJson vc的json字符串
+func (d *DidContract) VerifyVc(vcJson string) (bool, error) {
+	report, err := d.VerifyVcReport(vcJson)
+	if err != nil {
+		return false, err
+	}
+
+	err = report.Err()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// VerifyVcReport 验证VC并返回每个检查项的结果，任一检查项未通过时报告结果为未通过
+// @params vcJson vc的json字符串
+func (d *DidContract) VerifyVcReport(vcJson string) (*model.VerifyReport, error) {
+	vc, err := model.NewVerifiableCredential(vcJson)
+	if err != nil {
+		report := model.NewVerifyReport("")
+		report.Fail(model.VerifyCheck_Format,
+			model.NewError(model.ErrCode_InvalidCredential, "invalid vc: [%s]", err.Error()))
+		return report, nil
+	}
+
+	now, err := model.GetTxTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return model.VerifyVc(vc, now, &verifySource{d: d})
+}
+
+// verifySource 使用合约状态实现model.VerifySource
+type verifySource struct {
+	d *DidContract
+}
+
+func (s *verifySource) GetDidDocument(did string) ([]byte, error) {
+	return s.d.dal.getDidDocument(did)
+}
+
+func (s *verifySource) GetVcTemplate(id string) ([]byte, error) {
+	return s.d.dal.getVcTemplate(id)
+}
+
+func (s *verifySource) GetVcRevocation(issuer, vcId string) (*model.VcRevocation, error) {
+	return s.d.GetVcRevocation(issuer, vcId)
+}
+
+func (s *verifySource) GetVcSuspension(issuer, vcId string) (*model.VcSuspension, error) {
+	return s.d.dal.getVcSuspension(issuer, vcId)
+}
+
+func (s *verifySource) IsInBlackList(did string) (bool, error) {
+	return s.d.dal.isInBlackList(did), nil
+}
+
+func (s *verifySource) GetStatusListCredential(id string) ([]byte, error) {
+	return s.d.dal.getStatusList(id)
+}
+
+func (s *verifySource) VerifyTrustIssuer(did, templateId string) error {
+	if !s.d.isTrustIssuer(did, templateId) {
+		return model.NewError(model.ErrCode_NotTrustedIssuer,
+			"the issuer of VC is not a trusted issuer of the VC template on the chain")
+	}
+	return nil
+}
+
+// RevokeVc 撤销VC，吊销记录按签发者区分，不同签发者的相同VC编号互不影响
+// @params issuer VC的签发者DID，为空时使用发送者或签发日志中的签发者
+// @params vcID VC业务编号
+// @params reasonCode 原因编码
+// @params reason 原因描述
+func (d *DidContract) RevokeVc(issuer, vcID string, reasonCode int, reason string) error {
+	issuer, err := d.resolveVcIssuer(issuer, vcID)
+	if err != nil {
+		return err
+	}
+
+	revoker, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return err
+	}
+
+	now, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	record := model.NewVcRevocation(issuer, vcID, reasonCode, reason, revoker, now)
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putVcRevocation(issuer, vcID, recordBytes)
+	if err != nil {
+		return err
+	}
+	emitRevokeVcEvent(vcID, recordBytes)
+	return nil
+}
+
+// GetRevokedVcList 获取撤销VC列表
+// @params issuer 签发者DID，为空时查询所有签发者
+// @params vcIDSearch VC编号前缀
+func (d *DidContract) GetRevokedVcList(issuer, vcIDSearch string, start int,
+	count int) ([]*model.VcRevocation, error) {
+	return d.dal.searchVcRevocation(issuer, vcIDSearch, start, count)
+}
+
+// GetVcRevocation 获取签发者的VC的吊销记录，VC未被吊销时返回nil
+// @params issuer VC的签发者DID
+// @params vcId VC业务编号
+func (d *DidContract) GetVcRevocation(issuer, vcId string) (*model.VcRevocation, error) {
+	record, err := d.dal.getVcRevocation(issuer, vcId)
+	if err != nil || record != nil {
+		return record, err
+	}
+
+	// 数据迁移时签发者未知的吊销记录对所有签发者生效
+	record, err = d.dal.getVcRevocation("", vcId)
+	if err != nil || record != nil {
+		return record, err
+	}
+
+	// 吊销数据迁移完成前，尚未迁移的早期版本吊销数据仍然有效
+	return d.getLegacyVcRevocation(issuer, vcId)
+}
+
+// SuspendVc 暂停VC，暂停期间VC验证不通过，可以通过UnsuspendVc恢复
+// @params issuer VC的签发者DID，为空时使用发送者或签发日志中的签发者
+// @params vcId VC业务编号
+// @params reason 原因描述
+func (d *DidContract) SuspendVc(issuer, vcId string, reason string) error {
+	issuer, err := d.resolveVcIssuer(issuer, vcId)
+	if err != nil {
+		return err
+	}
+
+	revocation, err := d.GetVcRevocation(issuer, vcId)
+	if err != nil {
+		return err
+	}
+	if revocation != nil {
+		return model.NewError(model.ErrCode_Revoked, "the VC is revoked, id: [%s]", vcId)
+	}
+
+	old, err := d.dal.getVcSuspension(issuer, vcId)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		return model.NewError(model.ErrCode_Suspended, "the VC is already suspended, id: [%s]", vcId)
+	}
+
+	record, err := d.newVcSuspension(issuer, vcId, reason)
+	if err != nil {
+		return err
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putVcSuspension(issuer, vcId, recordBytes)
+	if err != nil {
+		return err
+	}
+
+	emitSuspendVcEvent(vcId, recordBytes)
+	return nil
+}
+
+// UnsuspendVc 恢复被暂停的VC
+// @params issuer VC的签发者DID，为空时使用发送者或签发日志中的签发者
+// @params vcId VC业务编号
+// @params reason 原因描述
+func (d *DidContract) UnsuspendVc(issuer, vcId string, reason string) error {
+	issuer, err := d.resolveVcIssuer(issuer, vcId)
+	if err != nil {
+		return err
+	}
+
+	old, err := d.dal.getVcSuspension(issuer, vcId)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return model.NewError(model.ErrCode_InvalidParameter, "the VC is not suspended, id: [%s]", vcId)
+	}
+
+	record, err := d.newVcSuspension(issuer, vcId, reason)
+	if err != nil {
+		return err
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.deleteVcSuspension(issuer, vcId)
+	if err != nil {
+		return err
+	}
+
+	emitUnsuspendVcEvent(vcId, recordBytes)
+	return nil
+}
+
+// GetVcSuspension 获取签发者的VC的暂停记录，VC未被暂停时返回nil
+// @params issuer VC的签发者DID
+// @params vcId VC业务编号
+func (d *DidContract) GetVcSuspension(issuer, vcId string) (*model.VcSuspension, error) {
+	return d.dal.getVcSuspension(issuer, vcId)
+}
+
+// newVcSuspension 使用当前交易的发送者和时间生成暂停或恢复记录
+func (d *DidContract) newVcSuspension(issuer, vcId, reason string) (*model.VcSuspension, error) {
+	operator, err := sdk.Instance.GetSenderPk()
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := model.GetTxTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewVcSuspension(issuer, vcId, reason, operator, now), nil
+}
+
+// resolveVcIssuer 检查发送者可以吊销或暂停VC，并返回操作的VC所属的签发者
+// 签发者本人只能操作自己签发的VC；管理员和VC管理员可以指定签发者，未指定时使用签发日志中唯一的签发者
+// @params issuer 调用参数中的签发者DID，可以为空
+// @params vcId VC业务编号
+func (d *DidContract) resolveVcIssuer(issuer, vcId string) (string, error) {
+	// 判断是不是管理员或VC管理员
+	ok, err := d.hasSenderRole(model.Role_VcManager)
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		if len(issuer) != 0 {
+			return issuer, nil
+		}
+
+		issuers, err := d.dal.getVcIssuers(vcId)
+		if err != nil {
+			return "", err
+		}
+		if len(issuers) == 0 {
+			return "", model.NewError(model.ErrCode_InvalidParameter,
+				"the VC has no issue log, the issuer is required, id: [%s]", vcId)
+		}
+		if len(issuers) > 1 {
+			return "", model.NewError(model.ErrCode_InvalidParameter,
+				"the VC is issued by multiple issuers, the issuer is required, id: [%s]", vcId)
+		}
+		return issuers[0], nil
+	}
+
+	// 判断是不是签发者本人
+	senderDid, _ := d.dal.getSenderDid()
+	if len(senderDid) == 0 || (len(issuer) != 0 && issuer != senderDid) {
+		return "", model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	issueLog, err := d.dal.getVcIssueLog(senderDid, vcId)
+	if err != nil {
+		return "", err
+	}
+	if issueLog == nil {
+		return "", model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+	}
+
+	return senderDid, nil
+}
+
+// SetVcTemplate 设置VC模板
+func (d *DidContract) SetVcTemplate(id string, name string, version string, template string) error {
+	// 判读是否有权限
+	if !d.isSenderTrustIssuer() {
+		ok, _ := d.hasSenderRole(model.Role_TemplateManager)
+		if !ok {
+			return model.NewError(model.ErrCode_PermissionDenied, "no operation permission")
+		}
+	}
+
+	return d.setVcTemplate(id, name, version, template)
+}
+
+func (d *DidContract) setVcTemplate(id string, name string, version string, template string) error {
+	value, _ := d.GetVcTemplate(id)
+	if len(value) != 0 {
+		return model.NewError(model.ErrCode_VcTemplateAlreadyExists, "the VC template already exists")
+	}
+
+	// 需要校验一下模板里面是否包含ID字段
+	var tempJson model.VcTemplateJSONSchema
+	err := json.Unmarshal([]byte(template), &tempJson)
+	if err != nil {
+		return model.NewError(model.ErrCode_InvalidVcTemplate,
+			"the template does not conform to the json Schema specification")
+	}
+
+	var isIncludedId bool
+
+	for _, v := range tempJson.Required {
+		if v == "id" {
+			isIncludedId = true
+			break
+		}
+	}
+
+	if !isIncludedId {
+		return model.NewError(model.ErrCode_InvalidVcTemplate, "the template must contain the `id` subfield")
+	}
+
+	vcTemp := &model.VcTemplate{
+		Id:       id,
+		Name:     name,
+		Version:  version,
+		Template: json.RawMessage(template),
+	}
+
+	tempBytes, err := json.Marshal(vcTemp)
+	if err != nil {
+		return err
+	}
+
+	err = d.dal.putVcTemplate(id, tempBytes)
+	if err != nil {
+		return err
+	}
+
+	emitSetVcTemplateEvent(id, tempBytes)
+	return nil
+}
+
+// GetVcTemplate 获取VC模板
+func (d *DidContract) GetVcTemplate(id string) ([]byte, error) {
+	return d.dal.getVcTemplate(id)
+}
+
+// GetVcTemplateList 获取VC模板列表
+func (d *DidContract) GetVcTemplateList(templateNameSearch string, start int, count int) (
+	[]*model.VcTemplate, error) {
+	return d.dal.searchVcTemplate(templateNameSearch, start, count)
+}
+
+// VcIssueLog 存储签发日志
+func (d *DidContract) VcIssueLog(issuer, did, templateId, vcId string) error {
+	// 校验签发者是否具有该模板的签发资格
+	if !d.isTrustIssuer(issuer, templateId) {
+		return model.NewError(model.ErrCode_NotTrustedIssuer,
+			"the issuer is not in trust issuer list of the vc template")
+	}
+
+	// 校验被签发者是否合格
+	if d.dal.isInBlackList(did) {
+		return model.NewError(model.ErrCode_InBlackList, "the did is in black list")
+	}
+
+	if !d.dal.isDidDocExisting(did) {
+		return model.NewError(model.ErrCode_DidNotFound, "the did's doc not found on chain, did: [%s]", did)
+	}
+
+	// 检查模板是否在链上
+	temp, err := d.GetVcTemplate(templateId)
+	if err != nil {
+		return err
+	}
+
+	if len(temp) == 0 {
+		return model.NewError(model.ErrCode_VcTemplateNotFound, "the vc template not found on chain")
+	}
+
+	myTime, err := model.GetTxTime()
+	if err != nil {
+		return err
+	}
+
+	vcIssueLog := model.NewVcIssueLog(issuer, did, templateId, vcId, myTime)
+
+	v, err := json.Marshal(vcIssueLog)
+	if err != nil {
+		return err
+	}
+
+	emitVcIssueLogEvent(vcId, v)
+
+	return d.dal.putVcIssueLog(issuer, vcId, v)
+}
+
+// GetVcIssueLogs 获取签发日志列表，签发日志迁移完成前同时查询尚未迁移的早期版本签发日志
+func (d *DidContract) GetVcIssueLogs(vcIdSearch string, start int, count int) (
+	[]*model.VcIssueLog, error) {
+	version, err := d.dal.getSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.dal.searchVcIssueLogs(vcIdSearch, start, count, version < schemaVersionVcIssueRecord)
+}
@@ -0,0 +1,577 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/stretchr/testify/require"
+)
+
+const testVcTemplate = `{"$schema":"http://json-schema.org/draft-07/schema#","type":"object",` +
+	`"properties":{"id":{"type":"string"},"name":{"type":"string"}},"required":["id","name"]}`
+
+func setVcTemplateArgs(id, template string) map[string]string {
+	return map[string]string{
+		model.Params_VcTemplateId:      id,
+		model.Params_VcTemplateName:    "template " + id,
+		model.Params_VcTemplateVersion: "v1",
+		model.Params_VcTemplate:        template,
+	}
+}
+
+func TestSetVcTemplate(t *testing.T) {
+	d, m := newTestContract(t, true)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+
+	// 启用信任签发者后，不在信任列表中的用户不能设置模板
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)), model.ErrCode_PermissionDenied)
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddTrustIssuer, map[string]string{
+		model.Params_Did: testIssuerDid,
+	}))
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)))
+
+	// 模板ID不能重复
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)), model.ErrCode_VcTemplateAlreadyExists)
+
+	// 模板必须包含id字段
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("2", `{"type":"object","required":["name"]}`)), model.ErrCode_InvalidVcTemplate)
+
+	requireFail(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("2", "not json")))
+
+	// 模板管理员可以设置模板
+	requireFail(t, invokeAs(d, m, testUserSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("3", testVcTemplate)))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   model.Role_TemplateManager,
+		model.Params_Member: testUserSki,
+	}))
+
+	requireOK(t, invokeAs(d, m, testUserSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("3", testVcTemplate)))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetVcTemplateList, nil)
+	requireOK(t, resp)
+
+	var templates []*model.VcTemplate
+	require.Nil(t, json.Unmarshal(resp.Payload, &templates))
+	require.Len(t, templates, 2)
+
+	require.Len(t, m.Events(model.Topic_SetVcTemplate), 2)
+}
+
+func TestRevokeVc(t *testing.T) {
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)))
+
+	for _, vcId := range []string{"vc1", "vc2"} {
+		requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_VcIssueLog, map[string]string{
+			model.Params_Issuer:       testIssuerDid,
+			model.Params_Did:          testUserDid,
+			model.Params_VcTemplateId: "1",
+			model.Params_VcId:         vcId,
+		}))
+	}
+
+	// 持有者不能撤销VC
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}), model.ErrCode_PermissionDenied)
+
+	// 签发者可以撤销自己签发的VC
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}))
+
+	// 没有签发记录的VC只有VC管理员可以撤销
+	requireFail(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc3",
+	}))
+
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_GrantRole, map[string]string{
+		model.Params_Role:   model.Role_VcManager,
+		model.Params_Member: testUserDid,
+	}))
+
+	// 没有签发记录时VC管理员需要指定签发者
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc3",
+	}), model.ErrCode_InvalidParameter)
+
+	m.SetTxTime(1700000000)
+	requireOK(t, invokeAs(d, m, testUserSki, model.Method_RevokeVc, map[string]string{
+		model.Params_Issuer:     "did:cm:other",
+		model.Params_VcId:       "vc3",
+		model.Params_ReasonCode: "4",
+		model.Params_Reason:     "fraud",
+	}))
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetRevokedVcList, nil)
+	requireOK(t, resp)
+
+	var revoked []*model.VcRevocation
+	require.Nil(t, json.Unmarshal(resp.Payload, &revoked))
+	require.Len(t, revoked, 2)
+
+	resp = invokeAs(d, m, testUserSki, model.Method_GetRevokedVcList, map[string]string{
+		model.Params_Issuer: "did:cm:other",
+	})
+	requireOK(t, resp)
+	require.Nil(t, json.Unmarshal(resp.Payload, &revoked))
+	require.Equal(t, []*model.VcRevocation{model.NewVcRevocation("did:cm:other", "vc3",
+		model.RevocationReasonFraud, "fraud", testUserSki, 1700000000)}, revoked)
+
+	resp = invokeAs(d, m, testUserSki, model.Method_GetRevokedVcList, map[string]string{
+		model.Params_VcIdSearch: "vc1",
+	})
+	requireOK(t, resp)
+	require.Nil(t, json.Unmarshal(resp.Payload, &revoked))
+	require.Len(t, revoked, 1)
+	require.Equal(t, testIssuerDid, revoked[0].Issuer)
+	require.Equal(t, testIssuerSki, revoked[0].Revoker)
+
+	// 吊销记录按签发者区分，其他签发者的同名VC不受影响
+	resp = invokeAs(d, m, testUserSki, model.Method_GetVcRevocation, map[string]string{
+		model.Params_Issuer: testIssuerDid,
+		model.Params_VcId:   "vc3",
+	})
+	requireOK(t, resp)
+	require.Equal(t, "null", string(resp.Payload))
+
+	events := m.Events(model.Topic_RevokeVc)
+	require.Len(t, events, 2)
+	require.Equal(t, "vc3", events[1].Data[0])
+
+	// 黑名单中的DID不能再被签发VC
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: testUserDid,
+	}))
+
+	requireFail(t, invokeAs(d, m, testIssuerSki, model.Method_VcIssueLog, map[string]string{
+		model.Params_Issuer:       testIssuerDid,
+		model.Params_Did:          testUserDid,
+		model.Params_VcTemplateId: "1",
+		model.Params_VcId:         "vc4",
+	}))
+}
+
+func TestRevokeVcKeyCollision(t *testing.T) {
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)))
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_VcIssueLog, map[string]string{
+		model.Params_Issuer:       testIssuerDid,
+		model.Params_Did:          testUserDid,
+		model.Params_VcTemplateId: "1",
+		model.Params_VcId:         "a-b",
+	}))
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "a-b",
+	}))
+
+	// 只有分隔符不同的VC编号不会被误判为已吊销
+	for _, vcId := range []string{"a_b", "a.b", "a:b"} {
+		revocation, err := d.GetVcRevocation(testIssuerDid, vcId)
+		require.Nil(t, err)
+		require.Nil(t, revocation, vcId)
+	}
+
+	revocation, err := d.GetVcRevocation(testIssuerDid, "a-b")
+	require.Nil(t, err)
+	require.True(t, revocation.Matches(testIssuerDid, "a-b"))
+
+	// 早期版本签发者未知的吊销记录对所有签发者生效
+	require.Nil(t, d.dal.putVcRevocation("", "c-d", []byte("c-d")))
+	revocation, err = d.GetVcRevocation("did:cm:any", "c-d")
+	require.Nil(t, err)
+	require.Equal(t, "c-d", revocation.VcId)
+}
+
+func TestVcIssueLogPerIssuer(t *testing.T) {
+	const (
+		otherIssuerDid = "did:cm:issuer2"
+		otherIssuerSki = "e1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4"
+	)
+
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, otherIssuerDid, otherIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)))
+
+	// 两个签发者签发同名VC，签发日志互不覆盖
+	for _, sender := range []string{testIssuerSki, otherIssuerSki} {
+		issuer := testIssuerDid
+		if sender == otherIssuerSki {
+			issuer = otherIssuerDid
+		}
+		requireOK(t, invokeAs(d, m, sender, model.Method_VcIssueLog, map[string]string{
+			model.Params_Issuer:       issuer,
+			model.Params_Did:          testUserDid,
+			model.Params_VcTemplateId: "1",
+			model.Params_VcId:         "vc1",
+		}))
+	}
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetVcIssueLogs, map[string]string{
+		model.Params_VcIdSearch: "vc1",
+	})
+	requireOK(t, resp)
+
+	var logs []*model.VcIssueLog
+	require.Nil(t, json.Unmarshal(resp.Payload, &logs))
+	require.Len(t, logs, 2)
+
+	// 同一签发者重复记录签发日志时不会重复加入vcID的签发者索引
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_VcIssueLog, issueLogArgs(testIssuerDid, "1", "vc1")))
+	issuers, err := d.dal.getVcIssuers("vc1")
+	require.Nil(t, err)
+	require.Equal(t, []string{testIssuerDid, otherIssuerDid}, issuers)
+
+	// 先签发的签发者仍然可以吊销和暂停自己的VC
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SuspendVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}))
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}))
+
+	// 签发者不能操作其他签发者的VC
+	requireFailCode(t, invokeAs(d, m, otherIssuerSki, model.Method_SuspendVc, map[string]string{
+		model.Params_Issuer: testIssuerDid,
+		model.Params_VcId:   "vc1",
+	}), model.ErrCode_PermissionDenied)
+
+	// 同名VC有多个签发者时管理员需要指定签发者
+	requireFailCode(t, invokeAs(d, m, testCreatorSki, model.Method_SuspendVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}), model.ErrCode_InvalidParameter)
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_SuspendVc, map[string]string{
+		model.Params_Issuer: otherIssuerDid,
+		model.Params_VcId:   "vc1",
+	}))
+
+	revocation, err := d.GetVcRevocation(otherIssuerDid, "vc1")
+	require.Nil(t, err)
+	require.Nil(t, revocation)
+
+	suspension, err := d.GetVcSuspension(otherIssuerDid, "vc1")
+	require.Nil(t, err)
+	require.Equal(t, otherIssuerDid, suspension.Issuer)
+}
+
+func TestSuspendVc(t *testing.T) {
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)))
+	for _, vcId := range []string{"vc1", "vc2"} {
+		requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_VcIssueLog, map[string]string{
+			model.Params_Issuer:       testIssuerDid,
+			model.Params_Did:          testUserDid,
+			model.Params_VcTemplateId: "1",
+			model.Params_VcId:         vcId,
+		}))
+	}
+
+	suspendArgs := map[string]string{
+		model.Params_VcId:   "vc1",
+		model.Params_Reason: "under investigation",
+	}
+
+	// 持有者不能暂停VC
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_SuspendVc, suspendArgs),
+		model.ErrCode_PermissionDenied)
+
+	m.SetTxTime(1700000000)
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SuspendVc, suspendArgs))
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SuspendVc, suspendArgs),
+		model.ErrCode_Suspended)
+
+	resp := invokeAs(d, m, testUserSki, model.Method_GetVcSuspension, map[string]string{
+		model.Params_Issuer: testIssuerDid,
+		model.Params_VcId:   "vc1",
+	})
+	requireOK(t, resp)
+
+	var record *model.VcSuspension
+	require.Nil(t, json.Unmarshal(resp.Payload, &record))
+	require.Equal(t, model.NewVcSuspension(testIssuerDid, "vc1", "under investigation", testIssuerSki, 1700000000), record)
+
+	// 暂停的VC验证不通过，错误码与吊销不同
+	vcJson := `{"@context":["https://www.w3.org/2018/credentials/v1"],"id":"vc1",` +
+		`"type":["VerifiableCredential"],"credentialSubject":{"id":"` + testUserDid + `","name":"test"},` +
+		`"issuer":"` + testIssuerDid + `","issuanceDate":"2023-01-01T00:00:00Z",` +
+		`"expirationDate":"2033-01-01T00:00:00Z",` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"` + testIssuerDid + `#key-1","proofValue":"MEQ="}}`
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_VerifyVc, map[string]string{
+		model.Params_VcJson: vcJson,
+	}), model.ErrCode_Suspended)
+
+	// 恢复后不再处于暂停状态
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_UnsuspendVc, map[string]string{
+		model.Params_VcId: "vc2",
+	}), model.ErrCode_InvalidParameter)
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_UnsuspendVc, map[string]string{
+		model.Params_VcId:   "vc1",
+		model.Params_Reason: "cleared",
+	}))
+
+	resp = invokeAs(d, m, testUserSki, model.Method_GetVcSuspension, map[string]string{
+		model.Params_Issuer: testIssuerDid,
+		model.Params_VcId:   "vc1",
+	})
+	requireOK(t, resp)
+	require.Equal(t, "null", string(resp.Payload))
+
+	require.Len(t, m.Events(model.Topic_SuspendVc), 1)
+	events := m.Events(model.Topic_UnsuspendVc)
+	require.Len(t, events, 1)
+	require.Equal(t, "vc1", events[0].Data[0])
+	require.Nil(t, json.Unmarshal([]byte(events[0].Data[1]), &record))
+	require.Equal(t, "cleared", record.Reason)
+
+	// 已吊销的VC不能暂停，吊销优先于暂停
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SuspendVc, suspendArgs))
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_RevokeVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}))
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_VerifyVc, map[string]string{
+		model.Params_VcJson: vcJson,
+	}), model.ErrCode_Revoked)
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SuspendVc, map[string]string{
+		model.Params_VcId: "vc1",
+	}), model.ErrCode_Revoked)
+}
+
+func TestVerifyVcReport(t *testing.T) {
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	requireOK(t, invokeAs(d, m, testIssuerSki, model.Method_SetVcTemplate,
+		setVcTemplateArgs("1", testVcTemplate)))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_RevokeVc, map[string]string{
+		model.Params_Issuer: "did:cm:unknown",
+		model.Params_VcId:   "vc1",
+	}))
+	requireOK(t, invokeAs(d, m, testCreatorSki, model.Method_AddBlackList, map[string]string{
+		model.Params_Did: testUserDid,
+	}))
+
+	vcJson := `{"@context":["https://www.w3.org/2018/credentials/v1"],"id":"vc1",` +
+		`"type":["VerifiableCredential"],"credentialSubject":{"id":"` + testUserDid + `","name":"test"},` +
+		`"issuer":"did:cm:unknown","issuanceDate":"2023-01-01T00:00:00Z","expirationDate":"2033-01-01T00:00:00Z",` +
+		`"template":{"id":"1","name":"template 1"},` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"did:cm:unknown#key-1","proofValue":"MEQ="}}`
+
+	resp := invokeAs(d, m, testUserSki, model.Method_VerifyVcReport, map[string]string{
+		model.Params_VcJson: vcJson,
+	})
+	requireOK(t, resp)
+
+	var report model.VerifyReport
+	require.Nil(t, json.Unmarshal(resp.Payload, &report))
+	require.Equal(t, "vc1", report.Id)
+	require.False(t, report.Passed)
+
+	// 所有检查项都会执行，不会在第一个失败的检查项停止
+	results := make(map[string]model.ErrorCode)
+	for _, c := range report.Checks {
+		require.Equal(t, c.Passed, c.Code == model.ErrCode_Unknown, c.Name)
+		results[c.Name] = c.Code
+	}
+	require.Equal(t, map[string]model.ErrorCode{
+		model.VerifyCheck_Type:        model.ErrCode_Unknown,
+		model.VerifyCheck_Date:        model.ErrCode_Unknown,
+		model.VerifyCheck_Template:    model.ErrCode_Unknown,
+		model.VerifyCheck_IssuerTrust: model.ErrCode_Unknown,
+		model.VerifyCheck_Revocation:  model.ErrCode_Revoked,
+		model.VerifyCheck_BlackList:   model.ErrCode_InBlackList,
+		model.VerifyCheck_Signature:   model.ErrCode_DidNotFound,
+	}, results)
+
+	// VerifyVc返回第一个未通过的检查项
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_VerifyVc, map[string]string{
+		model.Params_VcJson: vcJson,
+	}), model.ErrCode_Revoked)
+
+	// VP中每个VC的验证报告
+	vpJson := `{"id":"vp1","type":["VerifiablePresentation"],"holder":"` + testIssuerDid + `",` +
+		`"verifiableCredential":[` + vcJson + `],` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"` + testIssuerDid + `#key-1","proofValue":"MEQ="}}`
+
+	resp = invokeAs(d, m, testUserSki, model.Method_VerifyVpReport, map[string]string{
+		model.Params_VpJson: vpJson,
+	})
+	requireOK(t, resp)
+
+	report = model.VerifyReport{}
+	require.Nil(t, json.Unmarshal(resp.Payload, &report))
+	require.False(t, report.Passed)
+	require.Len(t, report.Credentials, 1)
+	require.Equal(t, "vc1", report.Credentials[0].Id)
+
+	results = make(map[string]model.ErrorCode)
+	for _, c := range report.Checks {
+		results[c.Name] = c.Code
+	}
+	require.Equal(t, model.ErrCode_InvalidCredential, results[model.VerifyCheck_HolderBinding])
+	require.Equal(t, model.ErrCode_Revoked, results[model.VerifyCheck_Credential])
+	require.Equal(t, model.ErrCode_Unknown, results[model.VerifyCheck_BlackList])
+	require.Contains(t, results, model.VerifyCheck_Signature)
+}
+
+func TestStatusList(t *testing.T) {
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+	seedDid(t, d, m, testUserDid, testUserSki)
+
+	list, err := model.NewStatusList(model.StatusListMinLength)
+	require.Nil(t, err)
+	require.Nil(t, list.Set(7, true))
+	encodedList, err := list.Encode()
+	require.Nil(t, err)
+
+	decoded, err := model.DecodeStatusList(encodedList)
+	require.Nil(t, err)
+	require.Equal(t, list, decoded)
+	set, err := decoded.Get(7)
+	require.Nil(t, err)
+	require.True(t, set)
+
+	listJson := `{"@context":["https://www.w3.org/2018/credentials/v1"],"id":"sl1",` +
+		`"type":["BitstringStatusListCredential","VerifiableCredential"],` +
+		`"credentialSubject":{"id":"sl1#list","type":"BitstringStatusList","statusPurpose":"revocation",` +
+		`"encodedList":"` + encodedList + `"},` +
+		`"issuer":"` + testIssuerDid + `","issuanceDate":"2023-01-01T00:00:00Z",` +
+		`"expirationDate":"2033-01-01T00:00:00Z",` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"` + testIssuerDid + `#key-1","proofValue":"MEQ="}}`
+
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SetStatusList, map[string]string{
+		model.Params_StatusListCredential: "{",
+	}), model.ErrCode_InvalidParameter)
+
+	// 只有签发者可以发布状态列表
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_SetStatusList, map[string]string{
+		model.Params_StatusListCredential: listJson,
+	}), model.ErrCode_PermissionDenied)
+
+	// 签名无效的状态列表不能发布
+	requireFailCode(t, invokeAs(d, m, testIssuerSki, model.Method_SetStatusList, map[string]string{
+		model.Params_StatusListCredential: listJson,
+	}), model.ErrCode_InvalidCredential)
+
+	requireFailCode(t, invokeAs(d, m, testUserSki, model.Method_GetStatusList, map[string]string{
+		model.Params_StatusListId: "sl1",
+	}), model.ErrCode_StatusListNotFound)
+
+	// 状态列表不在链上时带credentialStatus的VC验证失败
+	status, err := json.Marshal(model.NewCredentialStatus("sl1", model.StatusPurpose_Revocation, 7))
+	require.Nil(t, err)
+	vcJson := `{"@context":["https://www.w3.org/2018/credentials/v1"],"id":"vc1",` +
+		`"type":["VerifiableCredential"],"credentialSubject":{"id":"` + testUserDid + `","name":"test"},` +
+		`"issuer":"` + testIssuerDid + `","issuanceDate":"2023-01-01T00:00:00Z",` +
+		`"expirationDate":"2033-01-01T00:00:00Z","credentialStatus":` + string(status) + `,` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"` + testIssuerDid + `#key-1","proofValue":"MEQ="}}`
+
+	resp := invokeAs(d, m, testUserSki, model.Method_VerifyVcReport, map[string]string{
+		model.Params_VcJson: vcJson,
+	})
+	requireOK(t, resp)
+
+	var report model.VerifyReport
+	require.Nil(t, json.Unmarshal(resp.Payload, &report))
+	for _, c := range report.Checks {
+		if c.Name == model.VerifyCheck_Revocation {
+			require.Equal(t, model.ErrCode_StatusListNotFound, c.Code)
+		}
+	}
+}
+
+// statusListJson 构造指定用途的状态列表凭证，证明无效
+func statusListJson(t *testing.T, list *model.StatusList, purpose string) string {
+	encodedList, err := list.Encode()
+	require.Nil(t, err)
+
+	return `{"@context":["https://www.w3.org/2018/credentials/v1"],"id":"sl1",` +
+		`"type":["BitstringStatusListCredential","VerifiableCredential"],` +
+		`"credentialSubject":{"id":"sl1#list","type":"BitstringStatusList","statusPurpose":"` + purpose + `",` +
+		`"encodedList":"` + encodedList + `"},` +
+		`"issuer":"` + testIssuerDid + `","issuanceDate":"2023-01-01T00:00:00Z",` +
+		`"expirationDate":"2033-01-01T00:00:00Z",` +
+		`"proof":{"type":"SM2Signature","verificationMethod":"` + testIssuerDid + `#key-1","proofValue":"MEQ="}}`
+}
+
+func TestStatusListRevocationIsFinal(t *testing.T) {
+	d, m := newTestContract(t, false)
+	seedDid(t, d, m, testIssuerDid, testIssuerSki)
+
+	newList := func(length int, revoked ...int) *model.StatusList {
+		list, err := model.NewStatusList(length)
+		require.Nil(t, err)
+		for _, i := range revoked {
+			require.Nil(t, list.Set(i, true))
+		}
+		return list
+	}
+
+	// 直接写入已发布的吊销列表，第7位已置位
+	published := statusListJson(t, newList(2*model.StatusListMinLength, 7), model.StatusPurpose_Revocation)
+	m.BeginTx("", nil)
+	require.Nil(t, d.dal.putStatusList("sl1", []byte(published)))
+	m.Commit()
+
+	setStatusList := func(listJson string) protogo.Response {
+		return invokeAs(d, m, testIssuerSki, model.Method_SetStatusList, map[string]string{
+			model.Params_StatusListCredential: listJson,
+		})
+	}
+
+	// 已吊销的位不能恢复
+	requireFailCode(t, setStatusList(statusListJson(t, newList(2*model.StatusListMinLength),
+		model.StatusPurpose_Revocation)), model.ErrCode_InvalidParameter)
+
+	// 列表不能缩短
+	requireFailCode(t, setStatusList(statusListJson(t, newList(model.StatusListMinLength, 7),
+		model.StatusPurpose_Revocation)), model.ErrCode_InvalidParameter)
+
+	// 列表用途不能修改
+	requireFailCode(t, setStatusList(statusListJson(t, newList(2*model.StatusListMinLength),
+		"suspension")), model.ErrCode_InvalidParameter)
+
+	// 只新增吊销位的更新通过检查，在签名验证时失败
+	requireFailCode(t, setStatusList(statusListJson(t, newList(2*model.StatusListMinLength, 7, 8),
+		model.StatusPurpose_Revocation)), model.ErrCode_InvalidCredential)
+
+	value, err := d.GetStatusList("sl1")
+	require.Nil(t, err)
+	require.Equal(t, published, string(value))
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+import (
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// VerifyVp 验证vp
+// @params vpJson vp的json字符串
+func (d *DidContract) VerifyVp(vpJson string) (bool, error) {
+	report, err := d.VerifyVpReport(vpJson)
+	if err != nil {
+		return false, err
+	}
+
+	err = report.Err()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// VerifyVpReport 验证VP并返回每个检查项的结果，VP中每个VC的验证报告在Credentials中
+// @params vpJson vp的json字符串
+func (d *DidContract) VerifyVpReport(vpJson string) (*model.VerifyReport, error) {
+	vp, err := model.NewVerifiablePresentation(vpJson)
+	if err != nil {
+		report := model.NewVerifyReport("")
+		report.Fail(model.VerifyCheck_Format,
+			model.NewError(model.ErrCode_InvalidCredential, "invalid vp: [%s]", err.Error()))
+		return report, nil
+	}
+
+	now, err := model.GetTxTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return model.VerifyVp(vp, now, &verifySource{d: d})
+}
@@ -0,0 +1,420 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mock 提供基于内存的长安链合约SDK，用于在go test中执行合约方法
+package mock
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	// DefaultTxTime 默认的初始交易时间（Unix秒）
+	DefaultTxTime = 1700000000
+	// DefaultOrgId 默认的组织ID
+	DefaultOrgId = "mock-org"
+	// DefaultRole 默认的成员角色
+	DefaultRole = "client"
+)
+
+// Event 已提交交易发送的合约事件
+type Event struct {
+	TxId  string
+	Topic string
+	Data  []string
+}
+
+// pendingValue 当前交易写入的数据，deleted表示已删除
+type pendingValue struct {
+	value   []byte
+	deleted bool
+}
+
+// SDK 实现了sdk.SDKInterface中合约用到的方法，未实现的方法会因为内嵌的接口为nil而panic
+// 数据的读写以交易为单位：BeginTx之后的写入和事件在Commit时生效，Rollback时丢弃
+type SDK struct {
+	sdk.SDKInterface
+
+	mu sync.Mutex
+
+	state   map[string]map[string][]byte
+	pending map[string]map[string]*pendingValue
+
+	events        []*Event
+	pendingEvents []*Event
+
+	args        map[string][]byte
+	txSeq       int
+	txId        string
+	txTime      int64
+	blockHeight int
+	sender      string
+	creator     string
+}
+
+// NewSDK 新建内存合约SDK
+// @params creatorPk 合约创建者公钥的SKI，也是默认的交易发送者
+func NewSDK(creatorPk string) *SDK {
+	return &SDK{
+		state:   make(map[string]map[string][]byte),
+		pending: make(map[string]map[string]*pendingValue),
+		args:    make(map[string][]byte),
+		txTime:  DefaultTxTime,
+		sender:  creatorPk,
+		creator: creatorPk,
+	}
+}
+
+// SetSender 设置之后交易的发送者
+// @params senderPk 发送者公钥的SKI
+func (m *SDK) SetSender(senderPk string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sender = senderPk
+}
+
+// SetTxTime 设置下一笔交易的时间，之后每笔交易的时间递增1秒
+// @params txTime 交易时间（Unix秒）
+func (m *SDK) SetTxTime(txTime int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.txTime = txTime - 1
+}
+
+// SetState 直接写入已提交的数据，用于构造测试数据
+func (m *SDK) SetState(key, field string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state[key] == nil {
+		m.state[key] = make(map[string][]byte)
+	}
+	m.state[key][field] = value
+}
+
+// State 获取已提交的数据
+func (m *SDK) State(key, field string) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state[key][field]
+}
+
+// Fields 获取key下所有已提交数据的field，按字典序排列
+func (m *SDK) Fields(key string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fields := make([]string, 0, len(m.state[key]))
+	for f := range m.state[key] {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// BeginTx 开始一笔新的交易，丢弃上一笔交易未提交的数据
+// @params txId 交易ID，为空时自动生成
+// @params args 交易参数
+// @return 交易ID
+func (m *SDK) BeginTx(txId string, args map[string][]byte) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.txSeq++
+	if len(txId) == 0 {
+		txId = "mocktx" + strconv.Itoa(m.txSeq)
+	}
+
+	if args == nil {
+		args = make(map[string][]byte)
+	}
+
+	m.txId = txId
+	m.args = args
+	m.txTime++
+	m.pending = make(map[string]map[string]*pendingValue)
+	m.pendingEvents = nil
+
+	return txId
+}
+
+// Commit 提交当前交易的写入和事件，区块高度加1
+func (m *SDK) Commit() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, fields := range m.pending {
+		for field, v := range fields {
+			if v.deleted {
+				delete(m.state[key], field)
+				continue
+			}
+
+			if m.state[key] == nil {
+				m.state[key] = make(map[string][]byte)
+			}
+			m.state[key][field] = v.value
+		}
+	}
+
+	m.events = append(m.events, m.pendingEvents...)
+	m.pending = make(map[string]map[string]*pendingValue)
+	m.pendingEvents = nil
+	m.blockHeight++
+}
+
+// Rollback 丢弃当前交易的写入和事件
+func (m *SDK) Rollback() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending = make(map[string]map[string]*pendingValue)
+	m.pendingEvents = nil
+}
+
+// Events 获取已提交交易发送的事件
+// @params topic 事件主题，为空表示获取所有事件
+func (m *SDK) Events(topic string) []*Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := make([]*Event, 0)
+	for _, e := range m.events {
+		if len(topic) == 0 || e.Topic == topic {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (m *SDK) GetArgs() map[string][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.args
+}
+
+func (m *SDK) GetState(key, field string) (string, error) {
+	value, err := m.GetStateByte(key, field)
+	return string(value), err
+}
+
+func (m *SDK) GetStateByte(key, field string) ([]byte, error) {
+	value, _ := m.getState(key, field)
+	return value, nil
+}
+
+func (m *SDK) GetStateWithExists(key, field string) (string, bool, error) {
+	value, ok := m.getState(key, field)
+	return string(value), ok, nil
+}
+
+func (m *SDK) GetStateFromKey(key string) (string, error) {
+	return m.GetState(key, "")
+}
+
+func (m *SDK) GetStateFromKeyByte(key string) ([]byte, error) {
+	return m.GetStateByte(key, "")
+}
+
+func (m *SDK) GetStateFromKeyWithExists(key string) (string, bool, error) {
+	return m.GetStateWithExists(key, "")
+}
+
+func (m *SDK) PutState(key, field string, value string) error {
+	return m.PutStateByte(key, field, []byte(value))
+}
+
+func (m *SDK) PutStateByte(key, field string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending[key] == nil {
+		m.pending[key] = make(map[string]*pendingValue)
+	}
+	m.pending[key][field] = &pendingValue{value: value}
+	return nil
+}
+
+func (m *SDK) PutStateFromKey(key string, value string) error {
+	return m.PutStateByte(key, "", []byte(value))
+}
+
+func (m *SDK) PutStateFromKeyByte(key string, value []byte) error {
+	return m.PutStateByte(key, "", value)
+}
+
+func (m *SDK) DelState(key, field string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending[key] == nil {
+		m.pending[key] = make(map[string]*pendingValue)
+	}
+	m.pending[key][field] = &pendingValue{deleted: true}
+	return nil
+}
+
+func (m *SDK) DelStateFromKey(key string) error {
+	return m.DelState(key, "")
+}
+
+func (m *SDK) GetCreatorOrgId() (string, error) {
+	return DefaultOrgId, nil
+}
+
+func (m *SDK) GetCreatorRole() (string, error) {
+	return DefaultRole, nil
+}
+
+func (m *SDK) GetCreatorPk() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.creator, nil
+}
+
+func (m *SDK) GetSenderOrgId() (string, error) {
+	return DefaultOrgId, nil
+}
+
+func (m *SDK) GetSenderRole() (string, error) {
+	return DefaultRole, nil
+}
+
+func (m *SDK) GetSenderPk() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sender, nil
+}
+
+func (m *SDK) GetBlockHeight() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.blockHeight, nil
+}
+
+func (m *SDK) GetTxId() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.txId, nil
+}
+
+func (m *SDK) GetTxTimeStamp() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return strconv.FormatInt(m.txTime, 10), nil
+}
+
+func (m *SDK) EmitEvent(topic string, data []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingEvents = append(m.pendingEvents, &Event{TxId: m.txId, Topic: topic, Data: data})
+}
+
+func (m *SDK) Log(_ string) {}
+
+func (m *SDK) Debugf(_ string, _ ...interface{}) {}
+
+func (m *SDK) Infof(_ string, _ ...interface{}) {}
+
+func (m *SDK) Warnf(_ string, _ ...interface{}) {}
+
+func (m *SDK) Errorf(_ string, _ ...interface{}) {}
+
+// NewIteratorPrefixWithKeyField 创建时对数据做快照（包含当前交易的写入），按field的字典序遍历
+func (m *SDK) NewIteratorPrefixWithKeyField(key string, field string) (sdk.ResultSetKV, error) {
+	return m.newIterator(key, func(f string) bool {
+		return strings.HasPrefix(f, field)
+	}), nil
+}
+
+// NewIteratorWithField 遍历field在[startField, limitField)范围内的数据，创建时对数据做快照（包含当前交易的写入）
+func (m *SDK) NewIteratorWithField(key string, startField string, limitField string) (sdk.ResultSetKV, error) {
+	return m.newIterator(key, func(f string) bool {
+		return f >= startField && f < limitField
+	}), nil
+}
+
+// newIterator 对key下满足条件的field做快照，按field的字典序遍历
+func (m *SDK) newIterator(key string, match func(field string) bool) *iterator {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merged := make(map[string][]byte)
+	for f, v := range m.state[key] {
+		if match(f) {
+			merged[f] = v
+		}
+	}
+
+	for f, v := range m.pending[key] {
+		if !match(f) {
+			continue
+		}
+		if v.deleted {
+			delete(merged, f)
+		} else {
+			merged[f] = v.value
+		}
+	}
+
+	iter := &iterator{key: key}
+	for f := range merged {
+		iter.fields = append(iter.fields, f)
+	}
+	sort.Strings(iter.fields)
+
+	for _, f := range iter.fields {
+		iter.values = append(iter.values, merged[f])
+	}
+
+	return iter
+}
+
+// getState 读取数据，优先读取当前交易的写入
+func (m *SDK) getState(key, field string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v, ok := m.pending[key][field]; ok {
+		if v.deleted {
+			return nil, false
+		}
+		return v.value, true
+	}
+
+	value, ok := m.state[key][field]
+	return value, ok
+}
+
+// iterator 数据快照的迭代器
+type iterator struct {
+	sdk.ResultSetKV
+
+	key    string
+	fields []string
+	values [][]byte
+	index  int
+}
+
+func (it *iterator) HasNext() bool {
+	return it.index < len(it.fields)
+}
+
+func (it *iterator) Next() (string, string, []byte, error) {
+	i := it.index
+	it.index++
+	return it.key, it.fields[i], it.values[i], nil
+}
+
+func (it *iterator) Close() (bool, error) {
+	return true, nil
+}
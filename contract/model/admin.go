@@ -0,0 +1,61 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	bcx509 "github.com/liuxinfeng96/bc-crypto/x509"
+)
+
+// AdminRecord 合约管理员记录
+type AdminRecord struct {
+	// Ski 管理员公钥的SKI，与GetSenderPk()保持一致
+	Ski string `json:"ski"`
+	// Did 管理员公钥对应的DID，以DID注册时为该DID，否则为链上根据公钥地址查到的DID
+	Did string `json:"did,omitempty"`
+	// ByDid 是否以DID注册，以DID注册的管理员公钥随DID Document的验证方法同步更新
+	ByDid bool `json:"byDid,omitempty"`
+	// IsCreator 是否是合约创建者，创建者不存储在管理员列表中，仅在查询时返回
+	IsCreator bool `json:"isCreator,omitempty"`
+	// AddTime 添加时间
+	AddTime int64 `json:"addTime,omitempty"`
+}
+
+// ParseAdminRecord 解析数据库中的管理员记录
+// 早期版本的合约只存储了SKI字符串，这里兼容为一条没有DID的记录
+func ParseAdminRecord(value []byte) (*AdminRecord, error) {
+	if len(value) != 0 && value[0] != '{' {
+		return &AdminRecord{Ski: string(value)}, nil
+	}
+
+	var record AdminRecord
+	err := json.Unmarshal(value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// PubKeyPemToSki 将公钥PEM编码转换为SKI（十六进制编码），与GetSenderPk()保持一致
+// @params pkPem 公钥PEM编码
+func PubKeyPemToSki(pkPem string) (string, error) {
+	publicKey, err := bcx509.ParsePublicKey([]byte(pkPem))
+	if err != nil {
+		return "", err
+	}
+
+	ski, err := bcx509.ComputeSKI(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(ski), nil
+}
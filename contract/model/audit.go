@@ -0,0 +1,137 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// AuditMethodList 需要记录审计日志的合约方法，即所有修改合约状态的方法
+var AuditMethodList = []string{
+	Method_AddDidDocument,
+	Method_UpdateDidDocument,
+	Method_AddBlackList,
+	Method_DeleteBlackList,
+	Method_AddTrustIssuer,
+	Method_DeleteTrustIssuer,
+	Method_AccreditIssuer,
+	Method_RevokeAccreditation,
+	Method_RevokeVc,
+	Method_SetVcTemplate,
+	Method_SetAdmin,
+	Method_DeleteAdmin,
+	Method_GrantRole,
+	Method_RevokeRole,
+	Method_CreateProposal,
+	Method_ApproveProposal,
+	Method_CancelProposal,
+	Method_SetProposalQuorum,
+	Method_SetContractConfig,
+	Method_Migrate,
+	Method_VcIssueLog,
+	Method_SetStatusList,
+	Method_SuspendVc,
+	Method_UnsuspendVc,
+}
+
+// IsAuditMethod 判断合约方法是否需要记录审计日志
+// @params method 合约方法
+func IsAuditMethod(method string) bool {
+	for _, v := range AuditMethodList {
+		if v == method {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditRecord 合约审计日志记录
+type AuditRecord struct {
+	// Seq 审计日志序号，从1开始递增
+	Seq uint64 `json:"seq"`
+	// TxId 交易ID
+	TxId   string `json:"txId"`
+	Method string `json:"method"`
+	// Operator 操作者公钥的SKI，与GetSenderPk()保持一致
+	Operator string `json:"operator"`
+	// OperatorDid 操作者公钥对应的DID，没有时为空
+	OperatorDid string `json:"operatorDid,omitempty"`
+	// ParamsDigest 交易参数的摘要，计算方法见ComputeParamsDigest
+	ParamsDigest string `json:"paramsDigest"`
+	// TxTime 交易时间
+	TxTime int64 `json:"txTime"`
+}
+
+// NewAuditRecord 新建审计日志记录
+// @params seq 审计日志序号
+// @params txId 交易ID
+// @params method 合约方法
+// @params operator 操作者公钥的SKI
+// @params operatorDid 操作者的DID
+// @params paramsDigest 交易参数的摘要
+// @params txTime 交易时间
+func NewAuditRecord(seq uint64, txId, method, operator, operatorDid, paramsDigest string,
+	txTime int64) *AuditRecord {
+	return &AuditRecord{
+		Seq:          seq,
+		TxId:         txId,
+		Method:       method,
+		Operator:     operator,
+		OperatorDid:  operatorDid,
+		ParamsDigest: paramsDigest,
+		TxTime:       txTime,
+	}
+}
+
+// ComputeParamsDigest 计算交易参数的摘要
+// 参数按key排序后依次写入 key、0x00、value、0x00，计算SHA256并十六进制编码
+// @params args 交易参数
+func ComputeParamsDigest(args map[string][]byte) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(args[k])
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Match 判断审计日志是否满足查询条件，条件为空或0表示不限制
+// @params method 合约方法
+// @params operator 操作者公钥的SKI或DID
+// @params startTime 开始时间（包含）
+// @params endTime 结束时间（包含）
+func (r *AuditRecord) Match(method, operator string, startTime, endTime int64) bool {
+	if len(method) != 0 && r.Method != method {
+		return false
+	}
+
+	if len(operator) != 0 && r.Operator != operator && r.OperatorDid != operator {
+		return false
+	}
+
+	if startTime > 0 && r.TxTime < startTime {
+		return false
+	}
+
+	if endTime > 0 && r.TxTime > endTime {
+		return false
+	}
+
+	return true
+}
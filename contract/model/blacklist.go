@@ -0,0 +1,79 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+)
+
+const (
+	// BlackListReasonUnspecified 未说明原因
+	BlackListReasonUnspecified = 0
+	// BlackListReasonKeyCompromise 密钥泄露
+	BlackListReasonKeyCompromise = 1
+	// BlackListReasonFraud 欺诈行为
+	BlackListReasonFraud = 2
+	// BlackListReasonLegalOrder 司法或监管要求
+	BlackListReasonLegalOrder = 3
+	// BlackListReasonViolation 违反平台规则
+	BlackListReasonViolation = 4
+)
+
+// BlackListRecord DID黑名单记录
+type BlackListRecord struct {
+	Did        string `json:"did"`
+	ReasonCode int    `json:"reasonCode"`
+	Reason     string `json:"reason,omitempty"`
+	// Operator 操作者公钥的SKI，与GetSenderPk()保持一致
+	Operator string `json:"operator,omitempty"`
+	// AddTime 加入黑名单的交易时间
+	AddTime int64 `json:"addTime"`
+	// ExpireTime 过期时间，0表示永久有效
+	ExpireTime int64 `json:"expireTime,omitempty"`
+}
+
+// NewBlackListRecord 新建DID黑名单记录
+// @params did 被加入黑名单的DID
+// @params reasonCode 原因编码
+// @params reason 原因描述
+// @params operator 操作者公钥的SKI
+// @params addTime 加入时间
+// @params expireTime 过期时间，0表示永久有效
+func NewBlackListRecord(did string, reasonCode int, reason, operator string,
+	addTime, expireTime int64) *BlackListRecord {
+	return &BlackListRecord{
+		Did:        did,
+		ReasonCode: reasonCode,
+		Reason:     reason,
+		Operator:   operator,
+		AddTime:    addTime,
+		ExpireTime: expireTime,
+	}
+}
+
+// ParseBlackListRecord 解析数据库中的黑名单记录
+// 早期版本的合约只存储了DID字符串，这里兼容为一条永久有效的记录
+func ParseBlackListRecord(value []byte) (*BlackListRecord, error) {
+	if len(value) != 0 && value[0] != '{' {
+		return &BlackListRecord{Did: string(value)}, nil
+	}
+
+	var record BlackListRecord
+	err := json.Unmarshal(value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// IsExpired 判断黑名单记录在给定时间是否已经过期
+// @params now 当前时间（Unix秒）
+func (r *BlackListRecord) IsExpired(now int64) bool {
+	return r.ExpireTime > 0 && now >= r.ExpireTime
+}
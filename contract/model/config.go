@@ -0,0 +1,24 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+// ContractConfig DID合约的运行时配置
+type ContractConfig struct {
+	// DidMethod DID Method，只能在安装合约时设置
+	DidMethod string `json:"didMethod"`
+	// EnableTrustIssuer 是否启用信任签发者
+	EnableTrustIssuer bool `json:"enableTrustIssuer"`
+	// MaxDocumentSize DID Document的最大字节数，0表示不限制
+	MaxDocumentSize int `json:"maxDocumentSize"`
+	// DefaultPageSize 列表查询未指定数量时返回的默认条数
+	DefaultPageSize int `json:"defaultPageSize"`
+	// ProposalQuorum 治理提案的法定人数，通过SetProposalQuorum设置
+	ProposalQuorum int `json:"proposalQuorum"`
+	// SchemaVersion 合约数据的版本，升级合约时自动迁移
+	SchemaVersion int `json:"schemaVersion"`
+}
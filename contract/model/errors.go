@@ -0,0 +1,169 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrorCode DID合约的错误码，错误码一经发布不再改变
+type ErrorCode int
+
+const (
+	// ErrCode_Unknown 未分类的错误
+	ErrCode_Unknown ErrorCode = 0
+
+	// ErrCode_InvalidParameter 参数缺失或格式错误
+	ErrCode_InvalidParameter ErrorCode = 1001
+	// ErrCode_PermissionDenied 没有操作权限
+	ErrCode_PermissionDenied ErrorCode = 1002
+	// ErrCode_MigrationNotFinished 合约数据迁移未完成
+	ErrCode_MigrationNotFinished ErrorCode = 1003
+
+	// ErrCode_DidNotFound DID文档不存在
+	ErrCode_DidNotFound ErrorCode = 2001
+	// ErrCode_DidAlreadyExists DID文档已存在
+	ErrCode_DidAlreadyExists ErrorCode = 2002
+	// ErrCode_InvalidDidDocument DID文档无效
+	ErrCode_InvalidDidDocument ErrorCode = 2003
+	// ErrCode_InBlackList DID在黑名单中
+	ErrCode_InBlackList ErrorCode = 2004
+
+	// ErrCode_NotTrustedIssuer 不是可信发行者
+	ErrCode_NotTrustedIssuer ErrorCode = 3001
+	// ErrCode_InvalidAccreditation 发行者授权无效，如超过授权深度或授权链断裂
+	ErrCode_InvalidAccreditation ErrorCode = 3002
+
+	// ErrCode_VcTemplateNotFound VC模板不存在
+	ErrCode_VcTemplateNotFound ErrorCode = 4001
+	// ErrCode_VcTemplateAlreadyExists VC模板已存在
+	ErrCode_VcTemplateAlreadyExists ErrorCode = 4002
+	// ErrCode_InvalidVcTemplate VC模板无效
+	ErrCode_InvalidVcTemplate ErrorCode = 4003
+	// ErrCode_InvalidCredential VC或VP无效，如格式错误或签名验证失败
+	ErrCode_InvalidCredential ErrorCode = 4004
+	// ErrCode_Revoked VC已被吊销
+	ErrCode_Revoked ErrorCode = 4005
+	// ErrCode_Expired VC或VP已过期
+	ErrCode_Expired ErrorCode = 4006
+	// ErrCode_StatusListNotFound VC的状态列表凭证不存在
+	ErrCode_StatusListNotFound ErrorCode = 4007
+	// ErrCode_Suspended VC已被暂停
+	ErrCode_Suspended ErrorCode = 4008
+
+	// ErrCode_ProposalNotFound 提案不存在
+	ErrCode_ProposalNotFound ErrorCode = 5001
+	// ErrCode_InvalidProposalStatus 提案状态不允许当前操作，如已过期或已执行
+	ErrCode_InvalidProposalStatus ErrorCode = 5002
+	// ErrCode_GovernanceRequired 开启治理后操作必须通过提案执行
+	ErrCode_GovernanceRequired ErrorCode = 5003
+)
+
+// 与错误码对应的哨兵错误，可以通过errors.Is判断错误类型
+var (
+	ErrInvalidParameter        = &Error{Code: ErrCode_InvalidParameter, Message: "invalid parameter"}
+	ErrPermissionDenied        = &Error{Code: ErrCode_PermissionDenied, Message: "no operation permission"}
+	ErrMigrationNotFinished    = &Error{Code: ErrCode_MigrationNotFinished, Message: "the migration is not finished"}
+	ErrDidNotFound             = &Error{Code: ErrCode_DidNotFound, Message: "did not found"}
+	ErrDidAlreadyExists        = &Error{Code: ErrCode_DidAlreadyExists, Message: "did already exists"}
+	ErrInvalidDidDocument      = &Error{Code: ErrCode_InvalidDidDocument, Message: "invalid did document"}
+	ErrInBlackList             = &Error{Code: ErrCode_InBlackList, Message: "the did is in black list"}
+	ErrNotTrustedIssuer        = &Error{Code: ErrCode_NotTrustedIssuer, Message: "not a trusted issuer"}
+	ErrInvalidAccreditation    = &Error{Code: ErrCode_InvalidAccreditation, Message: "invalid accreditation"}
+	ErrVcTemplateNotFound      = &Error{Code: ErrCode_VcTemplateNotFound, Message: "vc template not found"}
+	ErrVcTemplateAlreadyExists = &Error{Code: ErrCode_VcTemplateAlreadyExists, Message: "vc template already exists"}
+	ErrInvalidVcTemplate       = &Error{Code: ErrCode_InvalidVcTemplate, Message: "invalid vc template"}
+	ErrInvalidCredential       = &Error{Code: ErrCode_InvalidCredential, Message: "invalid credential"}
+	ErrRevoked                 = &Error{Code: ErrCode_Revoked, Message: "the vc is revoked"}
+	ErrExpired                 = &Error{Code: ErrCode_Expired, Message: "expired"}
+	ErrStatusListNotFound      = &Error{Code: ErrCode_StatusListNotFound, Message: "status list not found"}
+	ErrSuspended               = &Error{Code: ErrCode_Suspended, Message: "the vc is suspended"}
+	ErrProposalNotFound        = &Error{Code: ErrCode_ProposalNotFound, Message: "proposal not found"}
+	ErrInvalidProposalStatus   = &Error{Code: ErrCode_InvalidProposalStatus, Message: "invalid proposal status"}
+	ErrGovernanceRequired      = &Error{Code: ErrCode_GovernanceRequired, Message: "governance required"}
+)
+
+// errorPrefix 合约返回消息中错误码的前缀，格式为"[DID-<code>] <message>"
+const errorPrefix = "[DID-"
+
+// Error 带错误码的DID合约错误
+// 错误码相同的错误通过errors.Is判断时相等，与错误消息无关
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+// NewError 新建带错误码的错误
+// @params code 错误码
+// @params format 错误消息的格式
+// @params args 错误消息的参数
+func NewError(code ErrorCode, format string, args ...interface{}) *Error {
+	msg := format
+	if len(args) != 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	return &Error{Code: code, Message: msg}
+}
+
+// Error 错误消息
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is 错误码相同时返回true
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WrapError 为错误加上错误码，错误链中已有错误码时保留原错误码，错误消息不变
+// @params code 错误链中没有错误码时使用的错误码
+// @params err 错误
+func WrapError(code ErrorCode, err error) *Error {
+	if c := CodeOf(err); c != ErrCode_Unknown {
+		code = c
+	}
+	return &Error{Code: code, Message: err.Error()}
+}
+
+// CodeOf 获取错误链中的错误码，没有错误码时返回ErrCode_Unknown
+// @params err 错误
+func CodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ErrCode_Unknown
+}
+
+// FormatErrorMessage 将错误格式化为合约返回的消息，有错误码时加上"[DID-<code>] "前缀
+// @params err 错误
+func FormatErrorMessage(err error) string {
+	code := CodeOf(err)
+	if code == ErrCode_Unknown {
+		return err.Error()
+	}
+	return errorPrefix + strconv.Itoa(int(code)) + "] " + err.Error()
+}
+
+// ParseErrorMessage 解析合约返回的消息，消息没有错误码前缀时错误码为ErrCode_Unknown
+// @params msg 合约返回的消息
+func ParseErrorMessage(msg string) *Error {
+	if strings.HasPrefix(msg, errorPrefix) {
+		rest := msg[len(errorPrefix):]
+		if i := strings.Index(rest, "] "); i > 0 {
+			if code, err := strconv.Atoi(rest[:i]); err == nil {
+				return &Error{Code: ErrorCode(code), Message: rest[i+2:]}
+			}
+		}
+	}
+	return &Error{Code: ErrCode_Unknown, Message: msg}
+}
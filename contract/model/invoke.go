@@ -34,6 +34,14 @@ const (
 	Method_GetTrustIssuer = "GetTrustIssuer"
 	// Method_DeleteTrustIssuer method "DeleteTrustIssuer"
 	Method_DeleteTrustIssuer = "DeleteTrustIssuer"
+	// Method_GetTrustIssuerInfo method "GetTrustIssuerInfo"
+	Method_GetTrustIssuerInfo = "GetTrustIssuerInfo"
+	// Method_AccreditIssuer method "AccreditIssuer"
+	Method_AccreditIssuer = "AccreditIssuer"
+	// Method_RevokeAccreditation method "RevokeAccreditation"
+	Method_RevokeAccreditation = "RevokeAccreditation"
+	// Method_GetAccreditationChain method "GetAccreditationChain"
+	Method_GetAccreditationChain = "GetAccreditationChain"
 	// Method_RevokeVc method "RevokeVc"
 	Method_RevokeVc = "RevokeVc"
 	// Method_GetRevokedVcList method "GetRevokedVcList"
@@ -48,16 +56,64 @@ const (
 	Method_VerifyVc = "VerifyVc"
 	// Method_VerifyVp method "VerifyVp"
 	Method_VerifyVp = "VerifyVp"
+	// Method_VerifyVcReport method "VerifyVcReport"
+	Method_VerifyVcReport = "VerifyVcReport"
+	// Method_VerifyVpReport method "VerifyVpReport"
+	Method_VerifyVpReport = "VerifyVpReport"
 	// Method_SetAdmin method "SetAdmin"
 	Method_SetAdmin = "SetAdmin"
 	// Method_DeleteAdmin method "DeleteAdmin"
 	Method_DeleteAdmin = "DeleteAdmin"
 	// Method_IsAdmin method "IsAdmin"
 	Method_IsAdmin = "IsAdmin"
+	// Method_GetAdminList method "GetAdminList"
+	Method_GetAdminList = "GetAdminList"
+	// Method_GrantRole method "GrantRole"
+	Method_GrantRole = "GrantRole"
+	// Method_RevokeRole method "RevokeRole"
+	Method_RevokeRole = "RevokeRole"
+	// Method_GetRoleList method "GetRoleList"
+	Method_GetRoleList = "GetRoleList"
+	// Method_CreateProposal method "CreateProposal"
+	Method_CreateProposal = "CreateProposal"
+	// Method_ApproveProposal method "ApproveProposal"
+	Method_ApproveProposal = "ApproveProposal"
+	// Method_CancelProposal method "CancelProposal"
+	Method_CancelProposal = "CancelProposal"
+	// Method_GetProposal method "GetProposal"
+	Method_GetProposal = "GetProposal"
+	// Method_GetProposalList method "GetProposalList"
+	Method_GetProposalList = "GetProposalList"
+	// Method_SetProposalQuorum method "SetProposalQuorum"
+	Method_SetProposalQuorum = "SetProposalQuorum"
+	// Method_GetProposalQuorum method "GetProposalQuorum"
+	Method_GetProposalQuorum = "GetProposalQuorum"
 	// Method_VcIssueLog method "VcIssueLog"
 	Method_VcIssueLog = "VcIssueLog"
 	// Method_GetVcIssueLogs method "GetVcIssueLogs"
 	Method_GetVcIssueLogs = "GetVcIssueLogs"
+	// Method_GetAuditLog method "GetAuditLog"
+	Method_GetAuditLog = "GetAuditLog"
+	// Method_GetContractConfig method "GetContractConfig"
+	Method_GetContractConfig = "GetContractConfig"
+	// Method_SetContractConfig method "SetContractConfig"
+	Method_SetContractConfig = "SetContractConfig"
+	// Method_Migrate method "Migrate"
+	Method_Migrate = "Migrate"
+	// Method_GetMigrationStatus method "GetMigrationStatus"
+	Method_GetMigrationStatus = "GetMigrationStatus"
+	// Method_SetStatusList method "SetStatusList"
+	Method_SetStatusList = "SetStatusList"
+	// Method_GetStatusList method "GetStatusList"
+	Method_GetStatusList = "GetStatusList"
+	// Method_SuspendVc method "SuspendVc"
+	Method_SuspendVc = "SuspendVc"
+	// Method_UnsuspendVc method "UnsuspendVc"
+	Method_UnsuspendVc = "UnsuspendVc"
+	// Method_GetVcSuspension method "GetVcSuspension"
+	Method_GetVcSuspension = "GetVcSuspension"
+	// Method_GetVcRevocation method "GetVcRevocation"
+	Method_GetVcRevocation = "GetVcRevocation"
 )
 
 const (
@@ -77,6 +133,36 @@ const (
 	Topic_SetVcTemplate = "DidTopic_SetVcTemplate"
 	// Topic_VcIssueLog event topic "VcIssueLog"
 	Topic_VcIssueLog = "DidTopic_VcIssueLog"
+	// Topic_AccreditIssuer contract event topic "AccreditIssuer"
+	Topic_AccreditIssuer = "DidTopic_AccreditIssuer"
+	// Topic_RevokeAccreditation contract event topic "RevokeAccreditation"
+	Topic_RevokeAccreditation = "DidTopic_RevokeAccreditation"
+	// Topic_GrantRole contract event topic "GrantRole"
+	Topic_GrantRole = "DidTopic_GrantRole"
+	// Topic_RevokeRole contract event topic "RevokeRole"
+	Topic_RevokeRole = "DidTopic_RevokeRole"
+	// Topic_SetAdmin contract event topic "SetAdmin"
+	Topic_SetAdmin = "DidTopic_SetAdmin"
+	// Topic_DeleteAdmin contract event topic "DeleteAdmin"
+	Topic_DeleteAdmin = "DidTopic_DeleteAdmin"
+	// Topic_CreateProposal contract event topic "CreateProposal"
+	Topic_CreateProposal = "DidTopic_CreateProposal"
+	// Topic_ApproveProposal contract event topic "ApproveProposal"
+	Topic_ApproveProposal = "DidTopic_ApproveProposal"
+	// Topic_ExecuteProposal contract event topic "ExecuteProposal"
+	Topic_ExecuteProposal = "DidTopic_ExecuteProposal"
+	// Topic_CancelProposal contract event topic "CancelProposal"
+	Topic_CancelProposal = "DidTopic_CancelProposal"
+	// Topic_SetContractConfig contract event topic "SetContractConfig"
+	Topic_SetContractConfig = "DidTopic_SetContractConfig"
+	// Topic_Migrate contract event topic "Migrate"
+	Topic_Migrate = "DidTopic_Migrate"
+	// Topic_SetStatusList contract event topic "SetStatusList"
+	Topic_SetStatusList = "DidTopic_SetStatusList"
+	// Topic_SuspendVc contract event topic "SuspendVc"
+	Topic_SuspendVc = "DidTopic_SuspendVc"
+	// Topic_UnsuspendVc contract event topic "UnsuspendVc"
+	Topic_UnsuspendVc = "DidTopic_UnsuspendVc"
 )
 
 const (
@@ -106,6 +192,8 @@ const (
 	Params_VcIdSearch = "vcIdSearch"
 	// Params_VcTemplateId parameter of the contract method
 	Params_VcTemplateId = "vcTemplateId"
+	// Params_VcTemplateIdList parameter of the contract method
+	Params_VcTemplateIdList = "vcTemplateIds"
 	// Params_VcTemplateName parameter of the contract method
 	Params_VcTemplateName = "vcTemplateName"
 	// Params_VcTemplate parameter of the contract method
@@ -122,4 +210,46 @@ const (
 	Params_Ski = "ski"
 	// Params_Issuer parameter of the contract method
 	Params_Issuer = "issuer"
+	// Params_ReasonCode parameter of the contract method
+	Params_ReasonCode = "reasonCode"
+	// Params_Reason parameter of the contract method
+	Params_Reason = "reason"
+	// Params_ExpireTime parameter of the contract method
+	Params_ExpireTime = "expireTime"
+	// Params_Delegable parameter of the contract method
+	Params_Delegable = "delegable"
+	// Params_MaxDepth parameter of the contract method
+	Params_MaxDepth = "maxDepth"
+	// Params_Role parameter of the contract method
+	Params_Role = "role"
+	// Params_Member parameter of the contract method
+	Params_Member = "member"
+	// Params_ProposalId parameter of the contract method
+	Params_ProposalId = "proposalId"
+	// Params_ProposalAction parameter of the contract method
+	Params_ProposalAction = "action"
+	// Params_ProposalParams parameter of the contract method
+	Params_ProposalParams = "actionParams"
+	// Params_ProposalStatus parameter of the contract method
+	Params_ProposalStatus = "status"
+	// Params_Quorum parameter of the contract method
+	Params_Quorum = "quorum"
+	// Params_AuditMethod parameter of the contract method
+	Params_AuditMethod = "auditMethod"
+	// Params_Operator parameter of the contract method
+	Params_Operator = "operator"
+	// Params_StartTime parameter of the contract method
+	Params_StartTime = "startTime"
+	// Params_EndTime parameter of the contract method
+	Params_EndTime = "endTime"
+	// Params_MaxDocumentSize parameter of the contract method
+	Params_MaxDocumentSize = "maxDocumentSize"
+	// Params_DefaultPageSize parameter of the contract method
+	Params_DefaultPageSize = "defaultPageSize"
+	// Params_MigrationBatch parameter of the contract method
+	Params_MigrationBatch = "migrationBatch"
+	// Params_StatusListCredential parameter of the contract method
+	Params_StatusListCredential = "statusListCredential"
+	// Params_StatusListId parameter of the contract method
+	Params_StatusListId = "statusListId"
 )
@@ -0,0 +1,170 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+)
+
+// TrustIssuer 信任签发者记录
+type TrustIssuer struct {
+	Did string `json:"did"`
+	// TemplateIds 允许签发的VC模板ID列表，为空表示可以签发所有模板
+	TemplateIds []string `json:"templateIds,omitempty"`
+	// Accreditor 认证该签发者的上级签发者DID，由管理员直接添加的为空
+	Accreditor string `json:"accreditor,omitempty"`
+	// Delegable 是否有权认证下级签发者
+	Delegable bool `json:"delegable,omitempty"`
+	// MaxDepth 可以向下认证的最大层数，0表示不限制
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// AccreditTime 认证时间
+	AccreditTime int64 `json:"accreditTime,omitempty"`
+}
+
+// NewTrustIssuer 新建信任签发者记录
+// @params did 签发者DID
+// @params templateIds 允许签发的VC模板ID列表，为空表示不限制
+func NewTrustIssuer(did string, templateIds []string) *TrustIssuer {
+	return &TrustIssuer{
+		Did:         did,
+		TemplateIds: templateIds,
+	}
+}
+
+// NewAccreditedIssuer 新建由上级签发者认证的签发者记录
+// @params did 签发者DID
+// @params accreditor 上级签发者DID
+// @params templateIds 允许签发的VC模板ID列表
+// @params delegable 是否有权继续认证下级签发者
+// @params maxDepth 可以向下认证的最大层数，0表示不限制
+// @params accreditTime 认证时间
+func NewAccreditedIssuer(did, accreditor string, templateIds []string, delegable bool,
+	maxDepth int, accreditTime int64) *TrustIssuer {
+	return &TrustIssuer{
+		Did:          did,
+		TemplateIds:  templateIds,
+		Accreditor:   accreditor,
+		Delegable:    delegable,
+		MaxDepth:     maxDepth,
+		AccreditTime: accreditTime,
+	}
+}
+
+// ParseTrustIssuer 解析数据库中的信任签发者记录
+// 早期版本的合约只存储了DID字符串，这里兼容为一条不限制模板的记录
+func ParseTrustIssuer(value []byte) (*TrustIssuer, error) {
+	if len(value) != 0 && value[0] != '{' {
+		return &TrustIssuer{Did: string(value)}, nil
+	}
+
+	var issuer TrustIssuer
+	err := json.Unmarshal(value, &issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuer, nil
+}
+
+// IsTrustedFor 判断签发者是否可以签发指定模板的VC
+// @params templateId VC模板ID
+func (t *TrustIssuer) IsTrustedFor(templateId string) bool {
+	if len(t.TemplateIds) == 0 {
+		return true
+	}
+
+	for _, v := range t.TemplateIds {
+		if v == templateId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaxAccreditationChainLength 认证链的最大长度，防止环路和过长的链
+const MaxAccreditationChainLength = 16
+
+// BuildAccreditationChain 从签发者开始逐级查找认证链，任何一级缺失都会返回错误
+// 返回的列表从签发者本身开始，依次为上级签发者，最后一个为管理员添加的信任签发者
+// @params did 签发者DID
+// @params getTrustIssuer 获取信任签发者记录，不存在时返回nil
+func BuildAccreditationChain(did string, getTrustIssuer func(did string) (*TrustIssuer, error)) ([]*TrustIssuer,
+	error) {
+	chain := make([]*TrustIssuer, 0)
+
+	current := did
+	for i := 0; i < MaxAccreditationChainLength; i++ {
+		issuer, err := getTrustIssuer(current)
+		if err != nil {
+			return nil, err
+		}
+
+		if issuer == nil {
+			if i == 0 {
+				return nil, NewError(ErrCode_NotTrustedIssuer, "the did is not a trusted issuer")
+			}
+			return nil, NewError(ErrCode_InvalidAccreditation,
+				"the accreditation chain is broken, accreditor: [%s]", current)
+		}
+
+		chain = append(chain, issuer)
+
+		if len(issuer.Accreditor) == 0 {
+			return chain, nil
+		}
+
+		current = issuer.Accreditor
+	}
+
+	return nil, NewError(ErrCode_InvalidAccreditation, "the accreditation chain is too long")
+}
+
+// VerifyAccreditationChain 校验认证链上的每一级上级签发者：
+// 必须具有认证权限、下级所在层数不超过其限制，并且不在黑名单中
+// @params chain 认证链，见BuildAccreditationChain
+// @params isInBlackList 判断DID是否在黑名单中
+func VerifyAccreditationChain(chain []*TrustIssuer, isInBlackList func(did string) (bool, error)) error {
+	for i := 1; i < len(chain); i++ {
+		accreditor := chain[i]
+
+		if !accreditor.Delegable {
+			return NewError(ErrCode_InvalidAccreditation,
+				"the accreditor has no right to accredit issuers, did: [%s]", accreditor.Did)
+		}
+
+		if accreditor.MaxDepth > 0 && i > accreditor.MaxDepth {
+			return NewError(ErrCode_InvalidAccreditation,
+				"exceeds the accreditation depth limit of the accreditor, did: [%s]", accreditor.Did)
+		}
+
+		black, err := isInBlackList(accreditor.Did)
+		if err != nil {
+			return err
+		}
+
+		if black {
+			return NewError(ErrCode_InBlackList, "the accreditor is in the blacklist, did: [%s]", accreditor.Did)
+		}
+	}
+
+	return nil
+}
+
+// IsChainTrustedFor 判断认证链上的每一级签发者都可以签发指定模板的VC
+// @params chain 认证链，见BuildAccreditationChain
+// @params templateId VC模板ID
+func IsChainTrustedFor(chain []*TrustIssuer, templateId string) bool {
+	for _, issuer := range chain {
+		if !issuer.IsTrustedFor(templateId) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,23 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+// MigrationStatus 合约数据迁移状态
+type MigrationStatus struct {
+	// SchemaVersion 当前数据的版本，早期版本的合约没有记录时为0
+	SchemaVersion int `json:"schemaVersion"`
+	// LatestVersion 当前合约代码对应的数据版本
+	LatestVersion int `json:"latestVersion"`
+	// Cursor 正在执行的迁移步骤中断的位置，迁移完成时为空
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// IsDone 判断数据迁移是否已经完成
+func (s *MigrationStatus) IsDone() bool {
+	return s.SchemaVersion >= s.LatestVersion
+}
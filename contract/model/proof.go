@@ -28,6 +28,7 @@ type Proof struct {
 	Created            string `json:"created"`
 	ProofPurpose       string `json:"proofPurpose"`
 	Challenge          string `json:"challenge,omitempty"`
+	Domain             string `json:"domain,omitempty"`
 	VerificationMethod string `json:"verificationMethod"`
 	Jws                string `json:"jws,omitempty"`
 	ProofValue         string `json:"proofValue,omitempty"`
@@ -0,0 +1,103 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+const (
+	// ProposalStatus_Pending 等待审批
+	ProposalStatus_Pending = "pending"
+	// ProposalStatus_Executed 已达到法定人数并执行
+	ProposalStatus_Executed = "executed"
+	// ProposalStatus_Canceled 已被提案者取消
+	ProposalStatus_Canceled = "canceled"
+	// ProposalStatus_Expired 已过期，查询时根据交易时间判断
+	ProposalStatus_Expired = "expired"
+)
+
+// DefaultProposalExpiration 提案默认有效期（秒），7天
+const DefaultProposalExpiration = 7 * 24 * 60 * 60
+
+// ProposalActionList 可以通过提案执行的合约方法
+var ProposalActionList = []string{
+	Method_AddBlackList,
+	Method_DeleteBlackList,
+	Method_AddTrustIssuer,
+	Method_DeleteTrustIssuer,
+	Method_SetAdmin,
+	Method_DeleteAdmin,
+	Method_SetVcTemplate,
+	Method_GrantRole,
+	Method_RevokeRole,
+	Method_SetProposalQuorum,
+	Method_SetContractConfig,
+}
+
+// IsValidProposalAction 判断合约方法是否可以通过提案执行
+// @params action 合约方法名
+func IsValidProposalAction(action string) bool {
+	for _, a := range ProposalActionList {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Proposal 管理员治理提案
+type Proposal struct {
+	Id string `json:"id"`
+	// Action 提案要执行的合约方法，如AddBlackList
+	Action string `json:"action"`
+	// Params 执行合约方法的参数，key与合约方法的参数名保持一致
+	Params map[string]string `json:"params"`
+	// Proposer 提案者公钥的SKI
+	Proposer string `json:"proposer"`
+	// Approvals 已同意的管理员公钥SKI列表（包含提案者）
+	Approvals   []string `json:"approvals"`
+	Status      string   `json:"status"`
+	CreateTime  int64    `json:"createTime"`
+	ExpireTime  int64    `json:"expireTime"`
+	ExecuteTime int64    `json:"executeTime,omitempty"`
+}
+
+// NewProposal 新建提案，提案者默认同意
+// @params id 提案ID
+// @params action 要执行的合约方法
+// @params params 合约方法的参数
+// @params proposer 提案者公钥的SKI
+// @params createTime 创建时间
+// @params expireTime 过期时间
+func NewProposal(id, action string, params map[string]string, proposer string,
+	createTime, expireTime int64) *Proposal {
+	return &Proposal{
+		Id:         id,
+		Action:     action,
+		Params:     params,
+		Proposer:   proposer,
+		Approvals:  []string{proposer},
+		Status:     ProposalStatus_Pending,
+		CreateTime: createTime,
+		ExpireTime: expireTime,
+	}
+}
+
+// IsExpired 判断未执行的提案在给定时间是否已经过期
+// @params now 当前时间（Unix秒）
+func (p *Proposal) IsExpired(now int64) bool {
+	return p.Status == ProposalStatus_Pending && now >= p.ExpireTime
+}
+
+// HasApproved 判断管理员是否已经同意提案
+// @params ski 管理员公钥的SKI
+func (p *Proposal) HasApproved(ski string) bool {
+	for _, v := range p.Approvals {
+		if v == ski {
+			return true
+		}
+	}
+	return false
+}
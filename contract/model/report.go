@@ -0,0 +1,134 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 验证报告中的检查项
+const (
+	// VerifyCheck_Format 凭证的json格式
+	VerifyCheck_Format = "format"
+	// VerifyCheck_Type 凭证的类型
+	VerifyCheck_Type = "type"
+	// VerifyCheck_Date 签发时间和过期时间
+	VerifyCheck_Date = "date"
+	// VerifyCheck_Template VC模板及凭证主体是否符合模板的Schema
+	VerifyCheck_Template = "template"
+	// VerifyCheck_IssuerTrust 签发者是否为可签发该模板的可信签发者
+	VerifyCheck_IssuerTrust = "issuerTrust"
+	// VerifyCheck_Revocation VC是否已被吊销
+	VerifyCheck_Revocation = "revocation"
+	// VerifyCheck_BlackList VC持有者或VP持有者是否在黑名单中
+	VerifyCheck_BlackList = "blackList"
+	// VerifyCheck_Signature 签名是否由签发者或持有者的DID文档中的公钥签署
+	VerifyCheck_Signature = "signature"
+	// VerifyCheck_HolderBinding VP中的VC是否都属于VP持有者
+	VerifyCheck_HolderBinding = "holderBinding"
+	// VerifyCheck_Credential VP中的VC是否都验证通过，详细结果见VerifyReport.Credentials
+	VerifyCheck_Credential = "credential"
+	// VerifyCheck_Challenge VP的证明是否绑定了验证者提供的挑战和域
+	VerifyCheck_Challenge = "challenge"
+)
+
+// VerifyCheck 验证报告中的一个检查项
+type VerifyCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Detail 检查项的说明，未通过时为失败原因
+	Detail string `json:"detail,omitempty"`
+	// Code 未通过时的错误码
+	Code ErrorCode `json:"code,omitempty"`
+}
+
+// VerifyReport VC或VP的验证报告，列出每个检查项的结果
+type VerifyReport struct {
+	// Id VC或VP的ID
+	Id     string         `json:"id"`
+	Passed bool           `json:"passed"`
+	Checks []*VerifyCheck `json:"checks"`
+	// Credentials VP中每个VC的验证报告
+	Credentials []*VerifyReport `json:"credentials,omitempty"`
+}
+
+// NewVerifyReport 新建验证报告
+// @params id VC或VP的ID
+func NewVerifyReport(id string) *VerifyReport {
+	return &VerifyReport{
+		Id:     id,
+		Passed: true,
+		Checks: make([]*VerifyCheck, 0),
+	}
+}
+
+// Pass 添加通过的检查项
+// @params name 检查项
+// @params detail 说明，可以为空
+func (r *VerifyReport) Pass(name, detail string) {
+	r.Checks = append(r.Checks, &VerifyCheck{Name: name, Passed: true, Detail: detail})
+}
+
+// Fail 添加未通过的检查项，报告结果为未通过
+// @params name 检查项
+// @params err 失败原因
+func (r *VerifyReport) Fail(name string, err error) {
+	r.Passed = false
+	r.Checks = append(r.Checks, &VerifyCheck{Name: name, Detail: err.Error(), Code: CodeOf(err)})
+}
+
+// Check 根据err添加检查项，err为nil时检查通过
+// @params name 检查项
+// @params err 检查的结果
+func (r *VerifyReport) Check(name string, err error) {
+	if err != nil {
+		r.Fail(name, err)
+		return
+	}
+	r.Pass(name, "")
+}
+
+// Err 返回第一个未通过的检查项对应的错误，全部通过时返回nil
+func (r *VerifyReport) Err() error {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return &Error{Code: c.Code, Message: c.Detail}
+		}
+	}
+	return nil
+}
+
+// String 按行输出每个检查项的结果，VP中的VC报告缩进输出
+func (r *VerifyReport) String() string {
+	var b strings.Builder
+	r.write(&b, "")
+	return b.String()
+}
+
+func (r *VerifyReport) write(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s%s: %s\n", indent, r.Id, passedString(r.Passed))
+	for _, c := range r.Checks {
+		fmt.Fprintf(b, "%s  - %s: %s", indent, c.Name, passedString(c.Passed))
+		if len(c.Detail) != 0 {
+			fmt.Fprintf(b, " (%s)", c.Detail)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, cr := range r.Credentials {
+		cr.write(b, indent+"    ")
+	}
+}
+
+func passedString(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
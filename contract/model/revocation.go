@@ -0,0 +1,80 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+)
+
+const (
+	// RevocationReasonUnspecified 未说明原因
+	RevocationReasonUnspecified = 0
+	// RevocationReasonKeyCompromise 持有者密钥泄露
+	RevocationReasonKeyCompromise = 1
+	// RevocationReasonSuperseded 已被新的VC替代
+	RevocationReasonSuperseded = 2
+	// RevocationReasonCessation 凭证对应的资格已终止
+	RevocationReasonCessation = 3
+	// RevocationReasonFraud 签发时信息不实或存在欺诈
+	RevocationReasonFraud = 4
+)
+
+// VcRevocation VC吊销记录，同一个VC编号在不同签发者下的吊销记录互不影响
+type VcRevocation struct {
+	// Issuer VC的签发者DID，为空表示早期版本合约中签发者未知的吊销记录，对所有签发者生效
+	Issuer     string `json:"issuer,omitempty"`
+	VcId       string `json:"vcId"`
+	ReasonCode int    `json:"reasonCode"`
+	Reason     string `json:"reason,omitempty"`
+	// Revoker 吊销者公钥的SKI，与GetSenderPk()保持一致
+	Revoker string `json:"revoker,omitempty"`
+	// RevokeTime 吊销的交易时间
+	RevokeTime int64 `json:"revokeTime"`
+}
+
+// NewVcRevocation 新建VC吊销记录
+// @params issuer VC的签发者DID
+// @params vcId VC业务编号
+// @params reasonCode 原因编码
+// @params reason 原因描述
+// @params revoker 吊销者公钥的SKI
+// @params revokeTime 吊销时间
+func NewVcRevocation(issuer, vcId string, reasonCode int, reason, revoker string,
+	revokeTime int64) *VcRevocation {
+	return &VcRevocation{
+		Issuer:     issuer,
+		VcId:       vcId,
+		ReasonCode: reasonCode,
+		Reason:     reason,
+		Revoker:    revoker,
+		RevokeTime: revokeTime,
+	}
+}
+
+// ParseVcRevocation 解析数据库中的吊销记录
+// 早期版本的合约只存储了VC编号，这里兼容为一条签发者未知的记录
+func ParseVcRevocation(value []byte) (*VcRevocation, error) {
+	if len(value) != 0 && value[0] != '{' {
+		return &VcRevocation{VcId: string(value)}, nil
+	}
+
+	var record VcRevocation
+	err := json.Unmarshal(value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Matches 判断吊销记录是否适用于指定签发者的VC
+// @params issuer VC的签发者DID
+// @params vcId VC业务编号
+func (r *VcRevocation) Matches(issuer, vcId string) bool {
+	return r.VcId == vcId && (len(r.Issuer) == 0 || r.Issuer == issuer)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"strings"
+)
+
+const (
+	// Role_TemplateManager 模板管理员，可以设置VC模板
+	Role_TemplateManager = "template-manager"
+	// Role_BlackListManager 黑名单管理员，可以添加、删除黑名单
+	Role_BlackListManager = "blacklist-manager"
+	// Role_IssuerManager 签发者管理员，可以添加、删除信任签发者以及撤销签发者认证
+	Role_IssuerManager = "issuer-manager"
+	// Role_DidOperator DID操作员，可以更新任意DID Document
+	Role_DidOperator = "did-operator"
+	// Role_VcManager VC管理员，可以撤销任意VC
+	Role_VcManager = "vc-manager"
+)
+
+const (
+	// RoleMemberType_Ski 以公钥SKI授权的成员
+	RoleMemberType_Ski = "ski"
+	// RoleMemberType_Did 以DID授权的成员
+	RoleMemberType_Did = "did"
+)
+
+// RoleList 合约支持的所有角色
+var RoleList = []string{
+	Role_TemplateManager,
+	Role_BlackListManager,
+	Role_IssuerManager,
+	Role_DidOperator,
+	Role_VcManager,
+}
+
+// IsValidRole 判断是否是合约支持的角色
+// @params role 角色名称
+func IsValidRole(role string) bool {
+	for _, r := range RoleList {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleMember 角色成员记录
+type RoleMember struct {
+	Role string `json:"role"`
+	// Member 成员的公钥SKI（与GetSenderPk()保持一致）或DID
+	Member string `json:"member"`
+	// MemberType 成员类型，ski或did
+	MemberType string `json:"memberType"`
+	// Operator 授权者公钥的SKI
+	Operator string `json:"operator,omitempty"`
+	// GrantTime 授权时间
+	GrantTime int64 `json:"grantTime"`
+}
+
+// GetRoleMemberType 根据成员字符串判断成员类型，以"did:"开头的为DID，其余为公钥SKI
+// @params member 成员的公钥SKI或DID
+func GetRoleMemberType(member string) string {
+	if strings.HasPrefix(member, "did:") {
+		return RoleMemberType_Did
+	}
+	return RoleMemberType_Ski
+}
+
+// NewRoleMember 新建角色成员记录
+// @params role 角色名称
+// @params member 成员的公钥SKI或DID
+// @params operator 授权者公钥的SKI
+// @params grantTime 授权时间
+func NewRoleMember(role, member, operator string, grantTime int64) *RoleMember {
+	return &RoleMember{
+		Role:       role,
+		Member:     member,
+		MemberType: GetRoleMemberType(member),
+		Operator:   operator,
+		GrantTime:  grantTime,
+	}
+}
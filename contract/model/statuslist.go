@@ -0,0 +1,227 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 状态列表相关的类型，见W3C Bitstring Status List
+const (
+	// CredentialStatusType_BitstringStatusList VC中credentialStatus的类型
+	CredentialStatusType_BitstringStatusList = "BitstringStatusListEntry"
+	// StatusListCredentialType 状态列表凭证的类型
+	StatusListCredentialType = "BitstringStatusListCredential"
+	// StatusListSubjectType 状态列表凭证主体的类型
+	StatusListSubjectType = "BitstringStatusList"
+)
+
+// 状态列表的用途
+const (
+	// StatusPurpose_Revocation 吊销，置位后不能恢复
+	StatusPurpose_Revocation = "revocation"
+)
+
+const (
+	// StatusListMinLength 状态列表的最小位数，列表足够大时验证者无法通过列表推断正在验证的VC
+	StatusListMinLength = 131072
+	// StatusListMaxLength 状态列表的最大位数，限制解压后的大小
+	StatusListMaxLength = 1 << 27
+
+	// multibaseBase64Url encodedList使用的multibase前缀，表示不带填充的base64url编码
+	multibaseBase64Url = "u"
+)
+
+// CredentialStatus VC的状态，指向状态列表凭证中的一位
+type CredentialStatus struct {
+	Id                   string `json:"id,omitempty"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose"`
+	StatusListIndex      string `json:"statusListIndex"`
+	StatusListCredential string `json:"statusListCredential"`
+}
+
+// NewCredentialStatus 新建指向状态列表中一位的VC状态
+// @params listCredentialId 状态列表凭证的ID
+// @params purpose 状态列表的用途
+// @params index 在状态列表中的位置
+func NewCredentialStatus(listCredentialId, purpose string, index int) *CredentialStatus {
+	i := strconv.Itoa(index)
+	return &CredentialStatus{
+		Id:                   listCredentialId + "#" + i,
+		Type:                 CredentialStatusType_BitstringStatusList,
+		StatusPurpose:        purpose,
+		StatusListIndex:      i,
+		StatusListCredential: listCredentialId,
+	}
+}
+
+// Index 获取VC在状态列表中的位置
+func (s *CredentialStatus) Index() (int, error) {
+	index, err := strconv.Atoi(s.StatusListIndex)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid status list index: [%s]", s.StatusListIndex)
+	}
+	return index, nil
+}
+
+// StatusList 状态位串，位置0为第一个字节的最高位
+type StatusList struct {
+	bits []byte
+}
+
+// NewStatusList 新建所有位都为0的状态列表
+// @params length 位数，不能小于StatusListMinLength，会向上取整为8的倍数
+func NewStatusList(length int) (*StatusList, error) {
+	if length < StatusListMinLength || length > StatusListMaxLength {
+		return nil, fmt.Errorf("the length of status list should be in [%d, %d]", StatusListMinLength,
+			StatusListMaxLength)
+	}
+
+	return &StatusList{bits: make([]byte, (length+7)/8)}, nil
+}
+
+// DecodeStatusList 解码状态列表凭证中的encodedList，即multibase base64url编码的GZIP压缩位串
+// @params encodedList 编码后的状态列表
+func DecodeStatusList(encodedList string) (*StatusList, error) {
+	if !strings.HasPrefix(encodedList, multibaseBase64Url) {
+		return nil, errors.New("the encoded list should be multibase base64url encoded")
+	}
+
+	compressed, err := base64.RawURLEncoding.DecodeString(encodedList[len(multibaseBase64Url):])
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	bits, err := io.ReadAll(io.LimitReader(r, StatusListMaxLength/8+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bits) > StatusListMaxLength/8 {
+		return nil, errors.New("the status list is too long")
+	}
+
+	return &StatusList{bits: bits}, nil
+}
+
+// Encode 将状态列表编码为状态列表凭证中的encodedList
+func (l *StatusList) Encode() (string, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(l.bits)
+	if err != nil {
+		return "", err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return multibaseBase64Url + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Len 状态列表的位数
+func (l *StatusList) Len() int {
+	return len(l.bits) * 8
+}
+
+// Get 获取指定位置的状态
+// @params index 位置
+func (l *StatusList) Get(index int) (bool, error) {
+	if index < 0 || index >= l.Len() {
+		return false, fmt.Errorf("the status list index [%d] is out of range", index)
+	}
+
+	return l.bits[index/8]&(0x80>>(index%8)) != 0, nil
+}
+
+// Set 设置指定位置的状态
+// @params index 位置
+// @params status 状态，true表示置位
+func (l *StatusList) Set(index int, status bool) error {
+	if index < 0 || index >= l.Len() {
+		return fmt.Errorf("the status list index [%d] is out of range", index)
+	}
+
+	if status {
+		l.bits[index/8] |= 0x80 >> (index % 8)
+	} else {
+		l.bits[index/8] &^= 0x80 >> (index % 8)
+	}
+
+	return nil
+}
+
+// Covers 判断状态列表是否保留了old中所有已置位的位且长度不小于old，吊销列表的更新必须满足该条件
+// @params old 更新前的状态列表
+func (l *StatusList) Covers(old *StatusList) bool {
+	if len(l.bits) < len(old.bits) {
+		return false
+	}
+
+	for i, b := range old.bits {
+		if b&^l.bits[i] != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsStatusListCredential 是否为状态列表凭证
+func (vc *VerifiableCredential) IsStatusListCredential() bool {
+	for _, t := range vc.Type {
+		if t == StatusListCredentialType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStatusList 获取状态列表凭证中的状态列表
+// @params purpose 状态列表的用途，与凭证主体中的statusPurpose不一致时返回错误，为空时不检查
+func (vc *VerifiableCredential) GetStatusList(purpose string) (*StatusList, error) {
+	if !vc.IsStatusListCredential() {
+		return nil, NewError(ErrCode_InvalidCredential, "the VC is not a status list credential")
+	}
+
+	subjectType, _ := vc.CredentialSubject["type"].(string)
+	if subjectType != StatusListSubjectType {
+		return nil, NewError(ErrCode_InvalidCredential, "invalid status list subject type: [%s]", subjectType)
+	}
+
+	subjectPurpose, _ := vc.CredentialSubject["statusPurpose"].(string)
+	if len(subjectPurpose) == 0 || (len(purpose) != 0 && subjectPurpose != purpose) {
+		return nil, NewError(ErrCode_InvalidCredential, "the status purpose of the list is [%s], expected [%s]",
+			subjectPurpose, purpose)
+	}
+
+	encodedList, _ := vc.CredentialSubject["encodedList"].(string)
+	list, err := DecodeStatusList(encodedList)
+	if err != nil {
+		return nil, NewError(ErrCode_InvalidCredential, "invalid encoded list: [%s]", err.Error())
+	}
+
+	return list, nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+// VcSuspension VC暂停或恢复的记录，暂停期间VC验证不通过，恢复后重新有效
+type VcSuspension struct {
+	// Issuer VC的签发者DID
+	Issuer string `json:"issuer"`
+	VcId   string `json:"vcId"`
+	Reason string `json:"reason,omitempty"`
+	// Operator 操作者公钥的SKI，与GetSenderPk()保持一致
+	Operator string `json:"operator,omitempty"`
+	// Time 暂停或恢复的交易时间
+	Time int64 `json:"time"`
+}
+
+// NewVcSuspension 新建VC暂停或恢复的记录
+// @params issuer VC的签发者DID
+// @params vcId VC业务编号
+// @params reason 原因描述
+// @params operator 操作者公钥的SKI
+// @params time 交易时间
+func NewVcSuspension(issuer, vcId, reason, operator string, time int64) *VcSuspension {
+	return &VcSuspension{
+		Issuer:   issuer,
+		VcId:     vcId,
+		Reason:   reason,
+		Operator: operator,
+		Time:     time,
+	}
+}
@@ -33,7 +33,9 @@ type VerifiableCredential struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
 	} `json:"template,omitempty"`
-	Proof *Proof `json:"proof,omitempty"`
+	// CredentialStatus VC在状态列表中的位置，没有时只通过链上吊销列表检查吊销状态
+	CredentialStatus *CredentialStatus `json:"credentialStatus,omitempty"`
+	Proof            *Proof            `json:"proof,omitempty"`
 }
 
 // NewVerifiableCredential 根据VC凭证json字符串创建VC凭证
@@ -61,64 +63,108 @@ func (vc *VerifiableCredential) GetCredentialSubjectID() (string, error) {
 // @params pkPem 公钥的PEM编码
 // @params template
 func (vc *VerifiableCredential) Verify(pkPem, template []byte) (bool, error) {
+	err := vc.VerifyType()
+	if err != nil {
+		return false, err
+	}
 
-	// Check if the VC type is correct
-	if len(vc.Type) == 0 {
-		return false, errors.New("invalid VC type")
-	} else {
-		var isVcType bool
-		for _, v := range vc.Type {
-			if v == "VerifiableCredential" {
-				isVcType = true
-			}
+	// 检查当前时间是否在有效期内
+	myTime, err := GetTxTime()
+	if err != nil {
+		return false, err
+	}
+
+	err = vc.VerifyDate(myTime)
+	if err != nil {
+		return false, err
+	}
+
+	// 验证模板字段
+	if template != nil {
+		err = vc.VerifyTemplate(template)
+		if err != nil {
+			return false, err
 		}
+	}
 
-		if !isVcType {
-			return false, errors.New("invalid VC type")
+	// 验签
+	return vc.verifySignature(pkPem)
+}
+
+// VerifyType 验证VC的类型包含VerifiableCredential
+func (vc *VerifiableCredential) VerifyType() error {
+	for _, v := range vc.Type {
+		if v == "VerifiableCredential" {
+			return nil
 		}
 	}
 
+	return NewError(ErrCode_InvalidCredential, "invalid VC type")
+}
+
+// VerifyDate 验证VC的签发时间和过期时间
+// @params now 当前时间的Unix时间戳，秒
+func (vc *VerifiableCredential) VerifyDate(now int64) error {
 	issuanceDate, err := time.Parse(time.RFC3339, vc.IssuanceDate)
 	if err != nil {
-		return false, err
+		return NewError(ErrCode_InvalidCredential, "invalid issuance date: [%s]", err.Error())
 	}
 
 	expirationDate, err := time.Parse(time.RFC3339, vc.ExpirationDate)
 	if err != nil {
-		return false, err
+		return NewError(ErrCode_InvalidCredential, "invalid expiration date: [%s]", err.Error())
 	}
 
 	if issuanceDate.After(expirationDate) {
-		return false, errors.New("issuance date is after the expiration date")
+		return NewError(ErrCode_InvalidCredential, "issuance date is after the expiration date")
 	}
 
-	// 检查当前时间是否在有效期内
-	myTime, err := GetTxTime()
+	if now < issuanceDate.Unix() || now > expirationDate.Unix() {
+		return NewError(ErrCode_Expired, "the verifiable credential has expired")
+	}
+
+	return nil
+}
+
+// VerifyTemplate 验证VC的凭证主体符合VC模板
+// @params template VC模板的json
+func (vc *VerifiableCredential) VerifyTemplate(template []byte) error {
+	ok, err := vc.verifyCredentialSubject(template)
+	if !ok {
+		return NewError(ErrCode_InvalidCredential, "credential subject verified failed, err: [%s]", err.Error())
+	}
+
+	return nil
+}
+
+// VerifySignature 验证VC的签名
+// @params pkPem 签发者公钥的PEM编码
+func (vc *VerifiableCredential) VerifySignature(pkPem []byte) error {
+	ok, err := vc.verifySignature(pkPem)
 	if err != nil {
-		return false, err
+		return WrapError(ErrCode_InvalidCredential, err)
 	}
 
-	if myTime < issuanceDate.Unix() || myTime > expirationDate.Unix() {
-		return false, errors.New("the verifiable credential has expired")
+	if !ok {
+		return NewError(ErrCode_InvalidCredential, "the signature of VC is invalid")
 	}
 
-	// 验证模板字段
-	if template != nil {
-		ok, err := vc.verifyCredentialSubject(template)
-		if !ok {
-			return false, fmt.Errorf("credential subject verified failed, err: [%s]", err.Error())
-		}
+	return nil
+}
+
+func (vc *VerifiableCredential) verifySignature(pkPem []byte) (bool, error) {
+	if vc.Proof == nil {
+		return false, NewError(ErrCode_InvalidCredential, "the proof of VC is missing")
 	}
 
 	// 删除proof字段
 	withoutProof := jsonparser.Delete(vc.rawData, "proof")
 	//去掉空格换行等
-	withoutProof, err = CompactJson(withoutProof)
+	withoutProof, err := CompactJson(withoutProof)
 	if err != nil {
 		return false, err
 	}
 
-	// 验签
 	return vc.Proof.Verify(withoutProof, pkPem)
 }
 
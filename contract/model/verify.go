@@ -0,0 +1,371 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// VerifySource 验证VC和VP时需要的DID文档、VC模板、吊销、暂停、黑名单和签发者信任数据
+// 合约使用链上状态实现，SDK可以使用链上查询、缓存或离线快照实现
+// 返回带错误码的错误时对应的检查项未通过，返回其他错误时停止验证
+type VerifySource interface {
+	// GetDidDocument 获取DID文档，不存在时返回nil
+	GetDidDocument(did string) ([]byte, error)
+	// GetVcTemplate 获取VC模板，不存在时返回nil
+	GetVcTemplate(id string) ([]byte, error)
+	// GetVcRevocation 获取签发者的VC的吊销记录，VC未被吊销时返回nil
+	GetVcRevocation(issuer, vcId string) (*VcRevocation, error)
+	// GetVcSuspension 获取签发者的VC的暂停记录，VC未被暂停时返回nil
+	GetVcSuspension(issuer, vcId string) (*VcSuspension, error)
+	// IsInBlackList DID是否在黑名单中
+	IsInBlackList(did string) (bool, error)
+	// VerifyTrustIssuer 检查签发者是否可以签发指定模板的VC，不可以时返回错误码为ErrCode_NotTrustedIssuer的错误
+	VerifyTrustIssuer(did, templateId string) error
+	// GetStatusListCredential 获取状态列表凭证，不存在时返回nil
+	GetStatusListCredential(id string) ([]byte, error)
+}
+
+// VerifyVc 逐项验证VC并返回验证报告，所有检查项都会执行
+// @params vc VC
+// @params now 当前时间的Unix时间戳，秒
+// @params src 验证需要的数据
+func VerifyVc(vc *VerifiableCredential, now int64, src VerifySource) (*VerifyReport, error) {
+	report := NewVerifyReport(vc.Id)
+
+	report.Check(VerifyCheck_Type, vc.VerifyType())
+	report.Check(VerifyCheck_Date, vc.VerifyDate(now))
+
+	// 检查VC模板，受模板范围限制的签发者只能签发范围内的模板
+	var templateId string
+	if vc.Template == nil {
+		report.Pass(VerifyCheck_Template, "the vc has no template")
+	} else {
+		templateId = vc.Template.ID
+		err := check(report, VerifyCheck_Template, verifyVcTemplate(vc, src))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 检查签发者是否可信任
+	err := check(report, VerifyCheck_IssuerTrust, src.VerifyTrustIssuer(vc.Issuer, templateId))
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查VC撤销和暂停状态
+	err = check(report, VerifyCheck_Revocation, verifyVcStatus(vc, now, src))
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查vc拥有者是否在黑名单中
+	subId, err := vc.GetCredentialSubjectID()
+	if err != nil {
+		report.Fail(VerifyCheck_BlackList, WrapError(ErrCode_InvalidCredential, err))
+	} else {
+		err = check(report, VerifyCheck_BlackList, verifyNotInBlackList(subId, "vc owner is in black list", src))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 使用签发者DID公钥验签
+	pkPem, err := getProofPkPem(vc.Issuer, "issuer", vc.Proof, src)
+	if err == nil {
+		err = vc.VerifySignature(pkPem)
+	}
+
+	err = check(report, VerifyCheck_Signature, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// VerifyVp 逐项验证VP并返回验证报告，VP中每个VC的验证报告在Credentials中
+// @params vp VP
+// @params now 当前时间的Unix时间戳，秒
+// @params src 验证需要的数据
+func VerifyVp(vp *VerifiablePresentation, now int64, src VerifySource) (*VerifyReport, error) {
+	report := NewVerifyReport(vp.Id)
+
+	report.Check(VerifyCheck_Type, vp.VerifyType())
+	report.Check(VerifyCheck_Date, vp.VerifyDate(now))
+
+	// 检查持有者是否在黑名单中
+	err := check(report, VerifyCheck_BlackList, verifyNotInBlackList(vp.Holder, "vp owner is in black list", src))
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查VP中的VC都属于持有者
+	report.Check(VerifyCheck_HolderBinding, vp.VerifyHolderBinding())
+
+	// 验证VP中的VC
+	var credentialErr error
+	for _, v := range vp.VerifiableCredential {
+		vcString, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		vc, err := NewVerifiableCredential(string(vcString))
+		if err != nil {
+			return nil, err
+		}
+
+		vcReport, err := VerifyVc(vc, now, src)
+		if err != nil {
+			return nil, err
+		}
+		report.Credentials = append(report.Credentials, vcReport)
+
+		if err = vcReport.Err(); err != nil && credentialErr == nil {
+			credentialErr = fmt.Errorf("vc verify failed, id: [%s], err: [%w]", vc.Id, err)
+		}
+	}
+	report.Check(VerifyCheck_Credential, credentialErr)
+
+	// 使用持有者DID公钥验签
+	pkPem, err := getProofPkPem(vp.Holder, "holder", vp.Proof, src)
+	if err == nil {
+		err = vp.VerifySignature(pkPem)
+	}
+
+	err = check(report, VerifyCheck_Signature, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// VerifyCredentialStatus 根据VC的credentialStatus检查VC在状态列表中的状态，VC已被吊销时返回错误码为ErrCode_Revoked的错误
+// 状态列表凭证必须由VC的签发者签发，且在有效期内
+// @params vc VC
+// @params now 当前时间的Unix时间戳，秒
+// @params src 验证需要的数据
+func VerifyCredentialStatus(vc *VerifiableCredential, now int64, src VerifySource) error {
+	status := vc.CredentialStatus
+	if status.Type != CredentialStatusType_BitstringStatusList {
+		return NewError(ErrCode_InvalidCredential, "unsupported credential status type: [%s]", status.Type)
+	}
+
+	if status.StatusPurpose != StatusPurpose_Revocation {
+		return NewError(ErrCode_InvalidCredential, "unsupported status purpose: [%s]", status.StatusPurpose)
+	}
+
+	index, err := status.Index()
+	if err != nil {
+		return WrapError(ErrCode_InvalidCredential, err)
+	}
+
+	listBytes, err := src.GetStatusListCredential(status.StatusListCredential)
+	if err != nil {
+		return err
+	}
+
+	if len(listBytes) == 0 {
+		return NewError(ErrCode_StatusListNotFound, "the status list credential was not found, id: [%s]",
+			status.StatusListCredential)
+	}
+
+	listVc, err := NewVerifiableCredential(string(listBytes))
+	if err != nil {
+		return NewError(ErrCode_InvalidCredential, "invalid status list credential: [%s]", err.Error())
+	}
+
+	if listVc.Id != status.StatusListCredential {
+		return NewError(ErrCode_InvalidCredential, "the id of status list credential is mismatched, id: [%s]",
+			listVc.Id)
+	}
+
+	if listVc.Issuer != vc.Issuer {
+		return NewError(ErrCode_InvalidCredential, "the status list is not issued by the issuer of VC")
+	}
+
+	list, err := VerifyStatusListCredential(listVc, status.StatusPurpose, now, src)
+	if err != nil {
+		return err
+	}
+
+	set, err := list.Get(index)
+	if err != nil {
+		return WrapError(ErrCode_InvalidCredential, err)
+	}
+
+	if set {
+		return NewError(ErrCode_Revoked, "the VC is revoked in the status list")
+	}
+
+	return nil
+}
+
+// VerifyStatusListCredential 验证状态列表凭证的类型、有效期和签发者签名，返回其中的状态列表
+// @params listVc 状态列表凭证
+// @params purpose 状态列表的用途，为空时不检查
+// @params now 当前时间的Unix时间戳，秒
+// @params src 验证需要的数据
+func VerifyStatusListCredential(listVc *VerifiableCredential, purpose string, now int64,
+	src VerifySource) (*StatusList, error) {
+	err := listVc.VerifyType()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := listVc.GetStatusList(purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	err = listVc.VerifyDate(now)
+	if err != nil {
+		return nil, WrapError(ErrCode_InvalidCredential,
+			fmt.Errorf("the status list credential is invalid, err: [%w]", err))
+	}
+
+	pkPem, err := getProofPkPem(listVc.Issuer, "issuer", listVc.Proof, src)
+	if err != nil {
+		return nil, err
+	}
+
+	err = listVc.VerifySignature(pkPem)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// VerifyHolderBinding 验证VP中的VC都属于VP持有者
+func (vp *VerifiablePresentation) VerifyHolderBinding() error {
+	for _, v := range vp.VerifiableCredential {
+		subId, err := v.GetCredentialSubjectID()
+		if err != nil {
+			return WrapError(ErrCode_InvalidCredential, err)
+		}
+
+		if vp.Holder != subId {
+			return NewError(ErrCode_InvalidCredential, "the holder is different from the VC's subject ID")
+		}
+	}
+
+	return nil
+}
+
+// check 将检查结果写入报告，err没有错误码时不写入并返回err，用于区分检查未通过和数据获取失败
+func check(report *VerifyReport, name string, err error) error {
+	if err != nil && CodeOf(err) == ErrCode_Unknown {
+		return err
+	}
+
+	report.Check(name, err)
+	return nil
+}
+
+// verifyVcTemplate 检查VC模板存在且凭证主体符合模板
+func verifyVcTemplate(vc *VerifiableCredential, src VerifySource) error {
+	vcTemplateBytes, err := src.GetVcTemplate(vc.Template.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(vcTemplateBytes) == 0 {
+		return NewError(ErrCode_VcTemplateNotFound, "VC template was not found")
+	}
+
+	return vc.VerifyTemplate(vcTemplateBytes)
+}
+
+// verifyVcStatus 检查VC没有被吊销或暂停，已吊销时不再检查暂停状态
+func verifyVcStatus(vc *VerifiableCredential, now int64, src VerifySource) error {
+	revocation, err := src.GetVcRevocation(vc.Issuer, vc.Id)
+	if err != nil {
+		return err
+	}
+
+	if revocation != nil {
+		return NewError(ErrCode_Revoked, "the VC is revoked")
+	}
+
+	if vc.CredentialStatus != nil {
+		err = VerifyCredentialStatus(vc, now, src)
+		if err != nil {
+			return err
+		}
+	}
+
+	suspension, err := src.GetVcSuspension(vc.Issuer, vc.Id)
+	if err != nil {
+		return err
+	}
+
+	if suspension != nil {
+		return NewError(ErrCode_Suspended, "the VC is suspended, reason: [%s]", suspension.Reason)
+	}
+
+	return nil
+}
+
+// verifyNotInBlackList 检查DID不在黑名单中
+func verifyNotInBlackList(did, msg string, src VerifySource) error {
+	black, err := src.IsInBlackList(did)
+	if err != nil {
+		return err
+	}
+
+	if black {
+		return NewError(ErrCode_InBlackList, msg)
+	}
+
+	return nil
+}
+
+// getProofPkPem 获取证明的验证方法对应的公钥，证明必须属于did
+// @params did 签发者或持有者的DID
+// @params role 签发者或持有者，用于错误消息
+// @params proof 证明
+// @params src 验证需要的数据
+func getProofPkPem(did, role string, proof *Proof, src VerifySource) ([]byte, error) {
+	if proof == nil {
+		return nil, NewError(ErrCode_InvalidCredential, "the proof is missing")
+	}
+
+	didDoc, err := src.GetDidDocument(did)
+	if err != nil {
+		return nil, fmt.Errorf("get did document of the %s failed, err: [%w]", role, err)
+	}
+
+	if len(didDoc) == 0 {
+		return nil, NewError(ErrCode_DidNotFound, "the did's doc of the %s not found on chain, did: [%s]", role, did)
+	}
+
+	doc, err := NewDIDDocument(string(didDoc))
+	if err != nil {
+		return nil, NewError(ErrCode_InvalidCredential, "get did document of the %s failed, err: [%s]",
+			role, err.Error())
+	}
+
+	// 判断证明是不是属于签发者或持有者
+	i := strings.Index(proof.VerificationMethod, "#")
+	if i < 0 || proof.VerificationMethod[:i] != did {
+		return nil, NewError(ErrCode_InvalidCredential, "the proof that does not belong to the %s", role)
+	}
+
+	pkPem, err := doc.GetPkPemByVerificationMethodId(proof.VerificationMethod)
+	if err != nil {
+		return nil, NewError(ErrCode_InvalidCredential, "get pk from did doc failed, err: [%s]", err.Error())
+	}
+
+	return []byte(pkPem), nil
+}
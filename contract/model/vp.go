@@ -9,7 +9,6 @@ package model
 
 import (
 	"encoding/json"
-	"errors"
 	"time"
 
 	"github.com/buger/jsonparser"
@@ -39,20 +38,9 @@ func NewVerifiablePresentation(vpJson string) (*VerifiablePresentation, error) {
 }
 
 func (vp *VerifiablePresentation) Verify(pkPem []byte) (bool, error) {
-	// Check if the VC type is correct
-	if len(vp.Type) == 0 {
-		return false, errors.New("invalid VP type")
-	} else {
-		var isVpType bool
-		for _, v := range vp.Type {
-			if v == "VerifiablePresentation" {
-				isVpType = true
-			}
-		}
-
-		if !isVpType {
-			return false, errors.New("invalid VP type")
-		}
+	err := vp.VerifyType()
+	if err != nil {
+		return false, err
 	}
 
 	if len(vp.ExpirationDate) != 0 {
@@ -62,25 +50,118 @@ func (vp *VerifiablePresentation) Verify(pkPem []byte) (bool, error) {
 			return false, err
 		}
 
-		expirationDate, err := time.Parse(time.RFC3339, vp.ExpirationDate)
+		err = vp.VerifyDate(myTime)
 		if err != nil {
 			return false, err
 		}
+	}
 
-		if myTime > expirationDate.Unix() {
-			return false, errors.New("the verifiable presentation has expired")
+	// 验签
+	return vp.verifySignature(pkPem)
+}
+
+// VerifyType 验证VP的类型包含VerifiablePresentation
+func (vp *VerifiablePresentation) VerifyType() error {
+	for _, v := range vp.Type {
+		if v == "VerifiablePresentation" {
+			return nil
 		}
+	}
 
+	return NewError(ErrCode_InvalidCredential, "invalid VP type")
+}
+
+// VerifyDate 验证VP的过期时间，没有过期时间的VP不会过期
+// @params now 当前时间的Unix时间戳，秒
+func (vp *VerifiablePresentation) VerifyDate(now int64) error {
+	if len(vp.ExpirationDate) == 0 {
+		return nil
 	}
 
-	// 删除proof字段
-	withoutProof := jsonparser.Delete(vp.rawData, "proof")
-	//去掉空格换行等
-	withoutProof, err := CompactJson(withoutProof)
+	expirationDate, err := time.Parse(time.RFC3339, vp.ExpirationDate)
+	if err != nil {
+		return NewError(ErrCode_InvalidCredential, "invalid expiration date: [%s]", err.Error())
+	}
+
+	if now > expirationDate.Unix() {
+		return NewError(ErrCode_Expired, "the verifiable presentation has expired")
+	}
+
+	return nil
+}
+
+// VerifySignature 验证VP的签名
+// @params pkPem 持有者公钥的PEM编码
+func (vp *VerifiablePresentation) VerifySignature(pkPem []byte) error {
+	ok, err := vp.verifySignature(pkPem)
+	if err != nil {
+		return WrapError(ErrCode_InvalidCredential, err)
+	}
+
+	if !ok {
+		return NewError(ErrCode_InvalidCredential, "the signature of VP is invalid")
+	}
+
+	return nil
+}
+
+func (vp *VerifiablePresentation) verifySignature(pkPem []byte) (bool, error) {
+	if vp.Proof == nil {
+		return false, NewError(ErrCode_InvalidCredential, "the proof of VP is missing")
+	}
+
+	msg, err := PresentationSigningMessage(vp.rawData, vp.Proof.Challenge, vp.Proof.Domain)
 	if err != nil {
 		return false, err
 	}
 
-	// 验签
-	return vp.Proof.Verify(withoutProof, pkPem)
+	return vp.Proof.Verify(msg, pkPem)
+}
+
+// VerifyChallenge 验证VP的证明绑定了验证者提供的挑战和域，挑战和域包含在签名原文中，由VerifySignature保证未被篡改
+// @params challenge 验证者提供的挑战，为空时不检查
+// @params domain 验证者的域，为空时不检查
+func (vp *VerifiablePresentation) VerifyChallenge(challenge, domain string) error {
+	if vp.Proof == nil {
+		return NewError(ErrCode_InvalidCredential, "the proof of VP is missing")
+	}
+
+	if len(challenge) != 0 && vp.Proof.Challenge != challenge {
+		return NewError(ErrCode_InvalidCredential, "the challenge of VP is mismatched, challenge: [%s]",
+			vp.Proof.Challenge)
+	}
+
+	if len(domain) != 0 && vp.Proof.Domain != domain {
+		return NewError(ErrCode_InvalidCredential, "the domain of VP is mismatched, domain: [%s]", vp.Proof.Domain)
+	}
+
+	return nil
+}
+
+// PresentationSigningMessage 生成VP的签名原文，即删除proof字段并去掉空格换行的VP
+// 证明包含挑战或域时，原文保留只有challenge和domain的proof字段，使它们也被签名
+// @params vpJson VP的json
+// @params challenge 验证者提供的挑战
+// @params domain 验证者的域
+func PresentationSigningMessage(vpJson []byte, challenge, domain string) ([]byte, error) {
+	// 删除proof字段，jsonparser会修改传入的切片，先复制
+	msg := jsonparser.Delete(append([]byte(nil), vpJson...), "proof")
+
+	if len(challenge) != 0 || len(domain) != 0 {
+		options, err := json.Marshal(&struct {
+			Challenge string `json:"challenge,omitempty"`
+			Domain    string `json:"domain,omitempty"`
+		}{challenge, domain})
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err = jsonparser.Set(msg, options, "proof")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//去掉空格换行等
+	return CompactJson(msg)
 }
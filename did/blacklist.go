@@ -8,23 +8,46 @@ SPDX-License-Identifier: Apache-2.0
 package did
 
 import (
+	"context"
 	"did-sdk/invoke"
 	"encoding/json"
 	"strconv"
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 )
 
 // AddDidBlackListToChain
 // @params dids: did列表
 // @params client: 长安链客户端
-func AddDidBlackListToChain(dids []string, client *cmsdk.ChainClient) error {
+func AddDidBlackListToChain(dids []string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return AddDidBlackListToChainCtx(context.Background(), dids, client)
+}
+
+// AddDidBlackListToChainCtx 同AddDidBlackListToChain，可以通过ctx设置超时时间或取消调用
+func AddDidBlackListToChainCtx(ctx context.Context, dids []string, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
+	return AddDidBlackListWithReasonToChainCtx(ctx, dids, model.BlackListReasonUnspecified, "", 0, client)
+}
+
+// AddDidBlackListWithReasonToChain 在链上添加DID黑名单，并记录原因和过期时间
+// @params dids: did列表
+// @params reasonCode: 原因编码，参见model.BlackListReason*
+// @params reason: 原因描述
+// @params expireTime: 过期时间（Unix秒），0表示永久有效
+// @params client: 长安链客户端
+func AddDidBlackListWithReasonToChain(dids []string, reasonCode int, reason string, expireTime int64,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return AddDidBlackListWithReasonToChainCtx(context.Background(), dids, reasonCode, reason, expireTime, client)
+}
+
+// AddDidBlackListWithReasonToChainCtx 同AddDidBlackListWithReasonToChain，可以通过ctx设置超时时间或取消调用
+func AddDidBlackListWithReasonToChainCtx(ctx context.Context, dids []string, reasonCode int, reason string,
+	expireTime int64, client invoke.ChainClient) (*invoke.TxReceipt, error) {
 
 	didsBytes, err := json.Marshal(dids)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	params := make([]*common.KeyValuePair, 0)
@@ -34,12 +57,32 @@ func AddDidBlackListToChain(dids []string, client *cmsdk.ChainClient) error {
 		Value: []byte(didsBytes),
 	})
 
-	_, err = invoke.InvokeContract(invoke.DIDContractName, model.Method_AddBlackList, params, client)
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ReasonCode,
+		Value: []byte(strconv.Itoa(reasonCode)),
+	})
+
+	if len(reason) != 0 {
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_Reason,
+			Value: []byte(reason),
+		})
+	}
+
+	if expireTime != 0 {
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_ExpireTime,
+			Value: []byte(strconv.FormatInt(expireTime, 10)),
+		})
+	}
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_AddBlackList,
+		params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // GetDidBlackListFromChain
@@ -48,7 +91,13 @@ func AddDidBlackListToChain(dids []string, client *cmsdk.ChainClient) error {
 // @params count：要获取的数量，0表示获取所有
 // @params client：长安链客户端
 func GetDidBlackListFromChain(didSearch string, start int, count int,
-	client *cmsdk.ChainClient) ([]string, error) {
+	client invoke.ChainClient) ([]*model.BlackListRecord, error) {
+	return GetDidBlackListFromChainCtx(context.Background(), didSearch, start, count, client)
+}
+
+// GetDidBlackListFromChainCtx 同GetDidBlackListFromChain，可以通过ctx设置超时时间或取消调用
+func GetDidBlackListFromChainCtx(ctx context.Context, didSearch string, start int, count int,
+	client invoke.ChainClient) ([]*model.BlackListRecord, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -66,12 +115,12 @@ func GetDidBlackListFromChain(didSearch string, start int, count int,
 		Value: []byte(strconv.Itoa(count)),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetBlackList, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetBlackList, params, client)
 	if err != nil {
 		return nil, err
 	}
 
-	list := make([]string, 0)
+	list := make([]*model.BlackListRecord, 0)
 
 	err = json.Unmarshal(resp, &list)
 	if err != nil {
@@ -84,10 +133,16 @@ func GetDidBlackListFromChain(didSearch string, start int, count int,
 // DeleteDidBlackListFromChain
 // @params dids: did列表
 // @params client: 长安链客户端
-func DeleteDidBlackListFromChain(dids []string, client *cmsdk.ChainClient) error {
+func DeleteDidBlackListFromChain(dids []string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return DeleteDidBlackListFromChainCtx(context.Background(), dids, client)
+}
+
+// DeleteDidBlackListFromChainCtx 同DeleteDidBlackListFromChain，可以通过ctx设置超时时间或取消调用
+func DeleteDidBlackListFromChainCtx(ctx context.Context, dids []string, client invoke.ChainClient) (*invoke.TxReceipt,
+	error) {
 	didsBytes, err := json.Marshal(dids)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	params := make([]*common.KeyValuePair, 0)
@@ -97,10 +152,11 @@ func DeleteDidBlackListFromChain(dids []string, client *cmsdk.ChainClient) error
 		Value: []byte(didsBytes),
 	})
 
-	_, err = invoke.InvokeContract(invoke.DIDContractName, model.Method_DeleteBlackList, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_DeleteBlackList, params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
@@ -11,6 +11,7 @@ import (
 	"did-sdk/testdata"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"github.com/test-go/testify/require"
@@ -26,7 +27,7 @@ func TestAddDidBlackListToChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -34,7 +35,7 @@ func TestAddDidBlackListToChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = AddDidBlackListToChain([]string{document.Id}, c)
+	_, err = AddDidBlackListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	ok, err := IsValidDidOnChain(document.Id, c)
@@ -52,7 +53,7 @@ func TestGetDidBlackListFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -60,7 +61,7 @@ func TestGetDidBlackListFromChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = AddDidBlackListToChain([]string{document.Id}, c)
+	_, err = AddDidBlackListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	list, err := GetDidBlackListFromChain(document.Id, 0, 0, c)
@@ -68,7 +69,7 @@ func TestGetDidBlackListFromChain(t *testing.T) {
 
 	var isInBlacklist bool
 	for _, v := range list {
-		if v == document.Id {
+		if v.Did == document.Id {
 			isInBlacklist = true
 		}
 	}
@@ -76,6 +77,45 @@ func TestGetDidBlackListFromChain(t *testing.T) {
 	require.Equal(t, true, isInBlacklist)
 }
 
+func TestAddDidBlackListWithReasonToChain(t *testing.T) {
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	keyInfo, err := key.GenerateKey("EC_Secp256k1")
+	require.Nil(t, err)
+
+	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
+	require.Nil(t, err)
+
+	_, err = AddDidDocToChain(string(doc), c)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+
+	json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+
+	expireTime := time.Now().Add(time.Hour).Unix()
+
+	_, err = AddDidBlackListWithReasonToChain([]string{document.Id}, model.BlackListReasonFraud,
+		"fraudulent activity", expireTime, c)
+	require.Nil(t, err)
+
+	list, err := GetDidBlackListFromChain(document.Id, 0, 0, c)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(list))
+
+	require.Equal(t, document.Id, list[0].Did)
+	require.Equal(t, model.BlackListReasonFraud, list[0].ReasonCode)
+	require.Equal(t, "fraudulent activity", list[0].Reason)
+	require.Equal(t, expireTime, list[0].ExpireTime)
+	require.NotEqual(t, "", list[0].Operator)
+
+	ok, err := IsValidDidOnChain(document.Id, c)
+	require.NotNil(t, err)
+	require.Equal(t, false, ok)
+}
+
 func TestDeleteDidBlackListFromChain(t *testing.T) {
 	c, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
 	require.Nil(t, err)
@@ -86,7 +126,7 @@ func TestDeleteDidBlackListFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -94,14 +134,14 @@ func TestDeleteDidBlackListFromChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = AddDidBlackListToChain([]string{document.Id}, c)
+	_, err = AddDidBlackListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	ok, err := IsValidDidOnChain(document.Id, c)
 	require.NotNil(t, err)
 	require.Equal(t, false, ok)
 
-	err = DeleteDidBlackListFromChain([]string{document.Id}, c)
+	_, err = DeleteDidBlackListFromChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	ok, err = IsValidDidOnChain(document.Id, c)
@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package did
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rsa"
@@ -24,7 +25,6 @@ import (
 	"time"
 
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
 	"github.com/mr-tron/base58"
 	"github.com/tjfoc/gmsm/sm2"
@@ -45,9 +45,14 @@ const (
 // GetDidMethodFromChain query contract from chain
 // @params client the chainmaker sdk client
 // @return string the did method
-func GetDidMethodFromChain(client *cmsdk.ChainClient) (string, error) {
+func GetDidMethodFromChain(client invoke.ChainClient) (string, error) {
+	return GetDidMethodFromChainCtx(context.Background(), client)
+}
+
+// GetDidMethodFromChainCtx 同GetDidMethodFromChain，可以通过ctx设置超时时间或取消调用
+func GetDidMethodFromChainCtx(ctx context.Context, client invoke.ChainClient) (string, error) {
 
-	result, err := invoke.QueryContract(invoke.DIDContractName, model.Method_DidMethod, nil, client)
+	result, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_DidMethod, nil, client)
 	if err != nil {
 		return "", err
 	}
@@ -59,9 +64,14 @@ func GetDidMethodFromChain(client *cmsdk.ChainClient) (string, error) {
 // @params pkPem: PK PEM
 // @params client: ChainMaker SDK
 // @return string: the did string
-func GenerateDidByPK(pkPem []byte, client *cmsdk.ChainClient) (string, error) {
+func GenerateDidByPK(pkPem []byte, client invoke.ChainClient) (string, error) {
+	return GenerateDidByPKCtx(context.Background(), pkPem, client)
+}
+
+// GenerateDidByPKCtx 同GenerateDidByPK，可以通过ctx设置超时时间或取消调用
+func GenerateDidByPKCtx(ctx context.Context, pkPem []byte, client invoke.ChainClient) (string, error) {
 	// 从链上获取DID方法名
-	didMethod, err := GetDidMethodFromChain(client)
+	didMethod, err := GetDidMethodFromChainCtx(ctx, client)
 	if err != nil {
 		return "", err
 	}
@@ -78,7 +88,13 @@ func GenerateDidByPK(pkPem []byte, client *cmsdk.ChainClient) (string, error) {
 // @params keyInfo：密钥信息
 // @params client：长安链客户端
 // @params controller：父控制器，可变参数
-func GenerateDidDoc(keyInfo []*key.KeyInfo, client *cmsdk.ChainClient, controller ...string) ([]byte, error) {
+func GenerateDidDoc(keyInfo []*key.KeyInfo, client invoke.ChainClient, controller ...string) ([]byte, error) {
+	return GenerateDidDocCtx(context.Background(), keyInfo, client, controller...)
+}
+
+// GenerateDidDocCtx 同GenerateDidDoc，可以通过ctx设置超时时间或取消调用
+func GenerateDidDocCtx(ctx context.Context, keyInfo []*key.KeyInfo, client invoke.ChainClient,
+	controller ...string) ([]byte, error) {
 
 	// 密钥最少一把
 	if len(keyInfo) == 0 {
@@ -86,7 +102,7 @@ func GenerateDidDoc(keyInfo []*key.KeyInfo, client *cmsdk.ChainClient, controlle
 	}
 
 	// 通过公钥生成DID字符串
-	did, err := GenerateDidByPK(keyInfo[0].PkPEM, client)
+	did, err := GenerateDidByPKCtx(ctx, keyInfo[0].PkPEM, client)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +198,12 @@ func GenerateDidDoc(keyInfo []*key.KeyInfo, client *cmsdk.ChainClient, controlle
 // AddDidDocToChain store the DID document on the blockchain
 // @params doc：DID文档
 // @params client：长安链客户端
-func AddDidDocToChain(doc string, client *cmsdk.ChainClient) error {
+func AddDidDocToChain(doc string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return AddDidDocToChainCtx(context.Background(), doc, client)
+}
+
+// AddDidDocToChainCtx 同AddDidDocToChain，可以通过ctx设置超时时间或取消调用
+func AddDidDocToChainCtx(ctx context.Context, doc string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -190,18 +211,24 @@ func AddDidDocToChain(doc string, client *cmsdk.ChainClient) error {
 		Value: []byte(doc),
 	})
 
-	_, err := invoke.InvokeContract(invoke.DIDContractName, model.Method_AddDidDocument, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_AddDidDocument,
+		params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // IsValidDidOnChain 判断DID在链上是否有效（格式、是否在黑名单）
 // @params did：DID
 // @params client：长安链客户端
-func IsValidDidOnChain(did string, client *cmsdk.ChainClient) (bool, error) {
+func IsValidDidOnChain(did string, client invoke.ChainClient) (bool, error) {
+	return IsValidDidOnChainCtx(context.Background(), did, client)
+}
+
+// IsValidDidOnChainCtx 同IsValidDidOnChain，可以通过ctx设置超时时间或取消调用
+func IsValidDidOnChainCtx(ctx context.Context, did string, client invoke.ChainClient) (bool, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -209,7 +236,7 @@ func IsValidDidOnChain(did string, client *cmsdk.ChainClient) (bool, error) {
 		Value: []byte(did),
 	})
 
-	result, err := invoke.QueryContract(invoke.DIDContractName, model.Method_IsValidDid, params, client)
+	result, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_IsValidDid, params, client)
 	if err != nil {
 		return false, err
 	}
@@ -224,7 +251,12 @@ func IsValidDidOnChain(did string, client *cmsdk.ChainClient) (bool, error) {
 // GetDidDocFromChain 通过DID在链上获取DID文档
 // @params did：DID
 // @params client：长安链客户端
-func GetDidDocFromChain(did string, client *cmsdk.ChainClient) ([]byte, error) {
+func GetDidDocFromChain(did string, client invoke.ChainClient) ([]byte, error) {
+	return GetDidDocFromChainCtx(context.Background(), did, client)
+}
+
+// GetDidDocFromChainCtx 同GetDidDocFromChain，可以通过ctx设置超时时间或取消调用
+func GetDidDocFromChainCtx(ctx context.Context, did string, client invoke.ChainClient) ([]byte, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -232,7 +264,8 @@ func GetDidDocFromChain(did string, client *cmsdk.ChainClient) ([]byte, error) {
 		Value: []byte(did),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetDidDocument, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetDidDocument,
+		params, client)
 	if err != nil {
 		return nil, err
 	}
@@ -243,7 +276,12 @@ func GetDidDocFromChain(did string, client *cmsdk.ChainClient) ([]byte, error) {
 // GetDidByPkFromChain 通过PK获取DID
 // @params pkPem：公钥的PEM编码
 // @params client：长安链客户端
-func GetDidByPkFromChain(pkPem string, client *cmsdk.ChainClient) (string, error) {
+func GetDidByPkFromChain(pkPem string, client invoke.ChainClient) (string, error) {
+	return GetDidByPkFromChainCtx(context.Background(), pkPem, client)
+}
+
+// GetDidByPkFromChainCtx 同GetDidByPkFromChain，可以通过ctx设置超时时间或取消调用
+func GetDidByPkFromChainCtx(ctx context.Context, pkPem string, client invoke.ChainClient) (string, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -251,7 +289,8 @@ func GetDidByPkFromChain(pkPem string, client *cmsdk.ChainClient) (string, error
 		Value: []byte(pkPem),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetDidByPubKey, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetDidByPubKey,
+		params, client)
 	if err != nil {
 		return "", err
 	}
@@ -262,7 +301,12 @@ func GetDidByPkFromChain(pkPem string, client *cmsdk.ChainClient) (string, error
 // GetDidByAddressFromChain 通过Address获取DID
 // @params address：公钥的PEM编码
 // @params client：长安链客户端
-func GetDidByAddressFromChain(address string, client *cmsdk.ChainClient) (string, error) {
+func GetDidByAddressFromChain(address string, client invoke.ChainClient) (string, error) {
+	return GetDidByAddressFromChainCtx(context.Background(), address, client)
+}
+
+// GetDidByAddressFromChainCtx 同GetDidByAddressFromChain，可以通过ctx设置超时时间或取消调用
+func GetDidByAddressFromChainCtx(ctx context.Context, address string, client invoke.ChainClient) (string, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -270,7 +314,8 @@ func GetDidByAddressFromChain(address string, client *cmsdk.ChainClient) (string
 		Value: []byte(address),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetDidByAddress, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetDidByAddress,
+		params, client)
 	if err != nil {
 		return "", err
 	}
@@ -281,7 +326,12 @@ func GetDidByAddressFromChain(address string, client *cmsdk.ChainClient) (string
 // UpdateDidDocToChain 在链上更新DID文档
 // @params doc：DID文档
 // @params client：长安链客户端
-func UpdateDidDocToChain(doc string, client *cmsdk.ChainClient) error {
+func UpdateDidDocToChain(doc string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return UpdateDidDocToChainCtx(context.Background(), doc, client)
+}
+
+// UpdateDidDocToChainCtx 同UpdateDidDocToChain，可以通过ctx设置超时时间或取消调用
+func UpdateDidDocToChainCtx(ctx context.Context, doc string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -289,12 +339,13 @@ func UpdateDidDocToChain(doc string, client *cmsdk.ChainClient) error {
 		Value: []byte(doc),
 	})
 
-	_, err := invoke.InvokeContract(invoke.DIDContractName, model.Method_UpdateDidDocument, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_UpdateDidDocument, params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // UpdateDidDoc 更新DID文档（本地生成）
@@ -55,7 +55,7 @@ func TestAddDidDocToChain(t *testing.T) {
 
 	fmt.Println(string(doc))
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 }
 
@@ -84,7 +84,7 @@ func TestGetDidDocFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -108,7 +108,7 @@ func TestGetDidByAddressFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -132,7 +132,7 @@ func TestGetDidByPkFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -165,7 +165,7 @@ func TestUpdateDidDocToChain(t *testing.T) {
 	c2doc, err := GenerateDidDoc([]*key.KeyInfo{c2KeyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(c2doc), c)
+	_, err = AddDidDocToChain(string(c2doc), c)
 	require.Nil(t, err)
 
 	var c2Doc model.DidDocument
@@ -182,7 +182,7 @@ func TestUpdateDidDocToChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c, c2Did)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var oldDoc model.DidDocument
@@ -197,7 +197,7 @@ func TestUpdateDidDocToChain(t *testing.T) {
 	require.Nil(t, err)
 
 	// 使用c2更新，测试权限逻辑
-	err = UpdateDidDocToChain(string(newDoc), c2)
+	_, err = UpdateDidDocToChain(string(newDoc), c2)
 	require.Nil(t, err)
 
 	getDoc, err := GetDidDocFromChain(oldDoc.Id, c)
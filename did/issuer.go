@@ -8,23 +8,52 @@ SPDX-License-Identifier: Apache-2.0
 package did
 
 import (
+	"context"
 	"did-sdk/invoke"
 	"encoding/json"
 	"strconv"
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 )
 
 // AddTrustIssuerListToChain 在链上添加信任颁发者
 // @params dids：权威颁发者DID列表
 // @params client: 长安链客户端
-func AddTrustIssuerListToChain(dids []string, client *cmsdk.ChainClient) error {
+// @params templateIds：允许签发的VC模板ID列表，可变参数，不填表示可以签发所有模板
+func AddTrustIssuerListToChain(dids []string, client invoke.ChainClient, templateIds ...string) (*invoke.TxReceipt,
+	error) {
+	return AddTrustIssuerListToChainCtx(context.Background(), dids, client, templateIds...)
+}
+
+// AddTrustIssuerListToChainCtx 同AddTrustIssuerListToChain，可以通过ctx设置超时时间或取消调用
+func AddTrustIssuerListToChainCtx(ctx context.Context, dids []string, client invoke.ChainClient,
+	templateIds ...string) (*invoke.TxReceipt, error) {
+	return addTrustIssuerListToChain(ctx, dids, false, 0, client, templateIds...)
+}
+
+// AddDelegableTrustIssuerListToChain 在链上添加具有认证权限的信任颁发者，可以认证下级签发者
+// @params dids：权威颁发者DID列表
+// @params maxDepth：可以向下认证的最大层数，0表示不限制
+// @params client: 长安链客户端
+// @params templateIds：允许签发的VC模板ID列表，可变参数，不填表示可以签发所有模板
+func AddDelegableTrustIssuerListToChain(dids []string, maxDepth int, client invoke.ChainClient,
+	templateIds ...string) (*invoke.TxReceipt, error) {
+	return AddDelegableTrustIssuerListToChainCtx(context.Background(), dids, maxDepth, client, templateIds...)
+}
+
+// AddDelegableTrustIssuerListToChainCtx 同AddDelegableTrustIssuerListToChain，可以通过ctx设置超时时间或取消调用
+func AddDelegableTrustIssuerListToChainCtx(ctx context.Context, dids []string, maxDepth int, client invoke.ChainClient,
+	templateIds ...string) (*invoke.TxReceipt, error) {
+	return addTrustIssuerListToChain(ctx, dids, true, maxDepth, client, templateIds...)
+}
+
+func addTrustIssuerListToChain(ctx context.Context, dids []string, delegable bool, maxDepth int,
+	client invoke.ChainClient, templateIds ...string) (*invoke.TxReceipt, error) {
 
 	didsBytes, err := json.Marshal(dids)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	params := make([]*common.KeyValuePair, 0)
@@ -34,12 +63,38 @@ func AddTrustIssuerListToChain(dids []string, client *cmsdk.ChainClient) error {
 		Value: []byte(didsBytes),
 	})
 
-	_, err = invoke.InvokeContract(invoke.DIDContractName, model.Method_AddTrustIssuer, params, client)
+	if len(templateIds) != 0 {
+		var templateIdsBytes []byte
+		templateIdsBytes, err = json.Marshal(templateIds)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_VcTemplateIdList,
+			Value: templateIdsBytes,
+		})
+	}
+
+	if delegable {
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_Delegable,
+			Value: []byte(strconv.FormatBool(delegable)),
+		})
+
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_MaxDepth,
+			Value: []byte(strconv.Itoa(maxDepth)),
+		})
+	}
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_AddTrustIssuer,
+		params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // GetTrustIssuerListFromChain 从链上获取权威签发者列表
@@ -48,7 +103,13 @@ func AddTrustIssuerListToChain(dids []string, client *cmsdk.ChainClient) error {
 // @params count：要获取的数量，0表示获取所有
 // @params client：长安链客户端
 func GetTrustIssuerListFromChain(didSearch string, start int, count int,
-	client *cmsdk.ChainClient) ([]string, error) {
+	client invoke.ChainClient) ([]string, error) {
+	return GetTrustIssuerListFromChainCtx(context.Background(), didSearch, start, count, client)
+}
+
+// GetTrustIssuerListFromChainCtx 同GetTrustIssuerListFromChain，可以通过ctx设置超时时间或取消调用
+func GetTrustIssuerListFromChainCtx(ctx context.Context, didSearch string, start int, count int,
+	client invoke.ChainClient) ([]string, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -66,7 +127,8 @@ func GetTrustIssuerListFromChain(didSearch string, start int, count int,
 		Value: []byte(strconv.Itoa(count)),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetTrustIssuer, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetTrustIssuer,
+		params, client)
 	if err != nil {
 		return nil, err
 	}
@@ -81,13 +143,52 @@ func GetTrustIssuerListFromChain(didSearch string, start int, count int,
 	return list, nil
 }
 
+// GetTrustIssuerFromChain 从链上获取信任签发者记录（包含可签发的模板范围）
+// @params did：签发者DID
+// @params client：长安链客户端
+func GetTrustIssuerFromChain(did string, client invoke.ChainClient) (*model.TrustIssuer, error) {
+	return GetTrustIssuerFromChainCtx(context.Background(), did, client)
+}
+
+// GetTrustIssuerFromChainCtx 同GetTrustIssuerFromChain，可以通过ctx设置超时时间或取消调用
+func GetTrustIssuerFromChainCtx(ctx context.Context, did string,
+	client invoke.ChainClient) (*model.TrustIssuer, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Did,
+		Value: []byte(did),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetTrustIssuerInfo,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var issuer model.TrustIssuer
+
+	err = json.Unmarshal(resp, &issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuer, nil
+}
+
 // DeleteTrustIssuerListFromChain
 // @params dids: 要删除的did列表
 // @params client: 长安链客户端
-func DeleteTrustIssuerListFromChain(dids []string, client *cmsdk.ChainClient) error {
+func DeleteTrustIssuerListFromChain(dids []string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return DeleteTrustIssuerListFromChainCtx(context.Background(), dids, client)
+}
+
+// DeleteTrustIssuerListFromChainCtx 同DeleteTrustIssuerListFromChain，可以通过ctx设置超时时间或取消调用
+func DeleteTrustIssuerListFromChainCtx(ctx context.Context, dids []string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
 	didsBytes, err := json.Marshal(dids)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	params := make([]*common.KeyValuePair, 0)
@@ -97,10 +198,123 @@ func DeleteTrustIssuerListFromChain(dids []string, client *cmsdk.ChainClient) er
 		Value: []byte(didsBytes),
 	})
 
-	_, err = invoke.InvokeContract(invoke.DIDContractName, model.Method_DeleteTrustIssuer, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_DeleteTrustIssuer, params, client)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// AccreditIssuerToChain 由具有认证权限的信任签发者（客户端用户对应的DID）在链上认证下级签发者
+// @params did：被认证的签发者DID
+// @params delegable：被认证的签发者是否有权继续认证下级签发者
+// @params maxDepth：被认证的签发者可以向下认证的最大层数，0表示不限制（仍受上级限制）
+// @params client：长安链客户端
+// @params templateIds：允许签发的VC模板ID列表，可变参数，不填表示继承认证者的模板范围
+func AccreditIssuerToChain(did string, delegable bool, maxDepth int, client invoke.ChainClient,
+	templateIds ...string) (*invoke.TxReceipt, error) {
+	return AccreditIssuerToChainCtx(context.Background(), did, delegable, maxDepth, client, templateIds...)
+}
+
+// AccreditIssuerToChainCtx 同AccreditIssuerToChain，可以通过ctx设置超时时间或取消调用
+func AccreditIssuerToChainCtx(ctx context.Context, did string, delegable bool, maxDepth int, client invoke.ChainClient,
+	templateIds ...string) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Did,
+		Value: []byte(did),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Delegable,
+		Value: []byte(strconv.FormatBool(delegable)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_MaxDepth,
+		Value: []byte(strconv.Itoa(maxDepth)),
+	})
+
+	if len(templateIds) != 0 {
+		templateIdsBytes, err := json.Marshal(templateIds)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, &common.KeyValuePair{
+			Key:   model.Params_VcTemplateIdList,
+			Value: templateIdsBytes,
+		})
+	}
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_AccreditIssuer,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// RevokeIssuerAccreditationFromChain 撤销对下级签发者的认证，只有认证者或管理员可以操作
+// @params did：被撤销认证的签发者DID
+// @params client：长安链客户端
+func RevokeIssuerAccreditationFromChain(did string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return RevokeIssuerAccreditationFromChainCtx(context.Background(), did, client)
+}
+
+// RevokeIssuerAccreditationFromChainCtx 同RevokeIssuerAccreditationFromChain，可以通过ctx设置超时时间或取消调用
+func RevokeIssuerAccreditationFromChainCtx(ctx context.Context, did string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Did,
+		Value: []byte(did),
+	})
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client),
+		model.Method_RevokeAccreditation, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetIssuerAccreditationChainFromChain 从链上获取签发者的认证链
+// 返回的列表从签发者本身开始，依次为上级签发者，最后一个为管理员添加的信任签发者
+// @params did：签发者DID
+// @params client：长安链客户端
+func GetIssuerAccreditationChainFromChain(did string, client invoke.ChainClient) ([]*model.TrustIssuer, error) {
+	return GetIssuerAccreditationChainFromChainCtx(context.Background(), did, client)
+}
+
+// GetIssuerAccreditationChainFromChainCtx 同GetIssuerAccreditationChainFromChain，可以通过ctx设置超时时间或取消调用
+func GetIssuerAccreditationChainFromChainCtx(ctx context.Context, did string,
+	client invoke.ChainClient) ([]*model.TrustIssuer, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Did,
+		Value: []byte(did),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetAccreditationChain,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := make([]*model.TrustIssuer, 0)
+
+	err = json.Unmarshal(resp, &chain)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return chain, nil
 }
@@ -27,7 +27,7 @@ func TestAddTrustIssuerListToChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -35,7 +35,7 @@ func TestAddTrustIssuerListToChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = AddTrustIssuerListToChain([]string{document.Id}, c)
+	_, err = AddTrustIssuerListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 }
 
@@ -49,7 +49,7 @@ func TestGetTrustIssuerListFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -57,7 +57,7 @@ func TestGetTrustIssuerListFromChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = AddTrustIssuerListToChain([]string{document.Id}, c)
+	_, err = AddTrustIssuerListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	list, err := GetTrustIssuerListFromChain(document.Id, 0, 0, c)
@@ -83,7 +83,7 @@ func TestDeleteTrustIssuerListFromChain(t *testing.T) {
 	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = AddDidDocToChain(string(doc), c)
+	_, err = AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -91,7 +91,7 @@ func TestDeleteTrustIssuerListFromChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = AddTrustIssuerListToChain([]string{document.Id}, c)
+	_, err = AddTrustIssuerListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	list, err := GetTrustIssuerListFromChain(document.Id, 0, 0, c)
@@ -106,7 +106,7 @@ func TestDeleteTrustIssuerListFromChain(t *testing.T) {
 
 	require.Equal(t, true, isInIssuerList)
 
-	err = DeleteTrustIssuerListFromChain([]string{document.Id}, c)
+	_, err = DeleteTrustIssuerListFromChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	list2, err := GetTrustIssuerListFromChain(document.Id, 0, 0, c)
@@ -121,3 +121,70 @@ func TestDeleteTrustIssuerListFromChain(t *testing.T) {
 
 	require.Equal(t, false, isInIssuerList2)
 }
+
+func TestAccreditIssuerToChain(t *testing.T) {
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	// c2对应的DID作为具有认证权限的根签发者
+	c2, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
+	require.Nil(t, err)
+	c2pk, err := c2.GetPublicKey().String()
+	require.Nil(t, err)
+	c2sk, err := c2.GetPrivateKey().String()
+	require.Nil(t, err)
+
+	c2KeyInfo := &key.KeyInfo{
+		PkPEM: []byte(c2pk),
+		SkPEM: []byte(c2sk),
+	}
+
+	c2doc, err := GenerateDidDoc([]*key.KeyInfo{c2KeyInfo}, c)
+	require.Nil(t, err)
+
+	_, err = AddDidDocToChain(string(c2doc), c)
+	require.Nil(t, err)
+
+	var c2Doc model.DidDocument
+
+	json.Unmarshal(c2doc, &c2Doc)
+	require.Nil(t, err)
+
+	_, err = AddDelegableTrustIssuerListToChain([]string{c2Doc.Id}, 1, c)
+	require.Nil(t, err)
+
+	// 下级签发者
+	keyInfo, err := key.GenerateKey("EC_Secp256k1")
+	require.Nil(t, err)
+
+	doc, err := GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
+	require.Nil(t, err)
+
+	_, err = AddDidDocToChain(string(doc), c)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+
+	json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+
+	// 根签发者只能向下认证一层，下级签发者不能再具有认证权限
+	_, err = AccreditIssuerToChain(document.Id, true, 0, c2)
+	require.NotNil(t, err)
+
+	_, err = AccreditIssuerToChain(document.Id, false, 0, c2)
+	require.Nil(t, err)
+
+	chain, err := GetIssuerAccreditationChainFromChain(document.Id, c)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(chain))
+	require.Equal(t, document.Id, chain[0].Did)
+	require.Equal(t, c2Doc.Id, chain[0].Accreditor)
+	require.Equal(t, c2Doc.Id, chain[1].Did)
+
+	_, err = RevokeIssuerAccreditationFromChain(document.Id, c2)
+	require.Nil(t, err)
+
+	_, err = GetIssuerAccreditationChainFromChain(document.Id, c)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,327 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// Event 解码后的DID合约事件
+type Event struct {
+	Topic        string
+	ContractName string
+	BlockHeight  uint64
+	TxId         string
+	// EventIndex 事件在交易中的序号
+	EventIndex uint32
+	// Data 按主题解码的事件内容，如*DidDocumentSet、*VcRevoked，未知主题为事件的原始数据[]string
+	Data interface{}
+}
+
+// DidDocumentSet 添加或更新DID Document事件
+type DidDocumentSet struct {
+	Did      string
+	Document string
+}
+
+// BlackListAdded 添加黑名单事件
+type BlackListAdded struct {
+	Dids []string
+}
+
+// BlackListDeleted 删除黑名单事件
+type BlackListDeleted struct {
+	Dids []string
+}
+
+// TrustIssuerAdded 添加信任签发者事件
+type TrustIssuerAdded struct {
+	Dids []string
+}
+
+// TrustIssuerDeleted 删除信任签发者事件
+type TrustIssuerDeleted struct {
+	Dids []string
+}
+
+// IssuerAccredited 认证下级签发者事件
+type IssuerAccredited struct {
+	Did        string
+	Accreditor string
+	Issuer     *model.TrustIssuer
+}
+
+// AccreditationRevoked 撤销下级签发者认证事件
+type AccreditationRevoked struct {
+	Did        string
+	Accreditor string
+}
+
+// Migrated 数据迁移事件
+type Migrated struct {
+	Status *model.MigrationStatus
+}
+
+// ContractConfigSet 修改合约配置事件
+type ContractConfigSet struct {
+	Config *model.ContractConfig
+}
+
+// AdminSet 设置管理员事件
+type AdminSet struct {
+	Ski string
+	Did string
+}
+
+// AdminDeleted 删除管理员事件
+type AdminDeleted struct {
+	Ski string
+	Did string
+}
+
+// RoleGranted 授予角色事件
+type RoleGranted struct {
+	Role   string
+	Member string
+}
+
+// RoleRevoked 撤销角色事件
+type RoleRevoked struct {
+	Role   string
+	Member string
+}
+
+// ProposalCreated 创建提案事件
+type ProposalCreated struct {
+	Id       string
+	Action   string
+	Proposer string
+}
+
+// ProposalApproved 同意提案事件
+type ProposalApproved struct {
+	Id       string
+	Approver string
+}
+
+// ProposalExecuted 执行提案事件
+type ProposalExecuted struct {
+	Id     string
+	Action string
+}
+
+// ProposalCanceled 取消提案事件
+type ProposalCanceled struct {
+	Id string
+}
+
+// VcRevoked 吊销VC事件，Record为吊销记录，早期版本合约的事件中没有吊销记录，此时为nil
+type VcRevoked struct {
+	VcId   string
+	Record *model.VcRevocation
+}
+
+// VcSuspended 暂停VC事件
+type VcSuspended struct {
+	VcId   string
+	Record *model.VcSuspension
+}
+
+// VcUnsuspended 恢复VC事件，Record为恢复的原因、操作者和时间
+type VcUnsuspended struct {
+	VcId   string
+	Record *model.VcSuspension
+}
+
+// VcTemplateSet 设置VC模板事件
+type VcTemplateSet struct {
+	Id       string
+	Template *model.VcTemplate
+}
+
+// VcIssueLogged 记录VC签发日志事件
+type VcIssueLogged struct {
+	VcId string
+	Log  *model.VcIssueLog
+}
+
+// StatusListSet 发布状态列表凭证事件
+type StatusListSet struct {
+	Id     string
+	Issuer string
+}
+
+// Decode 按主题解码长安链SDK订阅到的合约事件
+// @params info: 合约事件
+func Decode(info *common.ContractEventInfo) (*Event, error) {
+	data, err := decodeData(info.Topic, info.EventData)
+	if err != nil {
+		return nil, fmt.Errorf("decode event failed, topic: [%s], TxId: [%s], err: [%w]", info.Topic, info.TxId, err)
+	}
+
+	return &Event{
+		Topic:        info.Topic,
+		ContractName: info.ContractName,
+		BlockHeight:  info.BlockHeight,
+		TxId:         info.TxId,
+		EventIndex:   info.EventIndex,
+		Data:         data,
+	}, nil
+}
+
+// decodeData 按主题解码事件数据，事件数据的格式与合约中发送事件时保持一致
+func decodeData(topic string, d []string) (interface{}, error) {
+	switch topic {
+	case model.Topic_SetDidDocument:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &DidDocumentSet{Did: d[0], Document: d[1]}, nil
+	case model.Topic_AddBlackList:
+		return &BlackListAdded{Dids: d}, nil
+	case model.Topic_DeleteBlackList:
+		return &BlackListDeleted{Dids: d}, nil
+	case model.Topic_AddTrustIssuer:
+		return &TrustIssuerAdded{Dids: d}, nil
+	case model.Topic_DeleteTrustIssuer:
+		return &TrustIssuerDeleted{Dids: d}, nil
+	case model.Topic_AccreditIssuer:
+		if err := checkLen(d, 3); err != nil {
+			return nil, err
+		}
+		var issuer model.TrustIssuer
+		if err := json.Unmarshal([]byte(d[2]), &issuer); err != nil {
+			return nil, err
+		}
+		return &IssuerAccredited{Did: d[0], Accreditor: d[1], Issuer: &issuer}, nil
+	case model.Topic_RevokeAccreditation:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &AccreditationRevoked{Did: d[0], Accreditor: d[1]}, nil
+	case model.Topic_Migrate:
+		if err := checkLen(d, 1); err != nil {
+			return nil, err
+		}
+		var status model.MigrationStatus
+		if err := json.Unmarshal([]byte(d[0]), &status); err != nil {
+			return nil, err
+		}
+		return &Migrated{Status: &status}, nil
+	case model.Topic_SetContractConfig:
+		if err := checkLen(d, 1); err != nil {
+			return nil, err
+		}
+		var config model.ContractConfig
+		if err := json.Unmarshal([]byte(d[0]), &config); err != nil {
+			return nil, err
+		}
+		return &ContractConfigSet{Config: &config}, nil
+	case model.Topic_SetAdmin:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &AdminSet{Ski: d[0], Did: d[1]}, nil
+	case model.Topic_DeleteAdmin:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &AdminDeleted{Ski: d[0], Did: d[1]}, nil
+	case model.Topic_GrantRole:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &RoleGranted{Role: d[0], Member: d[1]}, nil
+	case model.Topic_RevokeRole:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &RoleRevoked{Role: d[0], Member: d[1]}, nil
+	case model.Topic_CreateProposal:
+		if err := checkLen(d, 3); err != nil {
+			return nil, err
+		}
+		return &ProposalCreated{Id: d[0], Action: d[1], Proposer: d[2]}, nil
+	case model.Topic_ApproveProposal:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &ProposalApproved{Id: d[0], Approver: d[1]}, nil
+	case model.Topic_ExecuteProposal:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &ProposalExecuted{Id: d[0], Action: d[1]}, nil
+	case model.Topic_CancelProposal:
+		if err := checkLen(d, 1); err != nil {
+			return nil, err
+		}
+		return &ProposalCanceled{Id: d[0]}, nil
+	case model.Topic_RevokeVc:
+		if err := checkLen(d, 1); err != nil {
+			return nil, err
+		}
+		if len(d) == 1 {
+			return &VcRevoked{VcId: d[0]}, nil
+		}
+		var record model.VcRevocation
+		if err := json.Unmarshal([]byte(d[1]), &record); err != nil {
+			return nil, err
+		}
+		return &VcRevoked{VcId: d[0], Record: &record}, nil
+	case model.Topic_SuspendVc, model.Topic_UnsuspendVc:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		var record model.VcSuspension
+		if err := json.Unmarshal([]byte(d[1]), &record); err != nil {
+			return nil, err
+		}
+		if topic == model.Topic_SuspendVc {
+			return &VcSuspended{VcId: d[0], Record: &record}, nil
+		}
+		return &VcUnsuspended{VcId: d[0], Record: &record}, nil
+	case model.Topic_SetVcTemplate:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		var template model.VcTemplate
+		if err := json.Unmarshal([]byte(d[1]), &template); err != nil {
+			return nil, err
+		}
+		return &VcTemplateSet{Id: d[0], Template: &template}, nil
+	case model.Topic_VcIssueLog:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		var log model.VcIssueLog
+		if err := json.Unmarshal([]byte(d[1]), &log); err != nil {
+			return nil, err
+		}
+		return &VcIssueLogged{VcId: d[0], Log: &log}, nil
+	case model.Topic_SetStatusList:
+		if err := checkLen(d, 2); err != nil {
+			return nil, err
+		}
+		return &StatusListSet{Id: d[0], Issuer: d[1]}, nil
+	default:
+		return d, nil
+	}
+}
+
+// checkLen 检查事件数据的个数
+func checkLen(d []string, n int) error {
+	if len(d) < n {
+		return fmt.Errorf("the event data should have %d items, got %d", n, len(d))
+	}
+	return nil
+}
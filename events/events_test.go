@@ -0,0 +1,192 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package events
+
+import (
+	"context"
+	"did-sdk/did"
+	"did-sdk/invoke"
+	"did-sdk/key"
+	"did-sdk/simulator"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/test-go/testify/require"
+)
+
+// addTestDid 生成密钥并将DID Document上链，返回DID
+func addTestDid(t *testing.T, sim *simulator.Simulator) string {
+	keyInfo, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	c, err := sim.NewClient(keyInfo.PkPEM)
+	require.Nil(t, err)
+
+	doc, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
+	require.Nil(t, err)
+
+	_, err = did.AddDidDocToChain(string(doc), c)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+	err = json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+
+	return document.Id
+}
+
+// flakyClient 每次订阅只送达limit个事件后断开，用于测试重新订阅
+// 重新订阅时会再次收到最后送达区块中的事件，limit需要大于1
+type flakyClient struct {
+	*simulator.Client
+	limit int
+}
+
+func (c *flakyClient) SubscribeContractEvent(ctx context.Context, startBlock, endBlock int64, contractName,
+	topic string) (<-chan interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	in, err := c.Client.SubscribeContractEvent(ctx, startBlock, endBlock, contractName, topic)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan interface{})
+	go func() {
+		defer cancel()
+		defer close(out)
+
+		for i := 0; i < c.limit; i++ {
+			e, ok := <-in
+			if !ok {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- e:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// nextEvent 在超时时间内读取下一个事件
+func nextEvent(t *testing.T, sub *Subscription) *Event {
+	select {
+	case e, ok := <-sub.Events():
+		require.True(t, ok)
+		return e
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "wait event timeout")
+		return nil
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+
+	// 订阅前上链的事件从开始区块重放
+	did1 := addTestDid(t, sim)
+	did2 := addTestDid(t, sim)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &flakyClient{Client: sim.CreatorClient(), limit: 2}
+	sub, err := Subscribe(ctx, invoke.NewClient(client, ""), Options{
+		StartBlock:        0,
+		Topics:            []string{model.Topic_SetDidDocument, model.Topic_AddBlackList},
+		ReconnectInterval: time.Millisecond,
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), sub.LastBlockHeight())
+
+	e := nextEvent(t, sub)
+	require.Equal(t, model.Topic_SetDidDocument, e.Topic)
+	require.Equal(t, invoke.DIDContractName, e.ContractName)
+	require.Equal(t, did1, e.Data.(*DidDocumentSet).Did)
+	require.Equal(t, did2, nextEvent(t, sub).Data.(*DidDocumentSet).Did)
+
+	// 订阅后上链的事件，每个事件之后订阅都会断开并从最后送达的区块重新订阅，不会重复送达
+	did3 := addTestDid(t, sim)
+	_, err = did.AddDidBlackListToChain([]string{did1, did2}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	e = nextEvent(t, sub)
+	require.Equal(t, did3, e.Data.(*DidDocumentSet).Did)
+
+	e = nextEvent(t, sub)
+	require.Equal(t, model.Topic_AddBlackList, e.Topic)
+	require.Equal(t, []string{did1, did2}, e.Data.(*BlackListAdded).Dids)
+	require.Equal(t, int64(e.BlockHeight), sub.LastBlockHeight())
+
+	select {
+	case e = <-sub.Events():
+		require.FailNow(t, "duplicate event", e.TxId)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.Equal(t, errSubscriptionClosed, <-sub.Errors())
+
+	cancel()
+	_, ok := <-sub.Events()
+	require.False(t, ok)
+}
+
+func TestSubscribeUnsupportedClient(t *testing.T) {
+	var client struct{ invoke.ChainClient }
+	_, err := Subscribe(context.Background(), client, Options{})
+	require.NotNil(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	e, err := Decode(&common.ContractEventInfo{
+		Topic:     model.Topic_RevokeVc,
+		TxId:      "tx1",
+		EventData: []string{"vc1"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, &VcRevoked{VcId: "vc1"}, e.Data)
+
+	e, err = Decode(&common.ContractEventInfo{
+		Topic:     model.Topic_RevokeVc,
+		EventData: []string{"vc1", `{"issuer":"did:1","vcId":"vc1","reasonCode":4,"revokeTime":1}`},
+	})
+	require.Nil(t, err)
+	require.Equal(t, &VcRevoked{VcId: "vc1", Record: model.NewVcRevocation("did:1", "vc1",
+		model.RevocationReasonFraud, "", "", 1)}, e.Data)
+
+	e, err = Decode(&common.ContractEventInfo{
+		Topic:     model.Topic_UnsuspendVc,
+		EventData: []string{"vc1", `{"vcId":"vc1","reason":"cleared","time":1}`},
+	})
+	require.Nil(t, err)
+	require.Equal(t, &VcUnsuspended{VcId: "vc1", Record: &model.VcSuspension{VcId: "vc1", Reason: "cleared", Time: 1}},
+		e.Data)
+
+	e, err = Decode(&common.ContractEventInfo{Topic: model.Topic_SetStatusList, EventData: []string{"sl1", "did:1"}})
+	require.Nil(t, err)
+	require.Equal(t, &StatusListSet{Id: "sl1", Issuer: "did:1"}, e.Data)
+
+	_, err = Decode(&common.ContractEventInfo{Topic: model.Topic_SetVcTemplate, EventData: []string{"1", "{"}})
+	require.NotNil(t, err)
+
+	// 未知主题保留原始数据
+	e, err = Decode(&common.ContractEventInfo{Topic: "unknown", EventData: []string{"a"}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"a"}, e.Data)
+}
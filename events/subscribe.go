@@ -0,0 +1,228 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package events
+
+import (
+	"context"
+	"did-sdk/invoke"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// EventClient 订阅合约事件依赖的链客户端接口，长安链SDK的*ChainClient和链模拟器的客户端实现了该接口
+type EventClient interface {
+	// SubscribeContractEvent 订阅合约事件，channel中的元素为*common.ContractEventInfo
+	SubscribeContractEvent(ctx context.Context, startBlock, endBlock int64, contractName,
+		topic string) (<-chan interface{}, error)
+}
+
+// Options 订阅选项
+type Options struct {
+	// StartBlock 开始的区块高度（包含该区块），-1表示从最新区块开始
+	StartBlock int64
+	// Topics 订阅的事件主题，如model.Topic_RevokeVc，为空表示订阅所有主题
+	Topics []string
+	// ReconnectInterval 订阅断开后重新订阅前的等待时间，0表示使用DefaultReconnectInterval
+	ReconnectInterval time.Duration
+}
+
+// DefaultReconnectInterval 订阅断开后重新订阅前的默认等待时间
+var DefaultReconnectInterval = 3 * time.Second
+
+// errSubscriptionClosed 链客户端关闭了订阅
+var errSubscriptionClosed = errors.New("the subscription is closed by chain client")
+
+// Subscription DID合约事件的订阅，订阅断开后从最后送达事件的区块重新订阅，已送达的事件不会重复送达
+type Subscription struct {
+	client       EventClient
+	contractName string
+	opts         Options
+	topics       map[string]bool
+
+	events chan *Event
+	errs   chan error
+
+	mu sync.Mutex
+	// height 最后送达事件的区块高度，delivered为该区块中已送达的事件
+	height    int64
+	delivered map[string]bool
+}
+
+// Subscribe 订阅DID合约事件，ctx结束时取消订阅并关闭Events()
+// 同一个Subscription内重新订阅时不会重复送达事件，但已送达事件的记录只保存在内存中，
+// 跨进程继续订阅时事件至少送达一次，调用方需要根据TxId和EventIndex去重
+// @params ctx: 订阅的上下文
+// @params client: 链客户端连接，需要实现EventClient接口，可以是invoke.NewClient绑定了合约名称的客户端
+// @params opts: 订阅选项
+func Subscribe(ctx context.Context, client invoke.ChainClient, opts Options) (*Subscription, error) {
+	contractName := invoke.ContractNameOf(client)
+
+	if c, ok := client.(*invoke.Client); ok {
+		client = c.ChainClient
+	}
+
+	eventClient, ok := client.(EventClient)
+	if !ok {
+		return nil, fmt.Errorf("the chain client does not support subscribing contract events")
+	}
+
+	if opts.ReconnectInterval <= 0 {
+		opts.ReconnectInterval = DefaultReconnectInterval
+	}
+
+	s := &Subscription{
+		client:       eventClient,
+		contractName: contractName,
+		opts:         opts,
+		topics:       make(map[string]bool),
+		events:       make(chan *Event),
+		errs:         make(chan error, 16),
+		height:       -1,
+	}
+
+	for _, topic := range opts.Topics {
+		s.topics[topic] = true
+	}
+
+	ch, err := s.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.run(ctx, ch)
+
+	return s, nil
+}
+
+// Events 解码后的合约事件，按区块高度顺序送达，ctx结束后关闭
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Errors 订阅过程中出现的错误，如订阅断开、事件解码失败，不影响后续事件的送达
+// 没有及时读取时丢弃
+func (s *Subscription) Errors() <-chan error {
+	return s.errs
+}
+
+// LastBlockHeight 最后送达事件的区块高度，还没有送达事件时为-1
+// 可以保存该高度，之后以其作为Options.StartBlock继续订阅。新的订阅会从该区块的第一个事件开始，
+// 重复送达该区块中已经处理过的事件，调用方需要根据TxId和EventIndex去重
+func (s *Subscription) LastBlockHeight() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.height
+}
+
+// subscribe 从最后送达事件的区块开始订阅，没有送达过事件时从Options.StartBlock开始
+func (s *Subscription) subscribe(ctx context.Context) (<-chan interface{}, error) {
+	startBlock := s.opts.StartBlock
+	if height := s.LastBlockHeight(); height >= 0 {
+		startBlock = height
+	}
+
+	// 只订阅一个主题时由链过滤，否则订阅所有主题后在本地过滤
+	var topic string
+	if len(s.opts.Topics) == 1 {
+		topic = s.opts.Topics[0]
+	}
+
+	return s.client.SubscribeContractEvent(ctx, startBlock, -1, s.contractName, topic)
+}
+
+// run 接收订阅的事件，订阅断开后重新订阅，直到ctx结束
+func (s *Subscription) run(ctx context.Context, ch <-chan interface{}) {
+	defer close(s.events)
+	defer close(s.errs)
+
+	for {
+		for item := range ch {
+			switch v := item.(type) {
+			case *common.ContractEventInfo:
+				if !s.deliver(ctx, v) {
+					return
+				}
+			case error:
+				s.reportErr(v)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		s.reportErr(errSubscriptionClosed)
+
+		for {
+			timer := time.NewTimer(s.opts.ReconnectInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			var err error
+			ch, err = s.subscribe(ctx)
+			if err == nil {
+				break
+			}
+			s.reportErr(fmt.Errorf("resubscribe contract event failed, err: [%w]", err))
+		}
+	}
+}
+
+// deliver 解码并送达事件，跳过重新订阅后重复的事件，ctx结束时返回false
+func (s *Subscription) deliver(ctx context.Context, info *common.ContractEventInfo) bool {
+	if len(s.topics) != 0 && !s.topics[info.Topic] {
+		return true
+	}
+
+	height := int64(info.BlockHeight)
+	id := info.TxId + "#" + strconv.FormatUint(uint64(info.EventIndex), 10)
+
+	s.mu.Lock()
+	duplicate := height < s.height || (height == s.height && s.delivered[id])
+	s.mu.Unlock()
+	if duplicate {
+		return true
+	}
+
+	event, err := Decode(info)
+	if err != nil {
+		s.reportErr(err)
+	} else {
+		select {
+		case <-ctx.Done():
+			return false
+		case s.events <- event:
+		}
+	}
+
+	s.mu.Lock()
+	if height != s.height {
+		s.height = height
+		s.delivered = make(map[string]bool)
+	}
+	s.delivered[id] = true
+	s.mu.Unlock()
+
+	return true
+}
+
+// reportErr 发送订阅过程中的错误，没有及时读取时丢弃
+func (s *Subscription) reportErr(err error) {
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
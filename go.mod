@@ -4,6 +4,7 @@ go 1.19
 
 require (
 	chainmaker.org/chainmaker/common/v2 v2.3.3
+	chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.5
 	chainmaker.org/chainmaker/did-contract v1.0.0
 	chainmaker.org/chainmaker/pb-go/v2 v2.3.4
 	chainmaker.org/chainmaker/sdk-go/v2 v2.3.4
@@ -14,10 +15,10 @@ require (
 	github.com/test-go/testify v1.1.4
 	github.com/tjfoc/gmsm v1.4.1
 	github.com/xeipuuv/gojsonschema v1.2.0
+	go.etcd.io/bbolt v1.3.5
 )
 
 require (
-	chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.5 // indirect
 	chainmaker.org/chainmaker/protocol/v2 v2.3.4 // indirect
 	chainmaker.org/chainmaker/utils/v2 v2.3.4 // indirect
 	github.com/Rican7/retry v0.1.0 // indirect
@@ -0,0 +1,429 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"did-sdk/events"
+	"did-sdk/invoke"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// 索引数据库中的bucket，索引bucket的key为"索引值\x00主键"，value为空
+// 签发日志和吊销记录的主键为"签发者\x00VC编号"，不同签发者的同名VC互不影响
+var (
+	bucketMeta            = []byte("meta")
+	bucketDidDocument     = []byte("didDocument")
+	bucketDidByController = []byte("didByController")
+	bucketBlackList       = []byte("blackList")
+	bucketVcIssueLog      = []byte("vcIssueLog")
+	bucketLogByHolder     = []byte("vcIssueLogByHolder")
+	bucketLogByIssuer     = []byte("vcIssueLogByIssuer")
+	bucketLogByTemplate   = []byte("vcIssueLogByTemplate")
+	bucketRevokedVc       = []byte("revokedVc")
+	bucketRevokedVcByTime = []byte("revokedVcByTime")
+
+	allBuckets = [][]byte{bucketMeta, bucketDidDocument, bucketDidByController, bucketBlackList, bucketVcIssueLog,
+		bucketLogByHolder, bucketLogByIssuer, bucketLogByTemplate, bucketRevokedVc, bucketRevokedVcByTime}
+)
+
+// meta中的key
+var (
+	keyBlockHeight   = []byte("blockHeight")
+	keyContractName  = []byte("contractName")
+	keyFormatVersion = []byte("formatVersion")
+)
+
+// formatVersion 索引数据的格式版本，打开格式版本不同的数据库时清空索引，重新从第一个区块开始索引
+// 0: 签发日志和吊销记录只按VC编号存储
+// 1: 签发日志和吊销记录按签发者和VC编号存储
+const formatVersion = 1
+
+// Indexer DID合约的本地索引，订阅合约事件并写入本地的bbolt数据库，提供链上列表查询不支持的索引查询
+type Indexer struct {
+	db *bolt.DB
+}
+
+// RunOptions 运行索引的选项
+type RunOptions struct {
+	// ReconnectInterval 订阅断开或查询交易失败后重试前的等待时间，0表示使用events.DefaultReconnectInterval
+	ReconnectInterval time.Duration
+	// OnError 索引过程中不影响继续运行的错误，如订阅断开、事件解码失败，为nil时忽略
+	OnError func(err error)
+}
+
+// Open 打开或新建索引数据库，同一个数据库文件同时只能被一个进程打开，使用完后需要调用Close
+// @params path: 数据库文件路径
+func Open(path string) (*Indexer, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open index db failed, path: [%s], err: [%w]", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return checkFormatVersion(tx)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &Indexer{db: db}, nil
+}
+
+// checkFormatVersion 格式版本不同时清空索引数据和已索引的区块高度，保留绑定的合约名称
+func checkFormatVersion(tx *bolt.Tx) error {
+	meta := tx.Bucket(bucketMeta)
+
+	version := []byte(strconv.Itoa(formatVersion))
+	if bytes.Equal(meta.Get(keyFormatVersion), version) {
+		return nil
+	}
+
+	for _, name := range allBuckets {
+		if bytes.Equal(name, bucketMeta) {
+			continue
+		}
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	if err := meta.Delete(keyBlockHeight); err != nil {
+		return err
+	}
+	return meta.Put(keyFormatVersion, version)
+}
+
+// Close 关闭索引数据库
+func (ix *Indexer) Close() error {
+	return ix.db.Close()
+}
+
+// LastBlockHeight 已索引的最后一个事件的区块高度，索引为空时为-1
+func (ix *Indexer) LastBlockHeight() (int64, error) {
+	height := int64(-1)
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketMeta).Get(keyBlockHeight)
+		if v != nil {
+			height = int64(binary.BigEndian.Uint64(v))
+		}
+		return nil
+	})
+	return height, err
+}
+
+// Run 订阅DID合约事件并写入索引，直到ctx结束
+// 从已索引的最后一个区块继续订阅，索引为空时从第一个区块开始，重复的事件不影响索引结果
+// @params ctx: 运行的上下文，ctx结束时返回nil
+// @params client: 链客户端连接，需要支持订阅合约事件
+// @params opts: 运行索引的选项
+func (ix *Indexer) Run(ctx context.Context, client invoke.ChainClient, opts RunOptions) error {
+	if opts.ReconnectInterval <= 0 {
+		opts.ReconnectInterval = events.DefaultReconnectInterval
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+
+	err := ix.bindContract(invoke.ContractNameOf(client))
+	if err != nil {
+		return err
+	}
+
+	startBlock, err := ix.LastBlockHeight()
+	if err != nil {
+		return err
+	}
+	if startBlock < 0 {
+		startBlock = 0
+	}
+
+	sub, err := events.Subscribe(ctx, client, events.Options{
+		StartBlock:        startBlock,
+		ReconnectInterval: opts.ReconnectInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	errs := sub.Errors()
+	for {
+		select {
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			opts.OnError(err)
+		case e, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+
+			err = ix.index(ctx, client, e, opts)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+// bindContract 记录索引的合约名称，一个索引数据库只能索引一个合约
+func (ix *Indexer) bindContract(contractName string) error {
+	return ix.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMeta)
+
+		v := b.Get(keyContractName)
+		if v == nil {
+			return b.Put(keyContractName, []byte(contractName))
+		}
+
+		if string(v) != contractName {
+			return fmt.Errorf("the index db belongs to contract [%s], not [%s]", v, contractName)
+		}
+		return nil
+	})
+}
+
+// index 将一个事件写入索引，并在同一个数据库事务中记录已索引的区块高度
+func (ix *Indexer) index(ctx context.Context, client invoke.ChainClient, e *events.Event, opts RunOptions) error {
+	// 早期版本合约的吊销事件中没有吊销记录，使用交易所在区块的时间作为吊销时间
+	var revokeTime int64
+	if d, ok := e.Data.(*events.VcRevoked); ok && d.Record == nil {
+		var err error
+		revokeTime, err = txTime(ctx, client, e.TxId, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ix.db.Update(func(tx *bolt.Tx) error {
+		var err error
+
+		switch d := e.Data.(type) {
+		case *events.DidDocumentSet:
+			err = putDidDocument(tx, d)
+		case *events.BlackListAdded:
+			err = putAll(tx.Bucket(bucketBlackList), d.Dids)
+		case *events.BlackListDeleted:
+			err = deleteAll(tx.Bucket(bucketBlackList), d.Dids)
+		case *events.VcIssueLogged:
+			err = putVcIssueLog(tx, d.Log)
+		case *events.VcRevoked:
+			err = putRevokedVc(tx, newRevokedVc(e, d, revokeTime))
+		}
+		if err != nil {
+			return fmt.Errorf("index event failed, topic: [%s], TxId: [%s], err: [%w]", e.Topic, e.TxId, err)
+		}
+
+		height := make([]byte, 8)
+		binary.BigEndian.PutUint64(height, e.BlockHeight)
+		return tx.Bucket(bucketMeta).Put(keyBlockHeight, height)
+	})
+}
+
+// newRevokedVc 生成索引中的吊销记录，签发者、原因和吊销时间取自事件中的吊销记录
+// @params revokeTime: 事件中没有吊销记录时使用的吊销时间
+func newRevokedVc(e *events.Event, d *events.VcRevoked, revokeTime int64) *RevokedVc {
+	r := &RevokedVc{
+		VcId:        d.VcId,
+		RevokeTime:  revokeTime,
+		BlockHeight: e.BlockHeight,
+		TxId:        e.TxId,
+	}
+
+	if d.Record != nil {
+		r.Issuer = d.Record.Issuer
+		r.ReasonCode = d.Record.ReasonCode
+		r.Reason = d.Record.Reason
+		r.RevokeTime = d.Record.RevokeTime
+	}
+
+	return r
+}
+
+// txTime 查询交易所在区块的时间，查询失败时等待后重试，直到ctx结束
+func txTime(ctx context.Context, client invoke.ChainClient, txId string, opts RunOptions) (int64, error) {
+	for {
+		info, err := client.GetTxByTxId(txId)
+		if err == nil {
+			return info.BlockTimestamp, nil
+		}
+		opts.OnError(fmt.Errorf("get tx failed, TxId: [%s], err: [%w]", txId, err))
+
+		timer := time.NewTimer(opts.ReconnectInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// putDidDocument 保存DID文档，并将控制者索引更新为新文档的控制者
+func putDidDocument(tx *bolt.Tx, d *events.DidDocumentSet) error {
+	var doc model.DidDocument
+	err := json.Unmarshal([]byte(d.Document), &doc)
+	if err != nil {
+		return err
+	}
+
+	docs := tx.Bucket(bucketDidDocument)
+	byController := tx.Bucket(bucketDidByController)
+
+	if old := docs.Get([]byte(d.Did)); old != nil {
+		var oldDoc model.DidDocument
+		err = json.Unmarshal(old, &oldDoc)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range oldDoc.Controller {
+			if err = byController.Delete(indexKey(c, d.Did)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range doc.Controller {
+		if err = byController.Put(indexKey(c, d.Did), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return docs.Put([]byte(d.Did), []byte(d.Document))
+}
+
+// putVcIssueLog 保存VC签发日志，并按持有者、签发者和模板建立索引，同一签发者的同名VC覆盖之前的日志
+func putVcIssueLog(tx *bolt.Tx, log *model.VcIssueLog) error {
+	logs := tx.Bucket(bucketVcIssueLog)
+	key := vcRecordKey(log.Issuer, log.VcId)
+
+	if old := logs.Get(key); old != nil {
+		var oldLog model.VcIssueLog
+		err := json.Unmarshal(old, &oldLog)
+		if err != nil {
+			return err
+		}
+
+		err = updateLogIndex(tx, &oldLog, (*bolt.Bucket).Delete)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+
+	err = logs.Put(key, data)
+	if err != nil {
+		return err
+	}
+
+	return updateLogIndex(tx, log, func(b *bolt.Bucket, key []byte) error {
+		return b.Put(key, []byte{})
+	})
+}
+
+// updateLogIndex 对签发日志的每个索引执行op
+func updateLogIndex(tx *bolt.Tx, log *model.VcIssueLog, op func(b *bolt.Bucket, key []byte) error) error {
+	id := string(vcRecordKey(log.Issuer, log.VcId))
+
+	err := op(tx.Bucket(bucketLogByHolder), indexKey(log.Did, id))
+	if err != nil {
+		return err
+	}
+
+	err = op(tx.Bucket(bucketLogByIssuer), indexKey(log.Issuer, id))
+	if err != nil {
+		return err
+	}
+
+	return op(tx.Bucket(bucketLogByTemplate), indexKey(log.TemplateId, id))
+}
+
+// putRevokedVc 保存吊销记录并按吊销时间建立索引，同一签发者重复吊销时保留第一次的记录
+func putRevokedVc(tx *bolt.Tx, r *RevokedVc) error {
+	revoked := tx.Bucket(bucketRevokedVc)
+	key := vcRecordKey(r.Issuer, r.VcId)
+	if revoked.Get(key) != nil {
+		return nil
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	err = revoked.Put(key, data)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(bucketRevokedVcByTime).Put(timeKey(r.RevokeTime, string(key)), []byte{})
+}
+
+// putAll 以keys为key保存空值
+func putAll(b *bolt.Bucket, keys []string) error {
+	for _, k := range keys {
+		if err := b.Put([]byte(k), []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAll 删除keys
+func deleteAll(b *bolt.Bucket, keys []string) error {
+	for _, k := range keys {
+		if err := b.Delete([]byte(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexKey 索引bucket的key
+func indexKey(value, id string) []byte {
+	return []byte(value + "\x00" + id)
+}
+
+// vcRecordKey 签发日志和吊销记录的主键
+func vcRecordKey(issuer, vcId string) []byte {
+	return indexKey(issuer, vcId)
+}
+
+// timeKey 按时间排序的索引key，时间为8字节大端编码
+func timeKey(t int64, id string) []byte {
+	key := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(key, uint64(t))
+	return append(key, id...)
+}
@@ -0,0 +1,304 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package indexer
+
+import (
+	"context"
+	"did-sdk/did"
+	"did-sdk/invoke"
+	"did-sdk/key"
+	"did-sdk/simulator"
+	"did-sdk/vc"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"github.com/test-go/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// addTestDid 生成密钥并将DID Document上链，返回DID和客户端
+func addTestDid(t *testing.T, sim *simulator.Simulator, controller ...string) (string, *simulator.Client) {
+	keyInfo, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	c, err := sim.NewClient(keyInfo.PkPEM)
+	require.Nil(t, err)
+
+	doc, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo}, c, controller...)
+	require.Nil(t, err)
+
+	_, err = did.AddDidDocToChain(string(doc), c)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+	err = json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+
+	return document.Id, c
+}
+
+// runUntil 运行索引直到索引到指定区块高度
+func runUntil(t *testing.T, ix *Indexer, client invoke.ChainClient, height uint64) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ix.Run(ctx, client, RunOptions{ReconnectInterval: time.Millisecond})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		h, err := ix.LastBlockHeight()
+		require.Nil(t, err)
+		if h >= int64(height) {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "wait index timeout")
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	require.Nil(t, <-done)
+}
+
+func TestIndexer(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(1000)
+
+	issuerDid, issuerClient := addTestDid(t, sim)
+	userDid, _ := addTestDid(t, sim)
+	controlledDid, _ := addTestDid(t, sim, issuerDid)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+
+	for _, id := range []string{"1", "2"} {
+		_, err = vc.AddVcTemplateToChain(id, "身份认证", "v1", template, issuerClient)
+		require.Nil(t, err)
+	}
+
+	for _, l := range [][]string{{"vc1", "1"}, {"vc2", "1"}, {"vc3", "2"}} {
+		_, err = vc.AddVcIssueLogToChain(issuerDid, userDid, l[0], l[1], issuerClient)
+		require.Nil(t, err)
+	}
+
+	_, err = did.AddDidBlackListToChain([]string{controlledDid}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	receipt1, err := vc.RevokeVCOnChain("vc2", issuerClient)
+	require.Nil(t, err)
+	receipt2, err := vc.RevokeVCOnChain("vc1", issuerClient)
+	require.Nil(t, err)
+
+	ix, err := Open(filepath.Join(t.TempDir(), "index.db"))
+	require.Nil(t, err)
+	defer ix.Close()
+
+	height, err := ix.LastBlockHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), height)
+
+	runUntil(t, ix, issuerClient, receipt2.BlockHeight)
+
+	doc, err := ix.GetDidDocument(controlledDid)
+	require.Nil(t, err)
+	require.NotNil(t, doc)
+
+	// 生成的DID文档的控制者包括DID自身
+	dids, err := ix.GetDidsByController(issuerDid, 0, 0)
+	require.Nil(t, err)
+	require.Len(t, dids, 2)
+	require.Contains(t, dids, issuerDid)
+	require.Contains(t, dids, controlledDid)
+
+	blackList, err := ix.GetDidBlackList(0, 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{controlledDid}, blackList)
+
+	logs, err := ix.GetVcIssueLogsByHolder(userDid, 0, 0)
+	require.Nil(t, err)
+	require.Len(t, logs, 3)
+
+	logs, err = ix.GetVcIssueLogsByIssuer(issuerDid, 1, 1)
+	require.Nil(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "vc2", logs[0].VcId)
+
+	logs, err = ix.GetVcIssueLogsByTemplate("2", 0, 0)
+	require.Nil(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "vc3", logs[0].VcId)
+
+	records, err := ix.GetRevokedVcsByTime(receipt1.Timestamp, 0, 0, 0)
+	require.Nil(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "vc2", records[0].VcId)
+	require.Equal(t, receipt1.Timestamp, records[0].RevokeTime)
+	require.Equal(t, "vc1", records[1].VcId)
+
+	records, err = ix.GetRevokedVcsByTime(receipt2.Timestamp, receipt2.Timestamp, 0, 0)
+	require.Nil(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, receipt2.TxId, records[0].TxId)
+
+	// 从已索引的区块继续索引，重复吊销不改变吊销记录
+	_, err = did.DeleteDidBlackListFromChain([]string{controlledDid}, sim.CreatorClient())
+	require.Nil(t, err)
+	receipt3, err := vc.RevokeVCOnChain("vc1", issuerClient)
+	require.Nil(t, err)
+
+	runUntil(t, ix, issuerClient, receipt3.BlockHeight)
+
+	blackList, err = ix.GetDidBlackList(0, 0)
+	require.Nil(t, err)
+	require.Empty(t, blackList)
+
+	r, err := ix.GetRevokedVc(issuerDid, "vc1")
+	require.Nil(t, err)
+	require.Equal(t, receipt2.TxId, r.TxId)
+	require.Equal(t, issuerDid, r.Issuer)
+
+	r, err = ix.GetRevokedVc(issuerDid, "vc3")
+	require.Nil(t, err)
+	require.Nil(t, r)
+
+	// 一个索引数据库只能索引一个合约
+	err = ix.Run(context.Background(), invoke.NewClient(issuerClient, "other"), RunOptions{})
+	require.NotNil(t, err)
+}
+
+func TestIndexerSameVcIdFromDifferentIssuers(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(1000)
+
+	issuerA, clientA := addTestDid(t, sim)
+	issuerB, clientB := addTestDid(t, sim)
+	userDid, _ := addTestDid(t, sim)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, clientA)
+	require.Nil(t, err)
+
+	// 两个签发者签发同名VC
+	_, err = vc.AddVcIssueLogToChain(issuerA, userDid, "vc1", "1", clientA)
+	require.Nil(t, err)
+	_, err = vc.AddVcIssueLogToChain(issuerB, userDid, "vc1", "1", clientB)
+	require.Nil(t, err)
+
+	sim.SetTxTime(2000)
+	receipt, err := vc.RevokeVCWithReasonOnChain("", "vc1", model.RevocationReasonFraud, "fraud", clientB)
+	require.Nil(t, err)
+
+	ix, err := Open(filepath.Join(t.TempDir(), "index.db"))
+	require.Nil(t, err)
+	defer ix.Close()
+
+	runUntil(t, ix, clientA, receipt.BlockHeight)
+
+	// 签发日志互不覆盖
+	for _, issuer := range []string{issuerA, issuerB} {
+		log, err := ix.GetVcIssueLog(issuer, "vc1")
+		require.Nil(t, err)
+		require.Equal(t, issuer, log.Issuer)
+
+		logs, err := ix.GetVcIssueLogsByIssuer(issuer, 0, 0)
+		require.Nil(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, issuer, logs[0].Issuer)
+	}
+
+	logs, err := ix.GetVcIssueLogsByHolder(userDid, 0, 0)
+	require.Nil(t, err)
+	require.Len(t, logs, 2)
+
+	// 吊销一个签发者的VC不影响另一个签发者的同名VC，吊销记录取自事件
+	r, err := ix.GetRevokedVc(issuerA, "vc1")
+	require.Nil(t, err)
+	require.Nil(t, r)
+
+	r, err = ix.GetRevokedVc(issuerB, "vc1")
+	require.Nil(t, err)
+	require.Equal(t, &RevokedVc{
+		Issuer:      issuerB,
+		VcId:        "vc1",
+		ReasonCode:  model.RevocationReasonFraud,
+		Reason:      "fraud",
+		RevokeTime:  2000,
+		BlockHeight: receipt.BlockHeight,
+		TxId:        receipt.TxId,
+	}, r)
+
+	// 之后吊销另一个签发者的同名VC时不会被当作重复吊销
+	receipt, err = vc.RevokeVCOnChain("vc1", clientA)
+	require.Nil(t, err)
+
+	runUntil(t, ix, clientA, receipt.BlockHeight)
+
+	r, err = ix.GetRevokedVc(issuerA, "vc1")
+	require.Nil(t, err)
+	require.Equal(t, receipt.TxId, r.TxId)
+
+	records, err := ix.GetRevokedVcsByTime(0, 0, 0, 0)
+	require.Nil(t, err)
+	require.Len(t, records, 2)
+}
+
+func TestIndexerResetsOnFormatChange(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := simulator.NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+
+	issuerDid, issuerClient := addTestDid(t, sim)
+	receipt, err := did.AddDidBlackListToChain([]string{issuerDid}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), "index.db")
+	ix, err := Open(path)
+	require.Nil(t, err)
+
+	runUntil(t, ix, issuerClient, receipt.BlockHeight)
+
+	// 模拟早期版本格式的索引数据库
+	err = ix.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Delete(keyFormatVersion)
+	})
+	require.Nil(t, err)
+	require.Nil(t, ix.Close())
+
+	// 重新打开时清空索引，从第一个区块重新索引
+	ix, err = Open(path)
+	require.Nil(t, err)
+	defer ix.Close()
+
+	height, err := ix.LastBlockHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), height)
+
+	blackList, err := ix.GetDidBlackList(0, 0)
+	require.Nil(t, err)
+	require.Empty(t, blackList)
+
+	runUntil(t, ix, issuerClient, receipt.BlockHeight)
+
+	blackList, err = ix.GetDidBlackList(0, 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{issuerDid}, blackList)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package indexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// RevokedVc 索引中的VC吊销记录
+type RevokedVc struct {
+	// Issuer VC的签发者DID，为空表示签发者未知的吊销记录，对所有签发者生效
+	Issuer     string `json:"issuer,omitempty"`
+	VcId       string `json:"vcId"`
+	ReasonCode int    `json:"reasonCode"`
+	Reason     string `json:"reason,omitempty"`
+	// RevokeTime 吊销时间（Unix秒），早期版本合约的吊销为交易所在区块的时间
+	RevokeTime  int64  `json:"revokeTime"`
+	BlockHeight uint64 `json:"blockHeight"`
+	TxId        string `json:"txId"`
+}
+
+// GetDidDocument 从索引获取DID文档，不存在时返回nil
+// @params did: DID
+func (ix *Indexer) GetDidDocument(did string) ([]byte, error) {
+	var doc []byte
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucketDidDocument).Get([]byte(did)); v != nil {
+			doc = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	return doc, err
+}
+
+// GetDidsByController 从索引获取控制者为controller的DID列表，按DID排序
+// @params controller: 控制者DID
+// @params start: 开始的索引，0表示从第一个开始
+// @params count: 要获取的数量，0表示获取所有
+func (ix *Indexer) GetDidsByController(controller string, start, count int) ([]string, error) {
+	return ix.scanIndex(bucketDidByController, controller, start, count)
+}
+
+// GetDidBlackList 从索引获取DID黑名单，按DID排序
+// @params start: 开始的索引，0表示从第一个开始
+// @params count: 要获取的数量，0表示获取所有
+func (ix *Indexer) GetDidBlackList(start, count int) ([]string, error) {
+	var dids []string
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		return scan(tx.Bucket(bucketBlackList), nil, nil, start, count, func(k []byte) error {
+			dids = append(dids, string(k))
+			return nil
+		})
+	})
+	return dids, err
+}
+
+// GetVcIssueLog 从索引获取签发者的VC签发日志，不存在时返回nil
+// @params issuer: 签发者DID
+// @params vcId: VC编号
+func (ix *Indexer) GetVcIssueLog(issuer, vcId string) (*model.VcIssueLog, error) {
+	var log *model.VcIssueLog
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketVcIssueLog).Get(vcRecordKey(issuer, vcId))
+		if v == nil {
+			return nil
+		}
+		log = new(model.VcIssueLog)
+		return json.Unmarshal(v, log)
+	})
+	return log, err
+}
+
+// GetVcIssueLogsByHolder 从索引获取签发给holder的VC签发日志，按签发者和VC编号排序
+// @params holder: 被签发者DID
+// @params start: 开始的索引，0表示从第一个开始
+// @params count: 要获取的数量，0表示获取所有
+func (ix *Indexer) GetVcIssueLogsByHolder(holder string, start, count int) ([]model.VcIssueLog, error) {
+	return ix.getVcIssueLogs(bucketLogByHolder, holder, start, count)
+}
+
+// GetVcIssueLogsByIssuer 从索引获取issuer签发的VC签发日志，按VC编号排序
+// @params issuer: 签发者DID
+// @params start: 开始的索引，0表示从第一个开始
+// @params count: 要获取的数量，0表示获取所有
+func (ix *Indexer) GetVcIssueLogsByIssuer(issuer string, start, count int) ([]model.VcIssueLog, error) {
+	return ix.getVcIssueLogs(bucketLogByIssuer, issuer, start, count)
+}
+
+// GetVcIssueLogsByTemplate 从索引获取使用指定模板签发的VC签发日志，按签发者和VC编号排序
+// @params templateId: VC模板ID
+// @params start: 开始的索引，0表示从第一个开始
+// @params count: 要获取的数量，0表示获取所有
+func (ix *Indexer) GetVcIssueLogsByTemplate(templateId string, start, count int) ([]model.VcIssueLog, error) {
+	return ix.getVcIssueLogs(bucketLogByTemplate, templateId, start, count)
+}
+
+// GetRevokedVc 从索引获取签发者的VC的吊销记录，没有时使用签发者未知的吊销记录，VC没有被吊销时返回nil
+// @params issuer: 签发者DID
+// @params vcId: VC编号
+func (ix *Indexer) GetRevokedVc(issuer, vcId string) (*RevokedVc, error) {
+	var r *RevokedVc
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		revoked := tx.Bucket(bucketRevokedVc)
+
+		v := revoked.Get(vcRecordKey(issuer, vcId))
+		if v == nil {
+			v = revoked.Get(vcRecordKey("", vcId))
+		}
+		if v == nil {
+			return nil
+		}
+		r = new(RevokedVc)
+		return json.Unmarshal(v, r)
+	})
+	return r, err
+}
+
+// GetRevokedVcsByTime 从索引获取吊销时间在[startTime, endTime]内的吊销记录，按吊销时间排序
+// @params startTime: 开始时间（Unix秒）
+// @params endTime: 结束时间（Unix秒），0表示不限制
+// @params start: 开始的索引，0表示从第一个开始
+// @params count: 要获取的数量，0表示获取所有
+func (ix *Indexer) GetRevokedVcsByTime(startTime, endTime int64, start, count int) ([]*RevokedVc, error) {
+	inRange := func(k []byte) bool {
+		return endTime <= 0 || int64(binary.BigEndian.Uint64(k[:8])) <= endTime
+	}
+
+	var records []*RevokedVc
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		revoked := tx.Bucket(bucketRevokedVc)
+
+		return scan(tx.Bucket(bucketRevokedVcByTime), timeKey(startTime, ""), inRange, start, count,
+			func(k []byte) error {
+				r := new(RevokedVc)
+				if err := json.Unmarshal(revoked.Get(k[8:]), r); err != nil {
+					return err
+				}
+				records = append(records, r)
+				return nil
+			})
+	})
+	return records, err
+}
+
+// getVcIssueLogs 按索引bucket获取签发日志
+func (ix *Indexer) getVcIssueLogs(bucket []byte, value string, start, count int) ([]model.VcIssueLog, error) {
+	var logs []model.VcIssueLog
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		ids, err := scanIndexTx(tx, bucket, value, start, count)
+		if err != nil {
+			return err
+		}
+
+		b := tx.Bucket(bucketVcIssueLog)
+		for _, id := range ids {
+			var log model.VcIssueLog
+			if err = json.Unmarshal(b.Get([]byte(id)), &log); err != nil {
+				return err
+			}
+			logs = append(logs, log)
+		}
+		return nil
+	})
+	return logs, err
+}
+
+// scanIndex 获取索引bucket中索引值为value的主键列表
+func (ix *Indexer) scanIndex(bucket []byte, value string, start, count int) ([]string, error) {
+	var ids []string
+	err := ix.db.View(func(tx *bolt.Tx) error {
+		var err error
+		ids, err = scanIndexTx(tx, bucket, value, start, count)
+		return err
+	})
+	return ids, err
+}
+
+// scanIndexTx 在事务中获取索引bucket中索引值为value的主键列表
+func scanIndexTx(tx *bolt.Tx, bucket []byte, value string, start, count int) ([]string, error) {
+	prefix := indexKey(value, "")
+	inRange := func(k []byte) bool {
+		return bytes.HasPrefix(k, prefix)
+	}
+
+	var ids []string
+	err := scan(tx.Bucket(bucket), prefix, inRange, start, count, func(k []byte) error {
+		ids = append(ids, string(k[len(prefix):]))
+		return nil
+	})
+	return ids, err
+}
+
+// scan 从seek开始按key的顺序遍历bucket，直到inRange返回false，跳过前start个，最多遍历count个
+// @params seek: 开始的key，nil表示从第一个开始
+// @params inRange: 判断key是否在遍历范围内，nil表示遍历到最后
+func scan(b *bolt.Bucket, seek []byte, inRange func(k []byte) bool, start, count int,
+	fn func(k []byte) error) error {
+	c := b.Cursor()
+
+	var k []byte
+	if seek == nil {
+		k, _ = c.First()
+	} else {
+		k, _ = c.Seek(seek)
+	}
+
+	for i := 0; k != nil; k, _ = c.Next() {
+		if inRange != nil && !inRange(k) {
+			break
+		}
+		if count > 0 && i >= start+count {
+			break
+		}
+		if i >= start {
+			if err := fn(k); err != nil {
+				return err
+			}
+		}
+		i++
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	sdkutils "chainmaker.org/chainmaker/sdk-go/v2/utils"
+)
+
+// AsyncOptions 异步发送交易的选项，为0的字段使用DefaultAsyncOptions中的值
+type AsyncOptions struct {
+	// Concurrency 同时等待上链的最大交易数，达到上限时Submit阻塞
+	Concurrency int
+	// PollInterval 查询交易结果的间隔
+	PollInterval time.Duration
+	// Timeout 交易提交后等待上链的超时时间
+	Timeout time.Duration
+}
+
+// DefaultAsyncOptions 异步发送交易的默认选项
+var DefaultAsyncOptions = AsyncOptions{
+	Concurrency:  100,
+	PollInterval: 500 * time.Millisecond,
+	Timeout:      time.Minute,
+}
+
+// ErrAsyncInvokerClosed 异步发送器已关闭，未上链的交易不再等待结果
+var ErrAsyncInvokerClosed = errors.New("async invoker closed")
+
+// Handle 异步发送交易的句柄，交易上链或失败后可以获取交易回执
+type Handle struct {
+	TxId string
+
+	contractAndMethodName string
+	deadline              time.Time
+
+	done    chan struct{}
+	receipt *TxReceipt
+	err     error
+}
+
+// Done 交易上链或失败后关闭的channel
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Result 获取交易回执，交易还未上链时返回错误
+func (h *Handle) Result() (*TxReceipt, error) {
+	select {
+	case <-h.done:
+		return h.receipt, h.err
+	default:
+		return nil, fmt.Errorf("[%s] tx is pending, TxId: [%s]", h.contractAndMethodName, h.TxId)
+	}
+}
+
+// Wait 等待交易上链并获取交易回执，合约执行失败时返回错误
+// @params ctx: 调用上下文，ctx结束时不再等待，交易仍然会在后台继续查询
+func (h *Handle) Wait(ctx context.Context) (*TxReceipt, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-h.done:
+		return h.receipt, h.err
+	}
+}
+
+// AsyncInvoker 异步发送合约调用交易，发送后立即返回句柄，由后台协程轮询交易结果
+// 同时等待上链的交易数不超过AsyncOptions.Concurrency
+type AsyncInvoker struct {
+	client ChainClient
+	opts   AsyncOptions
+
+	// slots 控制同时等待上链的交易数
+	slots   chan struct{}
+	mu      sync.Mutex
+	pending map[string]*Handle
+	wg      sync.WaitGroup
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewAsyncInvoker 新建异步发送器并启动轮询交易结果的后台协程，使用完后需要调用Close
+// @params client: 链客户端连接
+// @params opts: 异步发送交易的选项
+func NewAsyncInvoker(client ChainClient, opts AsyncOptions) *AsyncInvoker {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultAsyncOptions.Concurrency
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultAsyncOptions.PollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultAsyncOptions.Timeout
+	}
+
+	a := &AsyncInvoker{
+		client:  client,
+		opts:    opts,
+		slots:   make(chan struct{}, opts.Concurrency),
+		pending: make(map[string]*Handle),
+		closed:  make(chan struct{}),
+	}
+
+	go a.pollLoop()
+
+	return a
+}
+
+// Submit 发送合约调用交易，不等待交易上链
+// 等待上链的交易数达到上限时阻塞，发送失败时按ctx中的重试策略重试
+// @params ctx: 调用上下文，只用于发送交易
+// @params contractName: 合约名称
+// @params method: 方法名称
+// @params params: 合约调用参数
+// @return 交易句柄
+func (a *AsyncInvoker) Submit(ctx context.Context, contractName, method string,
+	params []*common.KeyValuePair) (*Handle, error) {
+
+	contractAndMethodName := contractName + "-" + method
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-a.closed:
+		return nil, ErrAsyncInvokerClosed
+	case a.slots <- struct{}{}:
+	}
+
+	// 生成交易ID，重试时保持不变
+	txId := sdkutils.GetRandTxId()
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	resp, err := sendWithRetry(ctx, func(attempt int) (*common.TxResponse, error) {
+		// 调用SDK异步Invoke接口，交易进入交易池后返回
+		resp, err := a.client.InvokeContract(contractName, method, txId, params, requestTimeout(ctx), false)
+
+		// 之前发送的交易已经被链接收
+		if attempt > 0 && isTxDuplicate(resp, err) {
+			return &common.TxResponse{Code: common.TxStatusCode_SUCCESS, TxId: txId}, nil
+		}
+
+		return resp, err
+	})
+	if err != nil {
+		<-a.slots
+		return nil, fmt.Errorf("[%s] send tx failed, TxId: [%s], err: [%w]", contractAndMethodName, txId, err)
+	}
+
+	if resp.Code != common.TxStatusCode_SUCCESS {
+		<-a.slots
+		_, err = parseTxResponse(contractAndMethodName, resp)
+		return nil, err
+	}
+
+	h := &Handle{
+		TxId:                  txId,
+		contractAndMethodName: contractAndMethodName,
+		deadline:              time.Now().Add(a.opts.Timeout),
+		done:                  make(chan struct{}),
+	}
+
+	a.wg.Add(1)
+	a.mu.Lock()
+	a.pending[txId] = h
+	a.mu.Unlock()
+
+	// 发送期间异步发送器已关闭，轮询协程不会再处理该交易
+	select {
+	case <-a.closed:
+		a.resolve(h, nil, ErrAsyncInvokerClosed)
+	default:
+	}
+
+	return h, nil
+}
+
+// Wait 等待所有已发送的交易上链或失败
+// @params ctx: 调用上下文，ctx结束时不再等待
+func (a *AsyncInvoker) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
+// Close 停止轮询交易结果，未上链的交易以ErrAsyncInvokerClosed结束
+func (a *AsyncInvoker) Close() {
+	a.closeOnce.Do(func() {
+		close(a.closed)
+	})
+}
+
+// pollLoop 定时查询等待上链的交易
+func (a *AsyncInvoker) pollLoop() {
+	ticker := time.NewTicker(a.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.closed:
+			for _, h := range a.pendingHandles() {
+				a.resolve(h, nil, ErrAsyncInvokerClosed)
+			}
+			return
+		case <-ticker.C:
+			a.poll()
+		}
+	}
+}
+
+// poll 查询一遍等待上链的交易，已上链的交易生成交易回执，超时的交易返回错误
+func (a *AsyncInvoker) poll() {
+	for _, h := range a.pendingHandles() {
+		info, err := a.client.GetTxByTxId(h.TxId)
+		if err == nil {
+			var resp *common.TxResponse
+			resp, err = txInfoToResponse(h.TxId, info)
+			if err == nil {
+				a.resolveTx(h, info, resp)
+				continue
+			}
+		}
+
+		// 查询不到交易时继续等待，直到超时
+		if time.Now().After(h.deadline) {
+			a.resolve(h, nil, fmt.Errorf("[%s] wait tx result timeout, TxId: [%s], err: [%w]",
+				h.contractAndMethodName, h.TxId, err))
+		}
+	}
+}
+
+// resolveTx 根据已上链交易的结果结束句柄
+func (a *AsyncInvoker) resolveTx(h *Handle, info *common.TransactionInfo, resp *common.TxResponse) {
+	_, err := parseTxResponse(h.contractAndMethodName, resp)
+	if err != nil {
+		a.resolve(h, nil, err)
+		return
+	}
+
+	receipt := newTxReceipt(resp)
+	receipt.BlockHash = hex.EncodeToString(info.BlockHash)
+
+	a.resolve(h, receipt, nil)
+}
+
+// resolve 设置句柄的结果并释放占用的并发数
+func (a *AsyncInvoker) resolve(h *Handle, receipt *TxReceipt, err error) {
+	a.mu.Lock()
+	_, ok := a.pending[h.TxId]
+	delete(a.pending, h.TxId)
+	a.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	h.receipt = receipt
+	h.err = err
+	close(h.done)
+
+	<-a.slots
+	a.wg.Done()
+}
+
+// pendingHandles 获取等待上链的交易句柄
+func (a *AsyncInvoker) pendingHandles() []*Handle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	handles := make([]*Handle, 0, len(a.pending))
+	for _, h := range a.pending {
+		handles = append(handles, h)
+	}
+	return handles
+}
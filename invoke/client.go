@@ -0,0 +1,43 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+// Client 绑定了DID合约名称的链客户端，实现了ChainClient接口
+// 可以代替ChainClient传入did、vc、vp和admin包的所有接口，用于调用同一条链上部署的多个DID合约
+type Client struct {
+	ChainClient
+	contractName string
+}
+
+// NewClient 新建绑定DID合约名称的链客户端
+// @params client: 链客户端连接
+// @params contractName: DID合约名称，为空时使用DIDContractName
+func NewClient(client ChainClient, contractName string) *Client {
+	if len(contractName) == 0 {
+		contractName = DIDContractName
+	}
+
+	return &Client{
+		ChainClient:  client,
+		contractName: contractName,
+	}
+}
+
+// ContractName DID合约名称
+func (c *Client) ContractName() string {
+	return c.contractName
+}
+
+// ContractNameOf 获取client调用的DID合约名称，client没有绑定合约名称时为DIDContractName
+// @params client: 链客户端连接
+func ContractNameOf(client ChainClient) string {
+	if c, ok := client.(interface{ ContractName() string }); ok {
+		return c.ContractName()
+	}
+	return DIDContractName
+}
@@ -8,75 +8,129 @@ SPDX-License-Identifier: Apache-2.0
 package invoke
 
 import (
+	"context"
 	"fmt"
 
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	sdkutils "chainmaker.org/chainmaker/sdk-go/v2/utils"
 )
 
 // DIDContractName this contract name
 const DIDContractName = "ChainMakerDid"
 
+// ChainClient 合约调用依赖的链客户端接口
+// 长安链SDK的*ChainClient实现了该接口，测试时可以使用simulator包中的进程内链模拟器
+type ChainClient interface {
+	// InvokeContract 发送合约调用交易
+	InvokeContract(contractName, method, txId string, kvs []*common.KeyValuePair, timeout int64,
+		withSyncResult bool) (*common.TxResponse, error)
+	// QueryContract 查询合约，不落块
+	QueryContract(contractName, method string, kvs []*common.KeyValuePair, timeout int64) (*common.TxResponse, error)
+	// GetTxByTxId 根据交易ID查询已上链的交易
+	GetTxByTxId(txId string) (*common.TransactionInfo, error)
+}
+
 // InvokeContract 基于ChainMakerSDK包装的合约调用接口，使用监听交易的方式拿到交易结果
 // @params contractName: 合约名称
 // @params method: 方法名称
 // @params params: 合约调用参数
-// @params client: 长安链SDK客户端连接
+// @params client: 链客户端连接
 // @return 交易里的结果
 func InvokeContract(contractName, method string,
-	params []*common.KeyValuePair, client *cmsdk.ChainClient) ([]byte, error) {
+	params []*common.KeyValuePair, client ChainClient) ([]byte, error) {
+	return InvokeContractCtx(context.Background(), contractName, method, params, client)
+}
+
+// InvokeContractCtx 同InvokeContract，可以通过ctx设置超时时间或取消调用
+// 遇到网络或超时错误时按ctx中的重试策略（默认为DefaultRetryPolicy，不重试）重试，重试时使用相同的交易ID，同一笔交易不会被重复执行
+// @params ctx: 调用上下文，没有截止时间时使用DefaultTimeout
+// @params contractName: 合约名称
+// @params method: 方法名称
+// @params params: 合约调用参数
+// @params client: 链客户端连接
+// @return 交易里的结果
+func InvokeContractCtx(ctx context.Context, contractName, method string,
+	params []*common.KeyValuePair, client ChainClient) ([]byte, error) {
+	resp, err := invokeContract(ctx, contractName, method, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.ContractResult.Result, nil
+}
+
+// invokeContract 发送合约调用交易，返回执行成功的交易结果
+func invokeContract(ctx context.Context, contractName, method string,
+	params []*common.KeyValuePair, client ChainClient) (*common.TxResponse, error) {
 
-	// 生成交易ID
+	// 生成交易ID，重试时保持不变
 	txId := sdkutils.GetRandTxId()
 
 	contractAndMethodName := contractName + "-" + method
 
-	// 调用SDK不同步结果Invoke接口
-	resp, err := client.InvokeContract(contractName, method, txId, params, -1, true)
-	if err != nil {
-		return nil, fmt.Errorf("[%s] send tx failed, err: [%s]", contractAndMethodName, err.Error())
-	}
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
 
-	if resp.Code != common.TxStatusCode_SUCCESS {
-		if resp.ContractResult == nil {
-			return nil,
-				fmt.Errorf("[%s] exec contract failed, TxId: [%s], TxStatusCode: [%s], TxMsg: [%s]",
-					contractAndMethodName,
-					resp.TxId,
-					resp.Code.String(),
-					resp.Message)
+	resp, err := sendWithRetry(ctx, func(attempt int) (*common.TxResponse, error) {
+		// 调用SDK同步结果Invoke接口
+		resp, err := client.InvokeContract(contractName, method, txId, params, requestTimeout(ctx), true)
+
+		// 之前发送的交易已经被链接收，查询交易结果，不再重复执行
+		if attempt > 0 && isTxDuplicate(resp, err) {
+			return getTxResponse(txId, client)
 		}
 
-		return nil,
-			fmt.Errorf("[%s] exec contract failed, TxId: [%s], TxStatusCode: [%s], ContractCode: [%d], Result: [%s]",
-				contractAndMethodName,
-				resp.TxId,
-				resp.Code.String(),
-				resp.ContractResult.Code,
-				string(resp.ContractResult.Result))
+		return resp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[%s] send tx failed, TxId: [%s], err: [%w]", contractAndMethodName, txId, err)
 	}
 
-	return resp.ContractResult.Result, nil
+	if _, err = parseTxResponse(contractAndMethodName, resp); err != nil {
+		return nil, err
+	}
 
+	return resp, nil
 }
 
 // QueryContract 基于ChainMakerSDK包装的合约调用接口，仅查询使用，不落块
 // @params contractName: 合约名称
 // @params method: 方法名称
 // @params params: 合约调用参数
-// @params client: 长安链SDK客户端连接
+// @params client: 链客户端连接
 // @return 交易里的结果
 func QueryContract(contractName, method string,
-	params []*common.KeyValuePair, client *cmsdk.ChainClient) ([]byte, error) {
+	params []*common.KeyValuePair, client ChainClient) ([]byte, error) {
+	return QueryContractCtx(context.Background(), contractName, method, params, client)
+}
+
+// QueryContractCtx 同QueryContract，可以通过ctx设置超时时间或取消调用，发送失败时按重试策略重试
+// @params ctx: 调用上下文，没有截止时间时使用DefaultTimeout
+// @params contractName: 合约名称
+// @params method: 方法名称
+// @params params: 合约调用参数
+// @params client: 链客户端连接
+// @return 交易里的结果
+func QueryContractCtx(ctx context.Context, contractName, method string,
+	params []*common.KeyValuePair, client ChainClient) ([]byte, error) {
 
 	contractAndMethodName := contractName + "-" + method
 
-	resp, err := client.QueryContract(contractName, method, params, -1)
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	resp, err := sendWithRetry(ctx, func(_ int) (*common.TxResponse, error) {
+		return client.QueryContract(contractName, method, params, requestTimeout(ctx))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[%s] send tx failed, err: [%s]", contractAndMethodName, err.Error())
+		return nil, fmt.Errorf("[%s] send tx failed, err: [%w]", contractAndMethodName, err)
 	}
 
+	return parseTxResponse(contractAndMethodName, resp)
+}
+
+// parseTxResponse 解析交易结果，交易执行失败时返回错误
+func parseTxResponse(contractAndMethodName string, resp *common.TxResponse) ([]byte, error) {
 	if resp.Code != common.TxStatusCode_SUCCESS {
 		if resp.ContractResult == nil {
 			return nil,
@@ -87,14 +141,36 @@ func QueryContract(contractName, method string,
 					resp.Message)
 		}
 
-		return nil,
-			fmt.Errorf("[%s] exec contract failed, TxId: [%s], TxStatusCode: [%s], ContractCode: [%d], Result: [%s]",
-				contractAndMethodName,
-				resp.TxId,
-				resp.Code.String(),
-				resp.ContractResult.Code,
-				string(resp.ContractResult.Result))
+		return nil, newContractError(contractAndMethodName, resp)
 	}
 
 	return resp.ContractResult.Result, nil
 }
+
+// getTxResponse 查询已上链交易的结果，转换为发送交易时的返回格式
+func getTxResponse(txId string, client ChainClient) (*common.TxResponse, error) {
+	info, err := client.GetTxByTxId(txId)
+	if err != nil {
+		return nil, err
+	}
+
+	return txInfoToResponse(txId, info)
+}
+
+// txInfoToResponse 将已上链的交易转换为发送交易时的返回格式
+func txInfoToResponse(txId string, info *common.TransactionInfo) (*common.TxResponse, error) {
+	if info == nil || info.Transaction == nil || info.Transaction.Result == nil {
+		return nil, fmt.Errorf("the result of tx not found, TxId: [%s]", txId)
+	}
+
+	result := info.Transaction.Result
+
+	return &common.TxResponse{
+		Code:           result.Code,
+		Message:        result.Message,
+		ContractResult: result.ContractResult,
+		TxId:           txId,
+		TxTimestamp:    info.BlockTimestamp,
+		TxBlockHeight:  info.BlockHeight,
+	}, nil
+}
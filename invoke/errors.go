@@ -0,0 +1,91 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// 合约返回的错误码对应的哨兵错误，合约调用失败时可以通过errors.Is判断错误类型，如:
+// errors.Is(err, invoke.ErrDidNotFound)
+var (
+	ErrInvalidParameter        = model.ErrInvalidParameter
+	ErrPermissionDenied        = model.ErrPermissionDenied
+	ErrMigrationNotFinished    = model.ErrMigrationNotFinished
+	ErrDidNotFound             = model.ErrDidNotFound
+	ErrDidAlreadyExists        = model.ErrDidAlreadyExists
+	ErrInvalidDidDocument      = model.ErrInvalidDidDocument
+	ErrInBlackList             = model.ErrInBlackList
+	ErrNotTrustedIssuer        = model.ErrNotTrustedIssuer
+	ErrInvalidAccreditation    = model.ErrInvalidAccreditation
+	ErrVcTemplateNotFound      = model.ErrVcTemplateNotFound
+	ErrVcTemplateAlreadyExists = model.ErrVcTemplateAlreadyExists
+	ErrInvalidVcTemplate       = model.ErrInvalidVcTemplate
+	ErrInvalidCredential       = model.ErrInvalidCredential
+	ErrRevoked                 = model.ErrRevoked
+	ErrExpired                 = model.ErrExpired
+	ErrStatusListNotFound      = model.ErrStatusListNotFound
+	ErrSuspended               = model.ErrSuspended
+	ErrProposalNotFound        = model.ErrProposalNotFound
+	ErrInvalidProposalStatus   = model.ErrInvalidProposalStatus
+	ErrGovernanceRequired      = model.ErrGovernanceRequired
+)
+
+// ContractError 合约执行失败的错误
+// Code为合约返回的错误码，旧版本合约或没有分类的错误为model.ErrCode_Unknown
+type ContractError struct {
+	// ContractAndMethod 合约名称和方法名称
+	ContractAndMethod string
+	TxId              string
+	TxStatusCode      common.TxStatusCode
+	// ContractCode 合约返回的状态码
+	ContractCode uint32
+	// Code 合约返回的错误码
+	Code model.ErrorCode
+	// Message 合约返回的错误消息，不包含错误码前缀
+	Message string
+	// Result 合约返回的结果
+	Result []byte
+}
+
+// newContractError 根据交易结果生成合约执行失败的错误
+func newContractError(contractAndMethodName string, resp *common.TxResponse) *ContractError {
+	e := model.ParseErrorMessage(resp.ContractResult.Message)
+	return &ContractError{
+		ContractAndMethod: contractAndMethodName,
+		TxId:              resp.TxId,
+		TxStatusCode:      resp.Code,
+		ContractCode:      resp.ContractResult.Code,
+		Code:              e.Code,
+		Message:           e.Message,
+		Result:            resp.ContractResult.Result,
+	}
+}
+
+// Error 错误消息
+func (e *ContractError) Error() string {
+	msg := e.Message
+	if err := e.Unwrap(); err != nil {
+		msg = model.FormatErrorMessage(err)
+	}
+
+	return fmt.Sprintf("[%s] exec contract failed, TxId: [%s], TxStatusCode: [%s], ContractCode: [%d], "+
+		"Result: [%s], Message: [%s]", e.ContractAndMethod, e.TxId, e.TxStatusCode.String(), e.ContractCode,
+		string(e.Result), msg)
+}
+
+// Unwrap 返回带错误码的合约错误，使errors.Is可以与哨兵错误比较
+func (e *ContractError) Unwrap() error {
+	if e.Code == model.ErrCode_Unknown {
+		return nil
+	}
+	return &model.Error{Code: e.Code, Message: e.Message}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// TxReceipt 交易回执，记录上链交易的ID、所在区块、执行结果和发送的合约事件
+type TxReceipt struct {
+	TxId        string `json:"txId"`
+	BlockHeight uint64 `json:"blockHeight"`
+	// BlockHash 区块哈希的十六进制编码，查询不到交易所在区块时为空
+	BlockHash string `json:"blockHash,omitempty"`
+	// Timestamp 交易时间（Unix秒）
+	Timestamp int64 `json:"timestamp"`
+	// Code 合约执行结果码，0表示成功
+	Code uint32 `json:"code"`
+	// Result 合约返回的结果
+	Result  string     `json:"result,omitempty"`
+	Message string     `json:"message,omitempty"`
+	Events  []*TxEvent `json:"events,omitempty"`
+}
+
+// TxEvent 交易发送的合约事件
+type TxEvent struct {
+	Topic        string   `json:"topic"`
+	ContractName string   `json:"contractName"`
+	EventData    []string `json:"eventData"`
+}
+
+// InvokeContractWithReceipt 同InvokeContract，返回交易回执
+// @params contractName: 合约名称
+// @params method: 方法名称
+// @params params: 合约调用参数
+// @params client: 链客户端连接
+func InvokeContractWithReceipt(contractName, method string,
+	params []*common.KeyValuePair, client ChainClient) (*TxReceipt, error) {
+	return InvokeContractWithReceiptCtx(context.Background(), contractName, method, params, client)
+}
+
+// InvokeContractWithReceiptCtx 同InvokeContractCtx，返回交易回执
+// 交易执行成功后查询交易所在的区块，查询失败时回执中的区块哈希为空
+// @params ctx: 调用上下文
+// @params contractName: 合约名称
+// @params method: 方法名称
+// @params params: 合约调用参数
+// @params client: 链客户端连接
+func InvokeContractWithReceiptCtx(ctx context.Context, contractName, method string,
+	params []*common.KeyValuePair, client ChainClient) (*TxReceipt, error) {
+	resp, err := invokeContract(ctx, contractName, method, params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := newTxReceipt(resp)
+
+	info, err := getTransactionInfo(ctx, resp.TxId, client)
+	if err == nil && info != nil {
+		receipt.BlockHash = hex.EncodeToString(info.BlockHash)
+	}
+
+	return receipt, nil
+}
+
+// GetTxReceipt 根据交易ID查询已上链交易的回执
+// @params txId: 交易ID
+// @params client: 链客户端连接
+func GetTxReceipt(txId string, client ChainClient) (*TxReceipt, error) {
+	return GetTxReceiptCtx(context.Background(), txId, client)
+}
+
+// GetTxReceiptCtx 同GetTxReceipt，可以通过ctx设置超时时间或取消调用
+// @params ctx: 调用上下文
+// @params txId: 交易ID
+// @params client: 链客户端连接
+func GetTxReceiptCtx(ctx context.Context, txId string, client ChainClient) (*TxReceipt, error) {
+	info, err := getTransactionInfo(ctx, txId, client)
+	if err != nil {
+		return nil, fmt.Errorf("get tx failed, TxId: [%s], err: [%w]", txId, err)
+	}
+
+	resp, err := txInfoToResponse(txId, info)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := newTxReceipt(resp)
+	receipt.BlockHash = hex.EncodeToString(info.BlockHash)
+
+	return receipt, nil
+}
+
+// getTransactionInfo 查询已上链的交易，ctx结束时不再等待结果
+func getTransactionInfo(ctx context.Context, txId string, client ChainClient) (*common.TransactionInfo, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	var info *common.TransactionInfo
+	_, err := sendWithContext(ctx, func() (*common.TxResponse, error) {
+		var err error
+		info, err = client.GetTxByTxId(txId)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// newTxReceipt 根据交易结果生成交易回执
+func newTxReceipt(resp *common.TxResponse) *TxReceipt {
+	receipt := &TxReceipt{
+		TxId:        resp.TxId,
+		BlockHeight: resp.TxBlockHeight,
+		Timestamp:   resp.TxTimestamp,
+		Message:     resp.Message,
+	}
+
+	if resp.ContractResult != nil {
+		receipt.Code = resp.ContractResult.Code
+		receipt.Result = string(resp.ContractResult.Result)
+
+		if len(receipt.Message) == 0 {
+			receipt.Message = resp.ContractResult.Message
+		}
+
+		for _, e := range resp.ContractResult.ContractEvent {
+			receipt.Events = append(receipt.Events, &TxEvent{
+				Topic:        e.Topic,
+				ContractName: e.ContractName,
+				EventData:    e.EventData,
+			})
+		}
+	}
+
+	return receipt
+}
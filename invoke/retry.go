@@ -0,0 +1,169 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// RetryPolicy 发送交易失败时的重试策略
+type RetryPolicy struct {
+	// MaxRetries 最大重试次数，0表示不重试
+	MaxRetries int
+	// InitialBackoff 第一次重试前的等待时间，之后每次重试翻倍
+	InitialBackoff time.Duration
+	// MaxBackoff 重试等待时间的上限
+	MaxBackoff time.Duration
+}
+
+var (
+	// DefaultTimeout ctx没有设置截止时间时，一次合约调用（包括重试）的超时时间，0表示不限制
+	DefaultTimeout time.Duration
+
+	// DefaultRetryPolicy ctx中没有设置重试策略时使用的默认重试策略，默认不重试，
+	// 需要重试时通过WithRetryPolicy为调用设置重试策略
+	DefaultRetryPolicy = RetryPolicy{
+		MaxRetries:     0,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+)
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy 设置ctx中合约调用的重试策略
+// @params ctx: 调用上下文
+// @params policy: 重试策略
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// retryPolicyFromContext 获取ctx中的重试策略，没有时使用默认重试策略
+func retryPolicyFromContext(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// withDefaultTimeout ctx没有截止时间且设置了DefaultTimeout时为其添加超时时间
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || DefaultTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultTimeout)
+}
+
+// requestTimeout 根据ctx的剩余时间计算长安链SDK请求的超时时间（秒），-1表示使用SDK的默认值
+func requestTimeout(ctx context.Context) int64 {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return -1
+	}
+
+	seconds := int64(math.Ceil(time.Until(deadline).Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// sendWithRetry 发送请求，遇到网络或超时错误时按重试策略退避重试，ctx结束时立即返回且不再重试，
+// 因此同一个交易ID的请求不会同时发送
+// @params send: 发送请求，attempt为已经重试的次数
+func sendWithRetry(ctx context.Context,
+	send func(attempt int) (*common.TxResponse, error)) (*common.TxResponse, error) {
+	policy := retryPolicyFromContext(ctx)
+	backoff := policy.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := sendWithContext(ctx, func() (*common.TxResponse, error) {
+			return send(attempt)
+		})
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if !isTransient(resp, err) || attempt >= policy.MaxRetries {
+			return resp, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// sendWithContext 长安链SDK的请求不支持ctx，在单独的协程中发送，ctx结束时不再等待结果
+// 注意：ctx结束后发送请求的协程不会被取消，会一直运行到SDK请求超时（超时时间由ctx的截止时间计算），
+// 期间交易仍可能上链。调用方需要重新发送前，应先用错误中的TxId查询交易是否已经上链
+func sendWithContext(ctx context.Context,
+	send func() (*common.TxResponse, error)) (*common.TxResponse, error) {
+	type result struct {
+		resp *common.TxResponse
+		err  error
+	}
+
+	ch := make(chan *result, 1)
+	go func() {
+		resp, err := send()
+		ch <- &result{resp: resp, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-ch:
+		return r.resp, r.err
+	}
+}
+
+// isTransient 判断是否是可以重试的临时性错误：长安链SDK遇到网络错误或超时时返回TIMEOUT状态码，
+// 以及网络错误和请求超时。参数错误、合约执行失败等其他错误不重试
+func isTransient(resp *common.TxResponse, err error) bool {
+	if resp != nil && resp.Code == common.TxStatusCode_TIMEOUT {
+		return true
+	}
+
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// isTxDuplicate 判断重发的交易是否因为交易ID重复被拒绝，即之前发送的交易已经被链接收
+func isTxDuplicate(resp *common.TxResponse, err error) bool {
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	} else if resp != nil && resp.Code != common.TxStatusCode_SUCCESS {
+		msg = resp.Message
+	}
+
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "duplicate") || strings.Contains(msg, "already exist")
+}
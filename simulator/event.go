@@ -0,0 +1,105 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// publishEvents 记录交易发送的合约事件并通知订阅者，调用时需要持有instanceMu
+func (s *Simulator) publishEvents(blockHeight uint64, events []*common.ContractEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	for i, e := range events {
+		s.events = append(s.events, &common.ContractEventInfo{
+			BlockHeight:  blockHeight,
+			Topic:        e.Topic,
+			TxId:         e.TxId,
+			EventIndex:   uint32(i),
+			ContractName: e.ContractName,
+			EventData:    e.EventData,
+		})
+	}
+
+	close(s.eventNotify)
+	s.eventNotify = make(chan struct{})
+}
+
+// SubscribeContractEvent 订阅合约事件，与长安链SDK的接口一致
+// channel中的元素为*common.ContractEventInfo，ctx结束或达到结束区块后关闭
+// @params startBlock 开始的区块高度，-1表示从最新区块开始
+// @params endBlock 结束的区块高度，-1表示一直订阅
+// @params contractName 合约名称
+// @params topic 事件主题，为空表示订阅所有主题
+func (s *Simulator) SubscribeContractEvent(ctx context.Context, startBlock, endBlock int64,
+	contractName, topic string) (<-chan interface{}, error) {
+	if contractName != s.contractName {
+		return nil, fmt.Errorf("contract not found, name: [%s]", contractName)
+	}
+
+	instanceMu.Lock()
+	if startBlock < 0 {
+		height, _ := s.sdk.GetBlockHeight()
+		startBlock = int64(height) + 1
+	}
+	instanceMu.Unlock()
+
+	ch := make(chan interface{})
+
+	go func() {
+		defer close(ch)
+
+		next := 0
+		for {
+			instanceMu.Lock()
+			pending := s.events[next:]
+			next = len(s.events)
+			notify := s.eventNotify
+			height, _ := s.sdk.GetBlockHeight()
+			instanceMu.Unlock()
+
+			for _, e := range pending {
+				if int64(e.BlockHeight) < startBlock || (endBlock >= 0 && int64(e.BlockHeight) > endBlock) {
+					continue
+				}
+				if len(topic) != 0 && e.Topic != topic {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- e:
+				}
+			}
+
+			if endBlock >= 0 && int64(height) >= endBlock {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// SubscribeContractEvent 订阅合约事件
+func (c *Client) SubscribeContractEvent(ctx context.Context, startBlock, endBlock int64,
+	contractName, topic string) (<-chan interface{}, error) {
+	return c.sim.SubscribeContractEvent(ctx, startBlock, endBlock, contractName, topic)
+}
@@ -0,0 +1,270 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"did-sdk/invoke"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/did-contract/core"
+	"chainmaker.org/chainmaker/did-contract/mock"
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// instanceMu 合约代码通过全局的sdk.Instance访问链上数据，多个模拟器之间需要串行执行
+var instanceMu sync.Mutex
+
+// Simulator 进程内的链模拟器，直接调用DID合约代码，链上数据保存在内存中
+// 每次调用都是一笔独立的交易，执行成功时提交，失败时回滚
+type Simulator struct {
+	contractName string
+	contract     *core.DidContract
+	sdk          *mock.SDK
+	// txs 已上链的交易，包括执行失败的交易
+	txs map[string]*common.TransactionInfo
+	// events 已上链的合约事件，按区块高度排序
+	events []*common.ContractEventInfo
+	// eventNotify 有新的合约事件时关闭并重新创建，通知订阅者
+	eventNotify chan struct{}
+}
+
+// NewSimulator 新建链模拟器并以默认合约名称invoke.DIDContractName安装DID合约
+// @params creatorPkPem 合约创建者的公钥PEM编码
+// @params didMethod DID Method
+// @params enableTrustIssuer 是否启用信任签发者
+func NewSimulator(creatorPkPem []byte, didMethod string, enableTrustIssuer bool) (*Simulator, error) {
+	return NewSimulatorWithContractName(invoke.DIDContractName, creatorPkPem, didMethod, enableTrustIssuer)
+}
+
+// NewSimulatorWithContractName 新建链模拟器并以指定的合约名称安装DID合约，
+// 调用该合约需要使用invoke.NewClient绑定合约名称
+// @params contractName DID合约名称
+// @params creatorPkPem 合约创建者的公钥PEM编码
+// @params didMethod DID Method
+// @params enableTrustIssuer 是否启用信任签发者
+func NewSimulatorWithContractName(contractName string, creatorPkPem []byte, didMethod string,
+	enableTrustIssuer bool) (*Simulator, error) {
+	creator, err := model.PubKeyPemToSki(string(creatorPkPem))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Simulator{
+		contractName: contractName,
+		contract:     new(core.DidContract),
+		sdk:          mock.NewSDK(creator),
+		txs:          make(map[string]*common.TransactionInfo),
+		eventNotify:  make(chan struct{}),
+	}
+
+	args := map[string][]byte{
+		model.Params_DidMethod:         []byte(didMethod),
+		model.Params_EnableTrustIssuer: []byte(strconv.FormatBool(enableTrustIssuer)),
+	}
+
+	resp, err := s.execute(creator, "", args, true, func() protogo.Response {
+		return s.contract.InitContract()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != common.TxStatusCode_SUCCESS {
+		return nil, fmt.Errorf("install did contract failed, err: [%s]", resp.ContractResult.Message)
+	}
+
+	return s, nil
+}
+
+// NewClient 新建以指定公钥发送交易的客户端
+// @params senderPkPem 交易发送者的公钥PEM编码
+func (s *Simulator) NewClient(senderPkPem []byte) (*Client, error) {
+	sender, err := model.PubKeyPemToSki(string(senderPkPem))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{sim: s, sender: sender}, nil
+}
+
+// CreatorClient 以合约创建者身份发送交易的客户端
+func (s *Simulator) CreatorClient() *Client {
+	creator, _ := s.sdk.GetCreatorPk()
+	return &Client{sim: s, sender: creator}
+}
+
+// SetTxTime 设置下一笔交易的时间，之后每笔交易的时间递增1秒
+// @params txTime 交易时间（Unix秒）
+func (s *Simulator) SetTxTime(txTime int64) {
+	s.sdk.SetTxTime(txTime)
+}
+
+// Events 获取已提交交易发送的合约事件
+// @params topic 事件主题，为空表示获取所有事件
+func (s *Simulator) Events(topic string) []*mock.Event {
+	return s.sdk.Events(topic)
+}
+
+// GetTxByTxId 根据交易ID查询已上链的交易
+// @params txId 交易ID
+func (s *Simulator) GetTxByTxId(txId string) (*common.TransactionInfo, error) {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+
+	info, ok := s.txs[txId]
+	if !ok {
+		return nil, fmt.Errorf("tx not found, TxId: [%s]", txId)
+	}
+	return info, nil
+}
+
+// execute 以指定发送者执行一笔交易
+// @params commit 是否是上链的交易，上链的交易执行成功时提交，并且不能使用重复的交易ID；查询交易不提交
+func (s *Simulator) execute(sender, txId string, args map[string][]byte, commit bool,
+	run func() protogo.Response) (*common.TxResponse, error) {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+
+	if _, ok := s.txs[txId]; ok && commit {
+		return nil, fmt.Errorf("tx duplicate, TxId: [%s]", txId)
+	}
+
+	sdk.Instance = s.sdk
+	s.sdk.SetSender(sender)
+	txId = s.sdk.BeginTx(txId, args)
+
+	result := run()
+
+	if result.Status == sdk.OK && commit {
+		s.sdk.Commit()
+	} else {
+		s.sdk.Rollback()
+	}
+
+	txTime, _ := s.sdk.GetTxTimeStamp()
+	timestamp, _ := strconv.ParseInt(txTime, 10, 64)
+	height, _ := s.sdk.GetBlockHeight()
+
+	resp := &common.TxResponse{
+		Code:          common.TxStatusCode_SUCCESS,
+		TxId:          txId,
+		TxTimestamp:   timestamp,
+		TxBlockHeight: uint64(height),
+		ContractResult: &common.ContractResult{
+			Code:    uint32(result.Status),
+			Result:  result.Payload,
+			Message: result.Message,
+		},
+	}
+
+	if result.Status != sdk.OK {
+		resp.Code = common.TxStatusCode_CONTRACT_FAIL
+		resp.Message = result.Message
+	}
+
+	if commit {
+		resp.ContractResult.ContractEvent = s.txEvents(txId)
+		s.publishEvents(resp.TxBlockHeight, resp.ContractResult.ContractEvent)
+
+		// 模拟器没有真实的区块，使用区块高度和交易ID的哈希作为区块哈希
+		blockHash := sha256.Sum256([]byte(strconv.Itoa(height) + txId))
+
+		s.txs[txId] = &common.TransactionInfo{
+			Transaction: &common.Transaction{
+				Result: &common.Result{
+					Code:           resp.Code,
+					ContractResult: resp.ContractResult,
+					Message:        resp.Message,
+				},
+			},
+			BlockHeight:    resp.TxBlockHeight,
+			BlockHash:      blockHash[:],
+			BlockTimestamp: resp.TxTimestamp,
+		}
+	}
+
+	return resp, nil
+}
+
+// txEvents 获取指定交易发送的合约事件
+func (s *Simulator) txEvents(txId string) []*common.ContractEvent {
+	var events []*common.ContractEvent
+	for _, e := range s.sdk.Events("") {
+		if e.TxId != txId {
+			continue
+		}
+
+		events = append(events, &common.ContractEvent{
+			Topic:        e.Topic,
+			TxId:         e.TxId,
+			ContractName: s.contractName,
+			EventData:    e.Data,
+		})
+	}
+	return events
+}
+
+// Client 模拟器的客户端，实现了invoke.ChainClient接口
+type Client struct {
+	sim    *Simulator
+	sender string
+}
+
+// Sender 交易发送者公钥的SKI
+func (c *Client) Sender() string {
+	return c.sender
+}
+
+// InvokeContract 发送合约调用交易，交易同步执行，忽略超时时间
+// withSyncResult为false时与长安链一致，只返回交易ID，交易结果需要通过GetTxByTxId查询
+func (c *Client) InvokeContract(contractName, method, txId string, kvs []*common.KeyValuePair, _ int64,
+	withSyncResult bool) (*common.TxResponse, error) {
+	resp, err := c.call(contractName, method, txId, kvs, true)
+	if err != nil || withSyncResult {
+		return resp, err
+	}
+
+	return &common.TxResponse{
+		Code: common.TxStatusCode_SUCCESS,
+		TxId: resp.TxId,
+	}, nil
+}
+
+// QueryContract 查询合约，执行结果不会提交
+func (c *Client) QueryContract(contractName, method string, kvs []*common.KeyValuePair,
+	_ int64) (*common.TxResponse, error) {
+	return c.call(contractName, method, "", kvs, false)
+}
+
+func (c *Client) call(contractName, method, txId string, kvs []*common.KeyValuePair,
+	commit bool) (*common.TxResponse, error) {
+	args := make(map[string][]byte, len(kvs))
+	for _, kv := range kvs {
+		args[kv.Key] = kv.Value
+	}
+
+	return c.sim.execute(c.sender, txId, args, commit, func() protogo.Response {
+		if contractName != c.sim.contractName {
+			return sdk.Error(fmt.Sprintf("contract not found, name: [%s]", contractName))
+		}
+		return c.sim.contract.InvokeContract(method)
+	})
+}
+
+// GetTxByTxId 根据交易ID查询已上链的交易
+func (c *Client) GetTxByTxId(txId string) (*common.TransactionInfo, error) {
+	return c.sim.GetTxByTxId(txId)
+}
+
+var _ invoke.ChainClient = (*Client)(nil)
@@ -0,0 +1,915 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package simulator
+
+import (
+	"context"
+	"did-sdk/admin"
+	"did-sdk/did"
+	"did-sdk/invoke"
+	"did-sdk/key"
+	"did-sdk/vc"
+	"did-sdk/vp"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/test-go/testify/require"
+)
+
+// newTestDid 生成密钥并将DID Document上链，返回密钥和DID
+func newTestDid(t *testing.T, sim *Simulator) (*key.KeyInfo, string, *Client) {
+	keyInfo, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	c, err := sim.NewClient(keyInfo.PkPEM)
+	require.Nil(t, err)
+
+	doc, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
+	require.Nil(t, err)
+
+	_, err = did.AddDidDocToChain(string(doc), c)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+	err = json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+
+	return keyInfo, document.Id, c
+}
+
+func TestSimulator(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", true)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	creator := sim.CreatorClient()
+
+	method, err := did.GetDidMethodFromChain(creator)
+	require.Nil(t, err)
+	require.Equal(t, "cm", method)
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	_, userDid, userClient := newTestDid(t, sim)
+
+	ok, err := did.IsValidDidOnChain(userDid, userClient)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// 只有管理员可以添加信任签发者
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDid}, userClient)
+	require.NotNil(t, err)
+
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDid}, creator)
+	require.Nil(t, err)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1", expiration, "1")
+	require.Nil(t, err)
+
+	ok, err = vc.VerifyVCOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// 持有者不能撤销VC，签发者可以
+	_, err = vc.RevokeVCOnChain("vc1", userClient)
+	require.NotNil(t, err)
+
+	_, err = vc.RevokeVCOnChain("vc1", issuerClient)
+	require.Nil(t, err)
+
+	ok, _ = vc.VerifyVCOnChain(string(vcBytes), userClient)
+	require.False(t, ok)
+
+	receipt, err := did.AddDidBlackListToChain([]string{userDid}, creator)
+	require.Nil(t, err)
+	require.NotEmpty(t, receipt.TxId)
+	require.NotEmpty(t, receipt.BlockHash)
+	require.Len(t, receipt.Events, 1)
+	require.Equal(t, model.Topic_AddBlackList, receipt.Events[0].Topic)
+
+	// 根据交易ID查询到的回执与上链时返回的一致
+	got, err := invoke.GetTxReceipt(receipt.TxId, userClient)
+	require.Nil(t, err)
+	require.Equal(t, receipt, got)
+
+	list, err := did.GetDidBlackListFromChain(userDid, 0, 0, userClient)
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+
+	require.Len(t, sim.Events(model.Topic_RevokeVc), 1)
+
+	records, err := admin.GetAuditLogOfDidContract(model.Method_RevokeVc, "", 0, 0, 0, 0, creator)
+	require.Nil(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, issuerClient.Sender(), records[0].Operator)
+	require.Equal(t, issuerDid, records[0].OperatorDid)
+}
+
+// flakyClient 前几次发送交易时交易已经执行，但是丢失了返回结果，模拟网络异常
+type flakyClient struct {
+	*Client
+	failures int
+	sends    int
+	// err 前几次发送返回的错误，为空时与长安链SDK一样返回TIMEOUT状态码和网络错误
+	err error
+}
+
+func (c *flakyClient) InvokeContract(contractName, method, txId string, kvs []*common.KeyValuePair,
+	timeout int64, withSyncResult bool) (*common.TxResponse, error) {
+	c.sends++
+
+	resp, err := c.Client.InvokeContract(contractName, method, txId, kvs, timeout, withSyncResult)
+	if c.failures > 0 {
+		c.failures--
+		if c.err != nil {
+			return nil, c.err
+		}
+		return &common.TxResponse{Code: common.TxStatusCode_TIMEOUT, TxId: txId},
+			errors.New("connection reset by peer")
+	}
+
+	return resp, err
+}
+
+func TestInvokeRetry(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+
+	ctx := invoke.WithRetryPolicy(context.Background(), invoke.RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+	})
+
+	// 重试时使用相同的交易ID，交易只执行一次
+	c := &flakyClient{Client: sim.CreatorClient(), failures: 2}
+	_, err = did.AddDidBlackListToChainCtx(ctx, []string{"did:cm:test1"}, c)
+	require.Nil(t, err)
+	require.Equal(t, 3, c.sends)
+	require.Len(t, sim.Events(model.Topic_AddBlackList), 1)
+
+	// 超过最大重试次数
+	c = &flakyClient{Client: sim.CreatorClient(), failures: 4}
+	_, err = did.AddDidBlackListToChainCtx(ctx, []string{"did:cm:test2"}, c)
+	require.NotNil(t, err)
+	require.Equal(t, 4, c.sends)
+
+	// 合约执行失败不重试
+	c = &flakyClient{Client: sim.CreatorClient()}
+	_, err = did.UpdateDidDocToChainCtx(ctx, "{}", c)
+	require.NotNil(t, err)
+	require.Equal(t, 1, c.sends)
+
+	// 网络错误重试
+	c = &flakyClient{Client: sim.CreatorClient(), failures: 1,
+		err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}}
+	_, err = did.AddDidBlackListToChainCtx(ctx, []string{"did:cm:test3"}, c)
+	require.Nil(t, err)
+	require.Equal(t, 2, c.sends)
+
+	// 网络和超时以外的错误不重试，即使错误消息中包含超时等字样
+	c = &flakyClient{Client: sim.CreatorClient(), failures: 1,
+		err: errors.New("invalid tx params: timeout must be positive, got EOF")}
+	_, err = did.AddDidBlackListToChainCtx(ctx, []string{"did:cm:test4"}, c)
+	require.NotNil(t, err)
+	require.Equal(t, 1, c.sends)
+
+	// 没有设置重试策略时默认不重试
+	c = &flakyClient{Client: sim.CreatorClient(), failures: 1}
+	_, err = did.AddDidBlackListToChain([]string{"did:cm:test5"}, c)
+	require.NotNil(t, err)
+	require.Equal(t, 1, c.sends)
+
+	// ctx结束后不再发送交易
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = did.GetDidMethodFromChainCtx(cancelCtx, c)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestAsyncInvoke(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", true)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	pollInterval := invoke.DefaultAsyncOptions.PollInterval
+	invoke.DefaultAsyncOptions.PollInterval = time.Millisecond
+	defer func() {
+		invoke.DefaultAsyncOptions.PollInterval = pollInterval
+	}()
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	_, userDid, _ := newTestDid(t, sim)
+
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDid}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	requests := make([]*vc.VcIssueRequest, 0)
+	for _, id := range []string{"vc1", "vc2", "vc3", "vc4"} {
+		requests = append(requests, &vc.VcIssueRequest{
+			Subject:        map[string]interface{}{"id": userDid, "name": "小明"},
+			VcId:           id,
+			ExpirationDate: expiration,
+			VcTemplateId:   "1",
+		})
+	}
+
+	// 模板不存在的VC不发送交易
+	requests = append(requests, &vc.VcIssueRequest{
+		Subject:        map[string]interface{}{"id": userDid, "name": "小明"},
+		VcId:           "vc5",
+		ExpirationDate: expiration,
+		VcTemplateId:   "2",
+	})
+
+	results, err := vc.IssueVCBatch(issuerKey.SkPEM, issuerKey.PkPEM, 0, requests, issuerClient, 2)
+	require.Nil(t, err)
+	require.Len(t, results, 5)
+
+	for _, r := range results[:4] {
+		require.Nil(t, r.Err)
+		require.NotEmpty(t, r.Receipt.BlockHash)
+
+		ok, err := vc.VerifyVCOnChain(string(r.Vc), issuerClient)
+		require.Nil(t, err)
+		require.True(t, ok)
+	}
+	require.NotNil(t, results[4].Err)
+	require.Nil(t, results[4].Receipt)
+
+	logs, err := vc.GetVcIssueLogListFromChain("", 0, 0, issuerClient)
+	require.Nil(t, err)
+	require.Len(t, logs, 4)
+
+	// 合约执行失败的交易在结果中返回错误，未签发的VC不能吊销
+	results, err = vc.RevokeVCBatchOnChain([]string{"vc1", "vc2", "vc9"}, issuerClient, 0)
+	require.Nil(t, err)
+	require.Nil(t, results[0].Err)
+	require.Nil(t, results[1].Err)
+	require.NotNil(t, results[2].Err)
+
+	revoked, err := vc.GetVCRevokedListFromChain("", 0, 0, issuerClient)
+	require.Nil(t, err)
+	require.Len(t, revoked, 2)
+
+	// 关闭后未上链的交易不再等待
+	invoker := invoke.NewAsyncInvoker(issuerClient, invoke.AsyncOptions{PollInterval: time.Hour})
+	h, err := invoker.Submit(context.Background(), invoke.DIDContractName, model.Method_RevokeVc, nil)
+	require.Nil(t, err)
+
+	invoker.Close()
+	_, err = h.Wait(context.Background())
+	require.True(t, errors.Is(err, invoke.ErrAsyncInvokerClosed))
+	require.Nil(t, invoker.Wait(context.Background()))
+}
+
+func TestContractError(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", true)
+	require.Nil(t, err)
+
+	_, issuerDid, issuerClient := newTestDid(t, sim)
+	_, userDid, userClient := newTestDid(t, sim)
+
+	_, err = did.GetDidDocFromChain("did:cm:notfound", userClient)
+	require.True(t, errors.Is(err, invoke.ErrDidNotFound))
+	require.False(t, errors.Is(err, invoke.ErrRevoked))
+
+	var contractErr *invoke.ContractError
+	require.True(t, errors.As(err, &contractErr))
+	require.Equal(t, model.ErrCode_DidNotFound, contractErr.Code)
+	require.Equal(t, "the did's doc not found on chain, did: [did:cm:notfound]", contractErr.Message)
+
+	// 只有管理员可以操作黑名单
+	_, err = did.AddDidBlackListToChain([]string{userDid}, userClient)
+	require.True(t, errors.Is(err, invoke.ErrPermissionDenied))
+
+	// 不在信任列表中的签发者不能添加模板
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.True(t, errors.Is(err, invoke.ErrPermissionDenied))
+
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDid}, sim.CreatorClient())
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.True(t, errors.Is(err, invoke.ErrVcTemplateAlreadyExists))
+
+	_, err = did.AddDidBlackListToChain([]string{userDid}, sim.CreatorClient())
+	require.Nil(t, err)
+	_, err = did.GetDidDocFromChain(userDid, userClient)
+	require.True(t, errors.Is(err, invoke.ErrInBlackList))
+}
+
+// checkResults 返回验证报告中每个检查项是否通过
+func checkResults(report *model.VerifyReport) map[string]bool {
+	results := make(map[string]bool)
+	for _, c := range report.Checks {
+		results[c.Name] = c.Passed
+	}
+	return results
+}
+
+func TestVerifyReport(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	issuerKey, _, issuerClient := newTestDid(t, sim)
+	userKey, userDid, userClient := newTestDid(t, sim)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1", expiration, "1")
+	require.Nil(t, err)
+
+	vpBytes, err := vp.GenerateVP(userKey.SkPEM, 0, userDid, "vp1", []string{string(vcBytes)})
+	require.Nil(t, err)
+
+	report, err := vc.VerifyVCReportOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+	require.Equal(t, map[string]bool{
+		model.VerifyCheck_Type:        true,
+		model.VerifyCheck_Date:        true,
+		model.VerifyCheck_Template:    true,
+		model.VerifyCheck_IssuerTrust: true,
+		model.VerifyCheck_Revocation:  true,
+		model.VerifyCheck_BlackList:   true,
+		model.VerifyCheck_Signature:   true,
+	}, checkResults(report))
+
+	report, err = vp.VerifyVPReportOnChain(string(vpBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+	require.Len(t, report.Credentials, 1)
+
+	// 吊销VC并将持有者加入黑名单后，报告列出所有未通过的检查项
+	_, err = vc.RevokeVCOnChain("vc1", issuerClient)
+	require.Nil(t, err)
+	_, err = did.AddDidBlackListToChain([]string{userDid}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	report, err = vc.VerifyVCReportOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.False(t, report.Passed)
+	results := checkResults(report)
+	require.False(t, results[model.VerifyCheck_Revocation])
+	require.False(t, results[model.VerifyCheck_BlackList])
+	require.True(t, results[model.VerifyCheck_Signature])
+	require.True(t, errors.Is(report.Err(), invoke.ErrRevoked))
+	require.Contains(t, report.String(), "revocation: FAIL (the VC is revoked)")
+
+	report, err = vp.VerifyVPReportOnChain(string(vpBytes), userClient)
+	require.Nil(t, err)
+	require.False(t, report.Passed)
+	results = checkResults(report)
+	require.False(t, results[model.VerifyCheck_BlackList])
+	require.False(t, results[model.VerifyCheck_Credential])
+	require.True(t, results[model.VerifyCheck_HolderBinding])
+	require.True(t, results[model.VerifyCheck_Signature])
+	require.False(t, report.Credentials[0].Passed)
+
+	// 原有的验证接口返回第一个未通过的检查项
+	_, err = vc.VerifyVCOnChain(string(vcBytes), userClient)
+	require.True(t, errors.Is(err, invoke.ErrRevoked))
+}
+
+func TestVerifyVCLocal(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", true)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	_, userDid, userClient := newTestDid(t, sim)
+
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDid}, sim.CreatorClient())
+	require.Nil(t, err)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1", expiration, "1")
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// 通过链上查询在本地验证，结果与合约的验证报告相同
+	chainReport, err := vc.VerifyVCReportOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+
+	report, err := vc.VerifyVCLocal(ctx, string(vcBytes), vc.NewChainSource(userClient).Options())
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+	require.Equal(t, chainReport, report)
+
+	// 使用快照离线验证
+	issuerDoc, err := did.GetDidDocFromChain(issuerDid, userClient)
+	require.Nil(t, err)
+	vcTemplate, err := vc.GetVcTemplateFromChain("1", userClient)
+	require.Nil(t, err)
+	issuer, err := did.GetTrustIssuerFromChain(issuerDid, userClient)
+	require.Nil(t, err)
+
+	snapshot := &vc.Snapshot{
+		DidDocuments:      map[string]json.RawMessage{issuerDid: issuerDoc},
+		VcTemplates:       map[string]json.RawMessage{"1": vcTemplate},
+		EnableTrustIssuer: true,
+		TrustIssuers:      []*model.TrustIssuer{issuer},
+	}
+
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.Equal(t, chainReport, report)
+
+	// 吊销VC并将持有者加入黑名单
+	snapshot.RevokedVcs = []*model.VcRevocation{model.NewVcRevocation(issuerDid, "vc1",
+		model.RevocationReasonUnspecified, "", "", 0)}
+	snapshot.BlackList = []*model.BlackListRecord{{Did: userDid}}
+
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	results := checkResults(report)
+	require.False(t, results[model.VerifyCheck_Revocation])
+	require.False(t, results[model.VerifyCheck_BlackList])
+	require.True(t, results[model.VerifyCheck_Signature])
+	require.True(t, errors.Is(report.Err(), invoke.ErrRevoked))
+
+	// 签发者不在可信签发者列表中，关闭可信签发者检查后通过
+	snapshot.RevokedVcs = nil
+	snapshot.BlackList = nil
+	snapshot.TrustIssuers = nil
+
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrNotTrustedIssuer))
+
+	snapshot.EnableTrustIssuer = false
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+
+	// 使用指定的时钟检查过期时间
+	opts := snapshot.Options()
+	opts.Now = func() time.Time {
+		return time.Unix(expiration+1, 0)
+	}
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), opts)
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Date])
+
+	// 黑名单记录是否过期也使用指定的时钟判断
+	blackListExpire := time.Now().Add(time.Hour).Unix()
+	snapshot.BlackList = []*model.BlackListRecord{{Did: userDid, ExpireTime: blackListExpire}}
+	opts.Now = func() time.Time {
+		return time.Unix(blackListExpire+1, 0)
+	}
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), opts)
+	require.Nil(t, err)
+	require.True(t, checkResults(report)[model.VerifyCheck_BlackList])
+
+	opts.Now = func() time.Time {
+		return time.Unix(blackListExpire-1, 0)
+	}
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), opts)
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_BlackList])
+	snapshot.BlackList = nil
+
+	// 缺少签发者的DID文档时签名检查不通过
+	snapshot.DidDocuments = nil
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrDidNotFound))
+
+	report, err = vc.VerifyVCLocal(ctx, "{", snapshot.Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Format])
+
+	_, err = vc.VerifyVCLocal(ctx, string(vcBytes), vc.LocalVerifyOptions{})
+	require.NotNil(t, err)
+
+	// 没有可信签发者数据源时不能验证，不会跳过签发者可信检查
+	opts = snapshot.Options()
+	opts.Trust = nil
+	_, err = vc.VerifyVCLocal(ctx, string(vcBytes), opts)
+	require.NotNil(t, err)
+}
+
+func TestVerifyVPLocal(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	userKey, userDid, userClient := newTestDid(t, sim)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1", expiration, "1")
+	require.Nil(t, err)
+
+	vpExpiration := time.Now().Add(time.Hour).Unix()
+	vpBytes, err := vp.GenerateVPWithOptions(userKey.SkPEM, 0, userDid, "vp1", []string{string(vcBytes)},
+		vp.VpOptions{Challenge: "nonce1", Domain: "verifier.example.com", Expiration: vpExpiration})
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// 挑战和域包含在签名原文中，合约同样可以验证
+	chainReport, err := vp.VerifyVPReportOnChain(string(vpBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, chainReport.Passed, chainReport.String())
+
+	report, err := vp.VerifyVPLocal(ctx, string(vpBytes), "", "", vc.NewChainSource(userClient).Options())
+	require.Nil(t, err)
+	require.Equal(t, chainReport, report)
+
+	// 使用快照离线验证
+	issuerDoc, err := did.GetDidDocFromChain(issuerDid, userClient)
+	require.Nil(t, err)
+	userDoc, err := did.GetDidDocFromChain(userDid, userClient)
+	require.Nil(t, err)
+	vcTemplate, err := vc.GetVcTemplateFromChain("1", userClient)
+	require.Nil(t, err)
+
+	snapshot := &vc.Snapshot{
+		DidDocuments: map[string]json.RawMessage{issuerDid: issuerDoc, userDid: userDoc},
+		VcTemplates:  map[string]json.RawMessage{"1": vcTemplate},
+	}
+
+	report, err = vp.VerifyVPLocal(ctx, string(vpBytes), "nonce1", "verifier.example.com", snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+	require.True(t, checkResults(report)[model.VerifyCheck_Challenge])
+
+	// 挑战或域不一致
+	report, err = vp.VerifyVPLocal(ctx, string(vpBytes), "nonce2", "", snapshot.Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Challenge])
+	require.True(t, checkResults(report)[model.VerifyCheck_Signature])
+
+	report, err = vp.VerifyVPLocal(ctx, string(vpBytes), "", "other.example.com", snapshot.Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Challenge])
+
+	// 篡改挑战后签名验证失败
+	tampered := strings.Replace(string(vpBytes), `"challenge":"nonce1"`, `"challenge":"nonce2"`, 1)
+	report, err = vp.VerifyVPLocal(ctx, tampered, "nonce2", "", snapshot.Options())
+	require.Nil(t, err)
+	results := checkResults(report)
+	require.True(t, results[model.VerifyCheck_Challenge])
+	require.False(t, results[model.VerifyCheck_Signature])
+
+	// VP过期
+	opts := snapshot.Options()
+	opts.Now = func() time.Time {
+		return time.Unix(vpExpiration+1, 0)
+	}
+	report, err = vp.VerifyVPLocal(ctx, string(vpBytes), "", "", opts)
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Date])
+	require.True(t, errors.Is(report.Err(), invoke.ErrExpired))
+
+	// VP中的VC被吊销
+	snapshot.RevokedVcs = []*model.VcRevocation{model.NewVcRevocation(issuerDid, "vc1",
+		model.RevocationReasonUnspecified, "", "", 0)}
+	report, err = vp.VerifyVPLocal(ctx, string(vpBytes), "", "", snapshot.Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Credential])
+	require.Len(t, report.Credentials, 1)
+	require.False(t, checkResults(report.Credentials[0])[model.VerifyCheck_Revocation])
+
+	report, err = vp.VerifyVPLocal(ctx, "{", "", "", snapshot.Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Format])
+}
+
+func TestStatusList(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	_, userDid, userClient := newTestDid(t, sim)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	list, err := vc.NewStatusList("https://example.com/status/1", model.StatusPurpose_Revocation,
+		model.StatusListMinLength)
+	require.Nil(t, err)
+
+	status, err := list.Allocate()
+	require.Nil(t, err)
+	index, err := status.Index()
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVCWithStatus(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1",
+		expiration, "1", status)
+	require.Nil(t, err)
+
+	// 状态列表未发布时验证失败
+	report, err := vc.VerifyVCReportOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrStatusListNotFound))
+
+	listVc, err := list.IssueCredential(issuerKey.SkPEM, 0, issuerDid, expiration)
+	require.Nil(t, err)
+
+	// 只有签发者可以发布状态列表
+	_, err = vc.SetStatusListToChain(listVc, userClient)
+	require.True(t, errors.Is(err, invoke.ErrPermissionDenied))
+
+	_, err = vc.SetStatusListToChain(listVc, issuerClient)
+	require.Nil(t, err)
+
+	report, err = vc.VerifyVCReportOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+
+	// 状态列表可以保存后恢复
+	data, err := json.Marshal(list)
+	require.Nil(t, err)
+	list = &vc.StatusList{}
+	err = json.Unmarshal(data, list)
+	require.Nil(t, err)
+
+	// 吊销后重新发布状态列表
+	err = list.SetStatus(index, true)
+	require.Nil(t, err)
+	listVc, err = list.IssueCredential(issuerKey.SkPEM, 0, issuerDid, expiration)
+	require.Nil(t, err)
+	_, err = vc.SetStatusListToChain(listVc, issuerClient)
+	require.Nil(t, err)
+
+	report, err = vc.VerifyVCReportOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Revocation])
+	require.True(t, errors.Is(report.Err(), invoke.ErrRevoked))
+
+	ctx := context.Background()
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), vc.NewChainSource(userClient).Options())
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrRevoked))
+
+	// 使用快照中的状态列表离线验证
+	issuerDoc, err := did.GetDidDocFromChain(issuerDid, userClient)
+	require.Nil(t, err)
+	vcTemplate, err := vc.GetVcTemplateFromChain("1", userClient)
+	require.Nil(t, err)
+
+	snapshot := &vc.Snapshot{
+		DidDocuments: map[string]json.RawMessage{issuerDid: issuerDoc},
+		VcTemplates:  map[string]json.RawMessage{"1": vcTemplate},
+		StatusLists:  map[string]json.RawMessage{list.Id: listVc},
+	}
+
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrRevoked))
+
+	err = list.SetStatus(index, false)
+	require.Nil(t, err)
+	listVc, err = list.IssueCredential(issuerKey.SkPEM, 0, issuerDid, expiration)
+	require.Nil(t, err)
+	snapshot.StatusLists[list.Id] = listVc
+
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, report.Passed, report.String())
+
+	// 其他签发者签发的状态列表不能用于验证
+	otherKey, otherDid, _ := newTestDid(t, sim)
+	otherDoc, err := did.GetDidDocFromChain(otherDid, userClient)
+	require.Nil(t, err)
+	snapshot.DidDocuments[otherDid] = otherDoc
+	snapshot.StatusLists[list.Id], err = list.IssueCredential(otherKey.SkPEM, 0, otherDid, expiration)
+	require.Nil(t, err)
+
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Revocation])
+
+	snapshot.StatusLists = nil
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), snapshot.Options())
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrStatusListNotFound))
+}
+
+func TestSuspendVC(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	_, userDid, userClient := newTestDid(t, sim)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1", expiration, "1")
+	require.Nil(t, err)
+
+	record, err := vc.GetVcSuspensionFromChain(issuerDid, "vc1", userClient)
+	require.Nil(t, err)
+	require.Nil(t, record)
+
+	// 只有签发者或VC管理员可以暂停
+	_, err = vc.SuspendVCOnChain("", "vc1", "under investigation", userClient)
+	require.True(t, errors.Is(err, invoke.ErrPermissionDenied))
+
+	_, err = vc.SuspendVCOnChain("", "vc1", "under investigation", issuerClient)
+	require.Nil(t, err)
+
+	record, err = vc.GetVcSuspensionFromChain(issuerDid, "vc1", userClient)
+	require.Nil(t, err)
+	require.Equal(t, "under investigation", record.Reason)
+	require.NotZero(t, record.Time)
+
+	_, err = vc.VerifyVCOnChain(string(vcBytes), userClient)
+	require.True(t, errors.Is(err, invoke.ErrSuspended))
+	require.False(t, errors.Is(err, invoke.ErrRevoked))
+
+	ctx := context.Background()
+	report, err := vc.VerifyVCLocal(ctx, string(vcBytes), vc.NewChainSource(userClient).Options())
+	require.Nil(t, err)
+	require.False(t, checkResults(report)[model.VerifyCheck_Revocation])
+	require.True(t, errors.Is(report.Err(), invoke.ErrSuspended))
+
+	// 暂停状态使用快照，其他数据通过链上查询获取
+	opts := vc.NewChainSource(userClient).Options()
+	opts.Status = &vc.Snapshot{SuspendedVcs: []*model.VcSuspension{record}}
+	report, err = vc.VerifyVCLocal(ctx, string(vcBytes), opts)
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrSuspended))
+
+	// 恢复后验证通过
+	_, err = vc.UnsuspendVCOnChain("", "vc1", "investigation closed", issuerClient)
+	require.Nil(t, err)
+	_, err = vc.UnsuspendVCOnChain("", "vc1", "", issuerClient)
+	require.True(t, errors.Is(err, invoke.ErrInvalidParameter))
+
+	ok, err := vc.VerifyVCOnChain(string(vcBytes), userClient)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	require.Len(t, sim.Events(model.Topic_SuspendVc), 1)
+	require.Len(t, sim.Events(model.Topic_UnsuspendVc), 1)
+}
+
+func TestRevokeVCWithReason(t *testing.T) {
+	creatorKey, err := key.GenerateKey("SM2")
+	require.Nil(t, err)
+
+	sim, err := NewSimulator(creatorKey.PkPEM, "cm", false)
+	require.Nil(t, err)
+	sim.SetTxTime(time.Now().Unix())
+
+	issuerKey, issuerDid, issuerClient := newTestDid(t, sim)
+	_, otherDid, otherClient := newTestDid(t, sim)
+	_, userDid, userClient := newTestDid(t, sim)
+
+	template, err := vc.GenerateSimpleVcTemplate(map[string]string{"name": "姓名"})
+	require.Nil(t, err)
+	_, err = vc.AddVcTemplateToChain("1", "身份认证", "v1", template, issuerClient)
+	require.Nil(t, err)
+
+	subject := map[string]interface{}{
+		"id":   userDid,
+		"name": "小明",
+	}
+	expiration := time.Now().Add(48 * time.Hour).Unix()
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, subject, issuerClient, "vc1", expiration, "1")
+	require.Nil(t, err)
+
+	// 其他签发者不能吊销
+	_, err = vc.RevokeVCWithReasonOnChain(otherDid, "vc1", model.RevocationReasonFraud, "fraud", otherClient)
+	require.True(t, errors.Is(err, invoke.ErrPermissionDenied))
+
+	_, err = vc.RevokeVCWithReasonOnChain("", "vc1", model.RevocationReasonKeyCompromise, "key leaked",
+		issuerClient)
+	require.Nil(t, err)
+
+	record, err := vc.GetVcRevocationFromChain(issuerDid, "vc1", userClient)
+	require.Nil(t, err)
+	require.Equal(t, issuerDid, record.Issuer)
+	require.Equal(t, model.RevocationReasonKeyCompromise, record.ReasonCode)
+	require.Equal(t, "key leaked", record.Reason)
+	require.NotEmpty(t, record.Revoker)
+	require.NotZero(t, record.RevokeTime)
+
+	// 吊销记录按签发者区分
+	record, err = vc.GetVcRevocationFromChain(otherDid, "vc1", userClient)
+	require.Nil(t, err)
+	require.Nil(t, record)
+
+	list, err := vc.GetVCRevokedListFromChain("vc1", 0, 0, userClient)
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, issuerDid, list[0].Issuer)
+
+	_, err = vc.VerifyVCOnChain(string(vcBytes), userClient)
+	require.True(t, errors.Is(err, invoke.ErrRevoked))
+
+	report, err := vc.VerifyVCLocal(context.Background(), string(vcBytes), vc.NewChainSource(userClient).Options())
+	require.Nil(t, err)
+	require.True(t, errors.Is(report.Err(), invoke.ErrRevoked))
+
+	revokeEvents := sim.Events(model.Topic_RevokeVc)
+	require.Len(t, revokeEvents, 1)
+	require.Equal(t, []string{"vc1"}, revokeEvents[0].Data[:1])
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vc
+
+import (
+	"context"
+	"did-sdk/did"
+	"did-sdk/invoke"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// VcIssueRequest 批量颁发VC时单个VC的颁发信息，字段含义同IssueVC的参数
+type VcIssueRequest struct {
+	Subject        map[string]interface{}
+	VcId           string
+	ExpirationDate int64
+	VcTemplateId   string
+	VcType         []string
+	// CredentialStatus 可选，VC在状态列表中的位置，同IssueVCWithStatus
+	CredentialStatus *model.CredentialStatus
+}
+
+// VcBatchResult 批量颁发或吊销VC时单个VC的结果
+type VcBatchResult struct {
+	VcId string
+	// Vc 颁发的VC，吊销或颁发失败时为空
+	Vc      []byte
+	Receipt *invoke.TxReceipt
+	// Err 该VC颁发或吊销失败的原因，成功时为nil
+	Err error
+}
+
+// IssueVCBatch 批量颁发VC，签发日志异步上链，同时等待上链的交易数不超过concurrency
+// @params skPem: 私钥的PEM编码
+// @params pkPem: 公钥的PEM编码
+// @params keyIndex：公钥在DID文档中的索引
+// @params requests：每个VC的颁发信息
+// @params client：长安链客户端
+// @params concurrency：同时等待上链的最大交易数，0表示使用invoke.DefaultAsyncOptions
+// @return 与requests顺序一致的颁发结果
+func IssueVCBatch(skPem, pkPem []byte, keyIndex int, requests []*VcIssueRequest, client invoke.ChainClient,
+	concurrency int) ([]*VcBatchResult, error) {
+	return IssueVCBatchCtx(context.Background(), skPem, pkPem, keyIndex, requests, client, concurrency)
+}
+
+// IssueVCBatchCtx 同IssueVCBatch，可以通过ctx设置超时时间或取消调用
+func IssueVCBatchCtx(ctx context.Context, skPem, pkPem []byte, keyIndex int, requests []*VcIssueRequest,
+	client invoke.ChainClient, concurrency int) ([]*VcBatchResult, error) {
+
+	issuer, err := did.GenerateDidByPKCtx(ctx, pkPem, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一批次中相同的模板只从链上获取一次
+	templates := make(map[string]*model.VcTemplate)
+
+	return submitBatch(ctx, client, concurrency, len(requests),
+		func(i int, result *VcBatchResult) ([]*common.KeyValuePair, string, error) {
+			req := requests[i]
+			result.VcId = req.VcId
+
+			template, ok := templates[req.VcTemplateId]
+			if !ok {
+				var err error
+				template, err = getVcTemplateFromChain(ctx, req.VcTemplateId, client)
+				if err != nil {
+					return nil, "", err
+				}
+				templates[req.VcTemplateId] = template
+			}
+
+			vcBytes, didStr, err := generateVC(skPem, issuer, keyIndex, req.Subject, template, req.VcId,
+				req.ExpirationDate, req.CredentialStatus, req.VcType)
+			if err != nil {
+				return nil, "", err
+			}
+			result.Vc = vcBytes
+
+			return vcIssueLogParams(issuer, didStr, req.VcId, req.VcTemplateId), model.Method_VcIssueLog, nil
+		})
+}
+
+// RevokeVCBatchOnChain 在链上批量吊销VC，交易异步上链，同时等待上链的交易数不超过concurrency
+// @params vcIds: 要吊销的VC的ID编号
+// @params client：长安链客户端
+// @params concurrency：同时等待上链的最大交易数，0表示使用invoke.DefaultAsyncOptions
+// @return 与vcIds顺序一致的吊销结果
+func RevokeVCBatchOnChain(vcIds []string, client invoke.ChainClient, concurrency int) ([]*VcBatchResult, error) {
+	return RevokeVCBatchOnChainCtx(context.Background(), vcIds, client, concurrency)
+}
+
+// RevokeVCBatchOnChainCtx 同RevokeVCBatchOnChain，可以通过ctx设置超时时间或取消调用
+func RevokeVCBatchOnChainCtx(ctx context.Context, vcIds []string, client invoke.ChainClient,
+	concurrency int) ([]*VcBatchResult, error) {
+	return submitBatch(ctx, client, concurrency, len(vcIds),
+		func(i int, result *VcBatchResult) ([]*common.KeyValuePair, string, error) {
+			result.VcId = vcIds[i]
+			return revokeVcParams(vcIds[i]), model.Method_RevokeVc, nil
+		})
+}
+
+// submitBatch 依次生成每一项的合约调用参数并异步发送，等待所有交易上链后返回每一项的结果
+// 单项失败记录在结果中，ctx结束时返回错误
+// @params build: 生成第i项的合约调用参数和方法名，返回错误时该项不发送交易
+func submitBatch(ctx context.Context, client invoke.ChainClient, concurrency int, n int,
+	build func(i int, result *VcBatchResult) ([]*common.KeyValuePair, string, error)) ([]*VcBatchResult, error) {
+
+	opts := invoke.DefaultAsyncOptions
+	if concurrency > 0 {
+		opts.Concurrency = concurrency
+	}
+
+	invoker := invoke.NewAsyncInvoker(client, opts)
+	defer invoker.Close()
+
+	results := make([]*VcBatchResult, n)
+	handles := make([]*invoke.Handle, n)
+
+	for i := 0; i < n; i++ {
+		results[i] = &VcBatchResult{}
+
+		params, method, err := build(i, results[i])
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		handles[i], err = invoker.Submit(ctx, invoke.ContractNameOf(client), method, params)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			results[i].Vc = nil
+			results[i].Err = err
+		}
+	}
+
+	err := invoker.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, h := range handles {
+		if h == nil {
+			continue
+		}
+
+		results[i].Receipt, results[i].Err = h.Result()
+		if results[i].Err != nil {
+			results[i].Vc = nil
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vc
+
+import (
+	"context"
+	"crypto/rand"
+	"did-sdk/invoke"
+	"did-sdk/utils"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// allocateRandomTries 随机分配位置的尝试次数，超过后从随机位置开始顺序查找空闲位置
+const allocateRandomTries = 64
+
+// StatusList 签发者维护的状态列表，记录每个位置的状态和是否已分配给VC
+// 可以通过json.Marshal保存，json.Unmarshal恢复
+type StatusList struct {
+	// Id 状态列表凭证的ID，VC的credentialStatus通过该ID找到状态列表
+	Id string
+	// Purpose 状态列表的用途
+	Purpose string
+
+	list      *model.StatusList
+	allocated *model.StatusList
+}
+
+// statusListJson StatusList保存时的格式
+type statusListJson struct {
+	Id            string `json:"id"`
+	Purpose       string `json:"statusPurpose"`
+	EncodedList   string `json:"encodedList"`
+	AllocatedList string `json:"allocatedList"`
+}
+
+// NewStatusList 新建状态列表
+// @params id：状态列表凭证的ID
+// @params purpose：状态列表的用途，目前只支持model.StatusPurpose_Revocation
+// @params length：位数，不能小于model.StatusListMinLength
+func NewStatusList(id, purpose string, length int) (*StatusList, error) {
+	if len(id) == 0 {
+		return nil, errors.New("the id of status list is empty")
+	}
+
+	list, err := model.NewStatusList(length)
+	if err != nil {
+		return nil, err
+	}
+
+	allocated, err := model.NewStatusList(length)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusList{Id: id, Purpose: purpose, list: list, allocated: allocated}, nil
+}
+
+// Allocate 为新签发的VC随机分配一个未使用的位置，返回VC中的credentialStatus
+// 随机分配可以避免验证者通过位置推断VC的签发顺序
+func (l *StatusList) Allocate() (*model.CredentialStatus, error) {
+	length := big.NewInt(int64(l.allocated.Len()))
+
+	for i := 0; i < allocateRandomTries; i++ {
+		n, err := rand.Int(rand.Reader, length)
+		if err != nil {
+			return nil, err
+		}
+
+		index := int(n.Int64())
+		ok, err := l.allocate(index)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return model.NewCredentialStatus(l.Id, l.Purpose, index), nil
+		}
+	}
+
+	n, err := rand.Int(rand.Reader, length)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int(n.Int64())
+	for i := 0; i < l.allocated.Len(); i++ {
+		index := (start + i) % l.allocated.Len()
+		ok, err := l.allocate(index)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return model.NewCredentialStatus(l.Id, l.Purpose, index), nil
+		}
+	}
+
+	return nil, errors.New("the status list is full")
+}
+
+// allocate 位置未分配时标记为已分配并返回true
+func (l *StatusList) allocate(index int) (bool, error) {
+	used, err := l.allocated.Get(index)
+	if err != nil || used {
+		return false, err
+	}
+
+	return true, l.allocated.Set(index, true)
+}
+
+// SetStatus 设置指定位置的状态，修改后需要重新签发并发布状态列表凭证
+// @params index：位置，即credentialStatus中的statusListIndex
+// @params status：状态，true表示已吊销
+func (l *StatusList) SetStatus(index int, status bool) error {
+	return l.list.Set(index, status)
+}
+
+// Status 获取指定位置的状态
+// @params index：位置，即credentialStatus中的statusListIndex
+func (l *StatusList) Status(index int) (bool, error) {
+	return l.list.Get(index)
+}
+
+// IssueCredential 签发状态列表凭证，凭证可以通过SetStatusListToChain发布到链上，也可以由签发者自行发布
+// @params skPem：签发者私钥的PEM编码
+// @params keyIndex：公钥在DID文档中的索引
+// @params issuer：签发者DID，必须与使用该状态列表的VC的签发者相同
+// @params expirationDate：状态列表凭证的到期时间
+func (l *StatusList) IssueCredential(skPem []byte, keyIndex int, issuer string,
+	expirationDate int64) ([]byte, error) {
+	encodedList, err := l.list.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	vc := &model.VerifiableCredential{
+		Context: ContextVC,
+		Id:      l.Id,
+		Type:    []string{model.StatusListCredentialType, "VerifiableCredential"},
+		CredentialSubject: map[string]interface{}{
+			"id":            l.Id + "#list",
+			"type":          model.StatusListSubjectType,
+			"statusPurpose": l.Purpose,
+			"encodedList":   encodedList,
+		},
+		Issuer:         issuer,
+		IssuanceDate:   utils.ISO8601Time(time.Now().Unix()),
+		ExpirationDate: utils.ISO8601Time(expirationDate),
+	}
+
+	return signVC(skPem, keyIndex, vc)
+}
+
+// MarshalJSON 保存状态列表和分配情况
+func (l *StatusList) MarshalJSON() ([]byte, error) {
+	encodedList, err := l.list.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	allocatedList, err := l.allocated.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&statusListJson{
+		Id:            l.Id,
+		Purpose:       l.Purpose,
+		EncodedList:   encodedList,
+		AllocatedList: allocatedList,
+	})
+}
+
+// UnmarshalJSON 恢复MarshalJSON保存的状态列表
+func (l *StatusList) UnmarshalJSON(data []byte) error {
+	var s statusListJson
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	list, err := model.DecodeStatusList(s.EncodedList)
+	if err != nil {
+		return err
+	}
+
+	allocated, err := model.DecodeStatusList(s.AllocatedList)
+	if err != nil {
+		return err
+	}
+
+	if list.Len() != allocated.Len() {
+		return errors.New("the length of allocated list is different from the status list")
+	}
+
+	l.Id, l.Purpose, l.list, l.allocated = s.Id, s.Purpose, list, allocated
+	return nil
+}
+
+// SetStatusListToChain 在链上发布或更新状态列表凭证，只有状态列表凭证的签发者可以发布
+// @params statusListVc：IssueCredential签发的状态列表凭证
+// @params client：长安链客户端（客户端用户需要是签发者）
+func SetStatusListToChain(statusListVc []byte, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return SetStatusListToChainCtx(context.Background(), statusListVc, client)
+}
+
+// SetStatusListToChainCtx 同SetStatusListToChain，可以通过ctx设置超时时间或取消调用
+func SetStatusListToChainCtx(ctx context.Context, statusListVc []byte,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_StatusListCredential,
+		Value: statusListVc,
+	})
+
+	return invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_SetStatusList,
+		params, client)
+}
+
+// GetStatusListFromChain 从链上获取状态列表凭证
+// @params id：状态列表凭证的ID
+// @params client：长安链客户端
+func GetStatusListFromChain(id string, client invoke.ChainClient) ([]byte, error) {
+	return GetStatusListFromChainCtx(context.Background(), id, client)
+}
+
+// GetStatusListFromChainCtx 同GetStatusListFromChain，可以通过ctx设置超时时间或取消调用
+func GetStatusListFromChainCtx(ctx context.Context, id string, client invoke.ChainClient) ([]byte, error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_StatusListId,
+		Value: []byte(id),
+	})
+
+	return invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetStatusList, params, client)
+}
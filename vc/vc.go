@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package vc
 
 import (
+	"context"
 	"did-sdk/did"
 	"did-sdk/invoke"
 	"did-sdk/proof"
@@ -20,7 +21,6 @@ import (
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 	jsonschema "github.com/xeipuuv/gojsonschema"
 )
 
@@ -39,28 +39,72 @@ var ContextVC = []string{
 // @params expirationDate：VC的到期时间
 // @params vcTemplateId：VC的模板Id，在链上获取VC模板
 // @params vcType：VC中的`type`字段，描述VC的类型信息（可变参数，默认会填写“VerifiableCredential”,可继续根据业务类型追加）
-func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{}, client *cmsdk.ChainClient,
-	vcId string, expirationDate int64, vcTemplateId string, vcType ...string) ([]byte, error) {
+// @return VC和签发日志上链的交易回执
+func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{}, client invoke.ChainClient,
+	vcId string, expirationDate int64, vcTemplateId string, vcType ...string) ([]byte, *invoke.TxReceipt, error) {
+	return IssueVCCtx(context.Background(), skPem, pkPem, keyIndex, subject, client, vcId, expirationDate,
+		vcTemplateId, vcType...)
+}
 
-	// 获取sunject中的DID
-	d, ok := subject["id"]
-	if !ok {
-		return nil, errors.New("the id field must be included in the subject")
+// IssueVCCtx 同IssueVC，可以通过ctx设置超时时间或取消调用
+func IssueVCCtx(ctx context.Context, skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
+	client invoke.ChainClient, vcId string, expirationDate int64, vcTemplateId string, vcType ...string) ([]byte,
+	*invoke.TxReceipt, error) {
+	return IssueVCWithStatusCtx(ctx, skPem, pkPem, keyIndex, subject, client, vcId, expirationDate, vcTemplateId,
+		nil, vcType...)
+}
+
+// IssueVCWithStatus 同IssueVC，VC中包含指向状态列表的credentialStatus，吊销时只需要修改状态列表
+// @params status：VC在状态列表中的位置，通过StatusList.Allocate分配
+func IssueVCWithStatus(skPem, pkPem []byte, keyIndex int, subject map[string]interface{}, client invoke.ChainClient,
+	vcId string, expirationDate int64, vcTemplateId string, status *model.CredentialStatus,
+	vcType ...string) ([]byte, *invoke.TxReceipt, error) {
+	return IssueVCWithStatusCtx(context.Background(), skPem, pkPem, keyIndex, subject, client, vcId,
+		expirationDate, vcTemplateId, status, vcType...)
+}
+
+// IssueVCWithStatusCtx 同IssueVCWithStatus，可以通过ctx设置超时时间或取消调用
+func IssueVCWithStatusCtx(ctx context.Context, skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
+	client invoke.ChainClient, vcId string, expirationDate int64, vcTemplateId string,
+	status *model.CredentialStatus, vcType ...string) ([]byte, *invoke.TxReceipt, error) {
+
+	// 链上获取模板
+	template, err := getVcTemplateFromChain(ctx, vcTemplateId, client)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	didStr, ok := d.(string)
-	if !ok {
-		return nil, errors.New("the data type of the id is incorrect")
+	issuer, err := did.GenerateDidByPKCtx(ctx, pkPem, client)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// 链上获取模板
-	vcTemplate, err := GetVcTemplateFromChain(vcTemplateId, client)
+	vcBytesJSON, didStr, err := generateVC(skPem, issuer, keyIndex, subject, template, vcId, expirationDate, status,
+		vcType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 在链上生成签发日志（会对Issuer, did, vcTemplate进行校验）
+	receipt, err := AddVcIssueLogToChainCtx(ctx, issuer, didStr, vcId, vcTemplateId, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vcBytesJSON, receipt, nil
+}
+
+// getVcTemplateFromChain 链上获取VC模板，模板不存在时返回错误
+func getVcTemplateFromChain(ctx context.Context, vcTemplateId string,
+	client invoke.ChainClient) (*model.VcTemplate, error) {
+	vcTemplate, err := GetVcTemplateFromChainCtx(ctx, vcTemplateId, client)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(vcTemplate) == 0 {
-		return nil, errors.New("vc template not found on chain")
+		return nil, model.NewError(model.ErrCode_VcTemplateNotFound, "vc template not found on chain, id: [%s]",
+			vcTemplateId)
 	}
 
 	var template model.VcTemplate
@@ -69,17 +113,33 @@ func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
 		return nil, err
 	}
 
+	return &template, nil
+}
+
+// generateVC 根据链上的VC模板生成并签名VC
+// @return VC和subject中的DID
+func generateVC(skPem []byte, issuer string, keyIndex int, subject map[string]interface{},
+	template *model.VcTemplate, vcId string, expirationDate int64, status *model.CredentialStatus,
+	vcType []string) ([]byte, string, error) {
+
+	// 获取sunject中的DID
+	d, ok := subject["id"]
+	if !ok {
+		return nil, "", errors.New("the id field must be included in the subject")
+	}
+
+	didStr, ok := d.(string)
+	if !ok {
+		return nil, "", errors.New("the data type of the id is incorrect")
+	}
+
 	// 验证subject是否符合VC模板规范
-	ok, err = verifyCredentialSubject(subject, template.Template)
+	ok, err := verifyCredentialSubject(subject, template.Template)
 	if !ok {
-		return nil, err
+		return nil, "", err
 	}
 
 	vcType = append(vcType, "VerifiableCredential")
-	issuer, err := did.GenerateDidByPK(pkPem, client)
-	if err != nil {
-		return nil, err
-	}
 
 	issuanceDate := utils.ISO8601Time(time.Now().Unix())
 	expirationDateStr := utils.ISO8601Time(expirationDate)
@@ -99,8 +159,19 @@ func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
 			ID:   template.Id,
 			Name: template.Name,
 		},
+		CredentialStatus: status,
 	}
 
+	vcBytesJSON, err := signVC(skPem, keyIndex, vc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return vcBytesJSON, didStr, nil
+}
+
+// signVC 使用签发者私钥为VC生成证明，返回带证明的VC
+func signVC(skPem []byte, keyIndex int, vc *model.VerifiableCredential) ([]byte, error) {
 	vcBytes, err := json.Marshal(vc)
 	if err != nil {
 		return nil, err
@@ -111,7 +182,7 @@ func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
 		return nil, err
 	}
 
-	keyId := issuer + did.VerificationMethodKeySuffix + strconv.Itoa(keyIndex)
+	keyId := vc.Issuer + did.VerificationMethodKeySuffix + strconv.Itoa(keyIndex)
 	pf, err := proof.GenerateProofByKey(skPem, msg, keyId)
 	if err != nil {
 		return nil, err
@@ -119,18 +190,7 @@ func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
 
 	vc.Proof = pf
 
-	vcBytesJSON, err := json.Marshal(vc)
-	if err != nil {
-		return nil, err
-	}
-
-	// 在链上生成签发日志（会对Issuer, did, vcTemplate进行校验）
-	err = AddVcIssueLogToChain(issuer, didStr, vcId, vcTemplateId, client)
-	if err != nil {
-		return nil, err
-	}
-
-	return vcBytesJSON, nil
+	return json.Marshal(vc)
 }
 
 // IssueVCLocal 本地颁发VC（不经过链上计算和校验）
@@ -144,6 +204,15 @@ func IssueVC(skPem, pkPem []byte, keyIndex int, subject map[string]interface{},
 // @params vcType：VC中的`type`字段，描述VC的类型信息（可变参数，默认会填写“VerifiableCredential”,可继续根据业务类型追加）
 func IssueVCLocal(skPem []byte, keyIndex int, subject map[string]interface{}, issuer string,
 	vcId string, expirationDate int64, vcTemplate []byte, vcType ...string) ([]byte, error) {
+	return IssueVCLocalWithStatus(skPem, keyIndex, subject, issuer, vcId, expirationDate, vcTemplate, nil,
+		vcType...)
+}
+
+// IssueVCLocalWithStatus 同IssueVCLocal，VC中包含指向状态列表的credentialStatus
+// @params status：VC在状态列表中的位置，通过StatusList.Allocate分配
+func IssueVCLocalWithStatus(skPem []byte, keyIndex int, subject map[string]interface{}, issuer string,
+	vcId string, expirationDate int64, vcTemplate []byte, status *model.CredentialStatus,
+	vcType ...string) ([]byte, error) {
 	// 获取sunject中的DID
 	d, ok := subject["id"]
 	if !ok {
@@ -174,27 +243,10 @@ func IssueVCLocal(skPem []byte, keyIndex int, subject map[string]interface{}, is
 		Issuer:            issuer,
 		IssuanceDate:      issuanceDate,
 		ExpirationDate:    expirationDateStr,
+		CredentialStatus:  status,
 	}
 
-	vcBytes, err := json.Marshal(vc)
-	if err != nil {
-		return nil, err
-	}
-
-	msg, err := utils.CompactJson(vcBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	keyId := issuer + did.VerificationMethodKeySuffix + strconv.Itoa(keyIndex)
-	pf, err := proof.GenerateProofByKey(skPem, msg, keyId)
-	if err != nil {
-		return nil, err
-	}
-
-	vc.Proof = pf
-
-	return json.Marshal(vc)
+	return signVC(skPem, keyIndex, vc)
 }
 
 func verifyCredentialSubject(subject map[string]interface{}, vcTemplate []byte) (bool, error) {
@@ -226,7 +278,12 @@ func verifyCredentialSubject(subject map[string]interface{}, vcTemplate []byte)
 // VerifyVCOnChain 链上验证VC的有效性
 // @params vc: VC的JSON字符串
 // @params client：长安链客户端
-func VerifyVCOnChain(vc string, client *cmsdk.ChainClient) (bool, error) {
+func VerifyVCOnChain(vc string, client invoke.ChainClient) (bool, error) {
+	return VerifyVCOnChainCtx(context.Background(), vc, client)
+}
+
+// VerifyVCOnChainCtx 同VerifyVCOnChain，可以通过ctx设置超时时间或取消调用
+func VerifyVCOnChainCtx(ctx context.Context, vc string, client invoke.ChainClient) (bool, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -234,7 +291,7 @@ func VerifyVCOnChain(vc string, client *cmsdk.ChainClient) (bool, error) {
 		Value: []byte(vc),
 	})
 
-	_, err := invoke.QueryContract(invoke.DIDContractName, model.Method_VerifyVc, params, client)
+	_, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_VerifyVc, params, client)
 	if err != nil {
 		return false, err
 
@@ -243,10 +300,90 @@ func VerifyVCOnChain(vc string, client *cmsdk.ChainClient) (bool, error) {
 	return true, nil
 }
 
+// VerifyVCReportOnChain 链上验证VC，返回每个检查项的结果，检查项未通过时不返回error
+// @params vc: VC的JSON字符串
+// @params client：长安链客户端
+func VerifyVCReportOnChain(vc string, client invoke.ChainClient) (*model.VerifyReport, error) {
+	return VerifyVCReportOnChainCtx(context.Background(), vc, client)
+}
+
+// VerifyVCReportOnChainCtx 同VerifyVCReportOnChain，可以通过ctx设置超时时间或取消调用
+func VerifyVCReportOnChainCtx(ctx context.Context, vc string, client invoke.ChainClient) (*model.VerifyReport,
+	error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_VcJson,
+		Value: []byte(vc),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_VerifyVcReport, params,
+		client)
+	if err != nil {
+		return nil, err
+	}
+
+	var report model.VerifyReport
+	err = json.Unmarshal(resp, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
 // RevokeVCOnChain 在链上吊销VC
 // @params vcId: vc的ID编号
 // @params client：长安链客户端
-func RevokeVCOnChain(vcId string, client *cmsdk.ChainClient) error {
+func RevokeVCOnChain(vcId string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return RevokeVCOnChainCtx(context.Background(), vcId, client)
+}
+
+// RevokeVCOnChainCtx 同RevokeVCOnChain，可以通过ctx设置超时时间或取消调用
+func RevokeVCOnChainCtx(ctx context.Context, vcId string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := revokeVcParams(vcId)
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_RevokeVc,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// RevokeVCWithReasonOnChain 在链上吊销VC并记录原因，吊销记录按签发者区分
+// @params issuer：VC的签发者DID，签发者吊销自己签发的VC时可以为空，VC管理员吊销没有签发日志的VC时必须指定
+// @params vcId：vc的ID编号
+// @params reasonCode：原因编码，见model.RevocationReasonUnspecified等
+// @params reason：原因描述
+// @params client：长安链客户端
+func RevokeVCWithReasonOnChain(issuer, vcId string, reasonCode int, reason string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return RevokeVCWithReasonOnChainCtx(context.Background(), issuer, vcId, reasonCode, reason, client)
+}
+
+// RevokeVCWithReasonOnChainCtx 同RevokeVCWithReasonOnChain，可以通过ctx设置超时时间或取消调用
+func RevokeVCWithReasonOnChainCtx(ctx context.Context, issuer, vcId string, reasonCode int, reason string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := vcRecordParams(issuer, vcId)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_ReasonCode,
+		Value: []byte(strconv.Itoa(reasonCode)),
+	})
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Reason,
+		Value: []byte(reason),
+	})
+
+	return invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_RevokeVc,
+		params, client)
+}
+
+// revokeVcParams 生成吊销VC的合约调用参数
+func revokeVcParams(vcId string) []*common.KeyValuePair {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -254,24 +391,38 @@ func RevokeVCOnChain(vcId string, client *cmsdk.ChainClient) error {
 		Value: []byte(vcId),
 	})
 
-	_, err := invoke.InvokeContract(invoke.DIDContractName, model.Method_RevokeVc, params, client)
-	if err != nil {
-		return err
-	}
+	return params
+}
+
+// vcRecordParams 生成按签发者区分的VC记录的合约调用参数
+func vcRecordParams(issuer, vcId string) []*common.KeyValuePair {
+	params := revokeVcParams(vcId)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Issuer,
+		Value: []byte(issuer),
+	})
 
-	return nil
+	return params
 }
 
-// GetVCRevokedListFromChain 获取链上VC的吊销列表
+// GetVCRevokedListFromChain 获取链上VC的吊销记录列表
 // @params vcIdSearch：要查找的vc编号（空字符串可以查找全部列表）
 // @params start：开始的索引，0表示从第一个开始
 // @params count：要获取的数量，0表示获取所有
 // @params client：长安链客户端
-func GetVCRevokedListFromChain(vcIdSearch string, start int, count int, client *cmsdk.ChainClient) ([]string, error) {
+func GetVCRevokedListFromChain(vcIdSearch string, start int, count int,
+	client invoke.ChainClient) ([]*model.VcRevocation, error) {
+	return GetVCRevokedListFromChainCtx(context.Background(), vcIdSearch, start, count, client)
+}
+
+// GetVCRevokedListFromChainCtx 同GetVCRevokedListFromChain，可以通过ctx设置超时时间或取消调用
+func GetVCRevokedListFromChainCtx(ctx context.Context, vcIdSearch string, start int, count int,
+	client invoke.ChainClient) ([]*model.VcRevocation, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
-		Key:   model.Params_VcTemplateNameSearch,
+		Key:   model.Params_VcIdSearch,
 		Value: []byte(vcIdSearch),
 	})
 
@@ -285,12 +436,13 @@ func GetVCRevokedListFromChain(vcIdSearch string, start int, count int, client *
 		Value: []byte(strconv.Itoa(count)),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetRevokedVcList, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetRevokedVcList,
+		params, client)
 	if err != nil {
 		return nil, err
 	}
 
-	var revokedList []string
+	var revokedList []*model.VcRevocation
 
 	err = json.Unmarshal(resp, &revokedList)
 	if err != nil {
@@ -300,6 +452,104 @@ func GetVCRevokedListFromChain(vcIdSearch string, start int, count int, client *
 	return revokedList, nil
 }
 
+// GetVcRevocationFromChain 从链上获取签发者的VC的吊销记录，VC未被吊销时返回nil
+// @params issuer：VC的签发者DID
+// @params vcId：VC编号
+// @params client：长安链客户端
+func GetVcRevocationFromChain(issuer, vcId string, client invoke.ChainClient) (*model.VcRevocation, error) {
+	return GetVcRevocationFromChainCtx(context.Background(), issuer, vcId, client)
+}
+
+// GetVcRevocationFromChainCtx 同GetVcRevocationFromChain，可以通过ctx设置超时时间或取消调用
+func GetVcRevocationFromChainCtx(ctx context.Context, issuer, vcId string,
+	client invoke.ChainClient) (*model.VcRevocation, error) {
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetVcRevocation,
+		vcRecordParams(issuer, vcId), client)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *model.VcRevocation
+
+	err = json.Unmarshal(resp, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// SuspendVCOnChain 在链上暂停VC，暂停期间VC验证不通过，可以通过UnsuspendVCOnChain恢复
+// @params issuer：VC的签发者DID，签发者暂停自己签发的VC时可以为空
+// @params vcId：要暂停的VC编号
+// @params reason：原因描述
+// @params client：长安链客户端
+func SuspendVCOnChain(issuer, vcId, reason string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return SuspendVCOnChainCtx(context.Background(), issuer, vcId, reason, client)
+}
+
+// SuspendVCOnChainCtx 同SuspendVCOnChain，可以通过ctx设置超时时间或取消调用
+func SuspendVCOnChainCtx(ctx context.Context, issuer, vcId, reason string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_SuspendVc,
+		suspendVcParams(issuer, vcId, reason), client)
+}
+
+// UnsuspendVCOnChain 在链上恢复被暂停的VC
+// @params issuer：VC的签发者DID，签发者恢复自己签发的VC时可以为空
+// @params vcId：要恢复的VC编号
+// @params reason：原因描述
+// @params client：长安链客户端
+func UnsuspendVCOnChain(issuer, vcId, reason string, client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return UnsuspendVCOnChainCtx(context.Background(), issuer, vcId, reason, client)
+}
+
+// UnsuspendVCOnChainCtx 同UnsuspendVCOnChain，可以通过ctx设置超时时间或取消调用
+func UnsuspendVCOnChainCtx(ctx context.Context, issuer, vcId, reason string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_UnsuspendVc,
+		suspendVcParams(issuer, vcId, reason), client)
+}
+
+// suspendVcParams 生成暂停或恢复VC的合约调用参数
+func suspendVcParams(issuer, vcId, reason string) []*common.KeyValuePair {
+	params := vcRecordParams(issuer, vcId)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_Reason,
+		Value: []byte(reason),
+	})
+
+	return params
+}
+
+// GetVcSuspensionFromChain 从链上获取签发者的VC的暂停记录，VC未被暂停时返回nil
+// @params issuer：VC的签发者DID
+// @params vcId：VC编号
+// @params client：长安链客户端
+func GetVcSuspensionFromChain(issuer, vcId string, client invoke.ChainClient) (*model.VcSuspension, error) {
+	return GetVcSuspensionFromChainCtx(context.Background(), issuer, vcId, client)
+}
+
+// GetVcSuspensionFromChainCtx 同GetVcSuspensionFromChain，可以通过ctx设置超时时间或取消调用
+func GetVcSuspensionFromChainCtx(ctx context.Context, issuer, vcId string,
+	client invoke.ChainClient) (*model.VcSuspension, error) {
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetVcSuspension,
+		vcRecordParams(issuer, vcId), client)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *model.VcSuspension
+
+	err = json.Unmarshal(resp, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
 // AddVcIssueLogToChain 在链上添加VC签发日志
 // @params issuer：签发者DID（需要在链上被认可）
 // @params did：被签发者did
@@ -307,7 +557,26 @@ func GetVCRevokedListFromChain(vcIdSearch string, start int, count int, client *
 // @params vcTemplateId：模板编号
 // @params client：长安链客户端
 func AddVcIssueLogToChain(issuer, did, vcId, vcTemplateId string,
-	client *cmsdk.ChainClient) error {
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return AddVcIssueLogToChainCtx(context.Background(), issuer, did, vcId, vcTemplateId, client)
+}
+
+// AddVcIssueLogToChainCtx 同AddVcIssueLogToChain，可以通过ctx设置超时时间或取消调用
+func AddVcIssueLogToChainCtx(ctx context.Context, issuer, did, vcId, vcTemplateId string,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	params := vcIssueLogParams(issuer, did, vcId, vcTemplateId)
+
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_VcIssueLog,
+		params, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// vcIssueLogParams 生成VC签发日志上链的合约调用参数
+func vcIssueLogParams(issuer, did, vcId, vcTemplateId string) []*common.KeyValuePair {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -330,12 +599,7 @@ func AddVcIssueLogToChain(issuer, did, vcId, vcTemplateId string,
 		Value: []byte(vcTemplateId),
 	})
 
-	_, err := invoke.InvokeContract(invoke.DIDContractName, model.Method_VcIssueLog, params, client)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return params
 }
 
 // GetVcIssueLogListFromChain 从链上获取VC签发日志列表
@@ -344,7 +608,13 @@ func AddVcIssueLogToChain(issuer, did, vcId, vcTemplateId string,
 // @params count：要获取的数量，0表示获取所有
 // @params client：长安链客户端
 func GetVcIssueLogListFromChain(vcIdSearch string, start int, count int,
-	client *cmsdk.ChainClient) ([]*model.VcIssueLog, error) {
+	client invoke.ChainClient) ([]*model.VcIssueLog, error) {
+	return GetVcIssueLogListFromChainCtx(context.Background(), vcIdSearch, start, count, client)
+}
+
+// GetVcIssueLogListFromChainCtx 同GetVcIssueLogListFromChain，可以通过ctx设置超时时间或取消调用
+func GetVcIssueLogListFromChainCtx(ctx context.Context, vcIdSearch string, start int, count int,
+	client invoke.ChainClient) ([]*model.VcIssueLog, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -362,7 +632,8 @@ func GetVcIssueLogListFromChain(vcIdSearch string, start int, count int,
 		Value: []byte(strconv.Itoa(count)),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetVcIssueLogs, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetVcIssueLogs,
+		params, client)
 	if err != nil {
 		return nil, err
 	}
@@ -8,13 +8,13 @@ SPDX-License-Identifier: Apache-2.0
 package vc
 
 import (
+	"context"
 	"did-sdk/invoke"
 	"encoding/json"
 	"strconv"
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 )
 
 // SimpleVcTemplate 简易的JSON Schema的VC模板
@@ -77,7 +77,14 @@ func GenerateSimpleVcTemplate(fieldsMap map[string]string) ([]byte, error) {
 // @params version：模板版本
 // @params template：模板内容，需要JSON schema格式
 // @params client：长安链客户端
-func AddVcTemplateToChain(id string, name string, version string, template []byte, client *cmsdk.ChainClient) error {
+func AddVcTemplateToChain(id string, name string, version string, template []byte,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
+	return AddVcTemplateToChainCtx(context.Background(), id, name, version, template, client)
+}
+
+// AddVcTemplateToChainCtx 同AddVcTemplateToChain，可以通过ctx设置超时时间或取消调用
+func AddVcTemplateToChainCtx(ctx context.Context, id string, name string, version string, template []byte,
+	client invoke.ChainClient) (*invoke.TxReceipt, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -100,18 +107,24 @@ func AddVcTemplateToChain(id string, name string, version string, template []byt
 		Value: json.RawMessage(template),
 	})
 
-	_, err := invoke.InvokeContract(invoke.DIDContractName, model.Method_SetVcTemplate, params, client)
+	receipt, err := invoke.InvokeContractWithReceiptCtx(ctx, invoke.ContractNameOf(client), model.Method_SetVcTemplate,
+		params, client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // GetVcTemplateFromChain 从链上获取VC模板
 // @params id：模板ID
 // @params client：长安链客户端
-func GetVcTemplateFromChain(id string, client *cmsdk.ChainClient) ([]byte, error) {
+func GetVcTemplateFromChain(id string, client invoke.ChainClient) ([]byte, error) {
+	return GetVcTemplateFromChainCtx(context.Background(), id, client)
+}
+
+// GetVcTemplateFromChainCtx 同GetVcTemplateFromChain，可以通过ctx设置超时时间或取消调用
+func GetVcTemplateFromChainCtx(ctx context.Context, id string, client invoke.ChainClient) ([]byte, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -119,7 +132,7 @@ func GetVcTemplateFromChain(id string, client *cmsdk.ChainClient) ([]byte, error
 		Value: []byte(id),
 	})
 
-	return invoke.QueryContract(invoke.DIDContractName, model.Method_GetVcTemplate, params, client)
+	return invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetVcTemplate, params, client)
 }
 
 // GetVcTemplateListFromChain 从链上获取VC模板列表
@@ -128,7 +141,13 @@ func GetVcTemplateFromChain(id string, client *cmsdk.ChainClient) ([]byte, error
 // @params count：要获取的数量，0表示获取所有
 // @params client：长安链客户端
 func GetVcTemplateListFromChain(nameSearch string, start int, count int,
-	client *cmsdk.ChainClient) ([]*model.VcTemplate, error) {
+	client invoke.ChainClient) ([]*model.VcTemplate, error) {
+	return GetVcTemplateListFromChainCtx(context.Background(), nameSearch, start, count, client)
+}
+
+// GetVcTemplateListFromChainCtx 同GetVcTemplateListFromChain，可以通过ctx设置超时时间或取消调用
+func GetVcTemplateListFromChainCtx(ctx context.Context, nameSearch string, start int, count int,
+	client invoke.ChainClient) ([]*model.VcTemplate, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -146,7 +165,8 @@ func GetVcTemplateListFromChain(nameSearch string, start int, count int,
 		Value: []byte(strconv.Itoa(count)),
 	})
 
-	resp, err := invoke.QueryContract(invoke.DIDContractName, model.Method_GetVcTemplateList, params, client)
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_GetVcTemplateList,
+		params, client)
 	if err != nil {
 		return nil, err
 	}
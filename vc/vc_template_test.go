@@ -43,7 +43,7 @@ func TestAddVcTemplateToChain(t *testing.T) {
 	jsonSchema, err := GenerateSimpleVcTemplate(fieldsMap)
 	require.Nil(t, err)
 
-	err = AddVcTemplateToChain("1231323", "模板1", "version", jsonSchema, c)
+	_, err = AddVcTemplateToChain("1231323", "模板1", "version", jsonSchema, c)
 	require.Nil(t, err)
 }
 
@@ -59,7 +59,7 @@ func TestGetVcTemplateFromChain(t *testing.T) {
 	jsonSchema, err := GenerateSimpleVcTemplate(fieldsMap)
 	require.Nil(t, err)
 
-	err = AddVcTemplateToChain("3213", "模板1", "version", jsonSchema, c)
+	_, err = AddVcTemplateToChain("3213", "模板1", "version", jsonSchema, c)
 	require.Nil(t, err)
 
 	v, err := GetVcTemplateFromChain("3213", c)
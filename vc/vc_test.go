@@ -72,7 +72,7 @@ func TestIssueVC(t *testing.T) {
 	doc, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
 	require.Nil(t, err)
 
-	err = did.AddDidDocToChain(string(doc), c)
+	_, err = did.AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -80,7 +80,7 @@ func TestIssueVC(t *testing.T) {
 	err = json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = did.AddTrustIssuerListToChain([]string{document.Id}, c)
+	_, err = did.AddTrustIssuerListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	fieldsMap := make(map[string]string)
@@ -92,7 +92,7 @@ func TestIssueVC(t *testing.T) {
 	jsonSchema, err := GenerateSimpleVcTemplate(fieldsMap)
 	require.Nil(t, err)
 
-	err = AddVcTemplateToChain("abc12312", "模板1", "1.0.0", jsonSchema, c)
+	_, err = AddVcTemplateToChain("abc12312", "模板1", "1.0.0", jsonSchema, c)
 	require.Nil(t, err)
 
 	// 被签发者上链
@@ -102,7 +102,7 @@ func TestIssueVC(t *testing.T) {
 	doc2, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo2}, c)
 	require.Nil(t, err)
 
-	err = did.AddDidDocToChain(string(doc2), c)
+	_, err = did.AddDidDocToChain(string(doc2), c)
 	require.Nil(t, err)
 
 	var doc2Struct model.DidDocument
@@ -118,7 +118,7 @@ func TestIssueVC(t *testing.T) {
 
 	e := time.Now().Add(time.Hour * 24 * 365).Unix()
 
-	vcBytes, err := IssueVC(keyInfo.SkPEM, keyInfo.PkPEM, 0, sub, c, "vc_1111", e, "abc12312")
+	vcBytes, _, err := IssueVC(keyInfo.SkPEM, keyInfo.PkPEM, 0, sub, c, "vc_1111", e, "abc12312")
 	require.Nil(t, err)
 	fmt.Println(string(vcBytes))
 
@@ -134,13 +134,13 @@ func TestRevokeVCOnChain(t *testing.T) {
 	c, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
 	require.Nil(t, err)
 
-	err = RevokeVCOnChain("vc_1111", c)
+	_, err = RevokeVCOnChain("vc_1111", c)
 	require.Nil(t, err)
 
 	c2, err := testdata.GetChainmakerClient(testdata.ConfigPath2)
 	require.Nil(t, err)
 
-	err = RevokeVCOnChain("vc_1111", c2)
+	_, err = RevokeVCOnChain("vc_1111", c2)
 	require.NotNil(t, err)
 }
 
@@ -148,12 +148,13 @@ func TestGetVCRevokedListFromChain(t *testing.T) {
 	c, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
 	require.Nil(t, err)
 
-	err = RevokeVCOnChain("vc_1111", c)
+	_, err = RevokeVCOnChain("vc_1111", c)
 	require.Nil(t, err)
 
 	list, err := GetVCRevokedListFromChain("", 0, 0, c)
 	require.Nil(t, err)
-	require.Equal(t, []string{"vc_1111"}, list)
+	require.Len(t, list, 1)
+	require.Equal(t, "vc_1111", list[0].VcId)
 }
 
 func TestVerifyVCOnChain(t *testing.T) {
@@ -167,7 +168,7 @@ func TestVerifyVCOnChain(t *testing.T) {
 	require.Nil(t, err)
 	fmt.Println(string(doc))
 
-	err = did.AddDidDocToChain(string(doc), c)
+	_, err = did.AddDidDocToChain(string(doc), c)
 	require.Nil(t, err)
 
 	var document model.DidDocument
@@ -175,7 +176,7 @@ func TestVerifyVCOnChain(t *testing.T) {
 	json.Unmarshal(doc, &document)
 	require.Nil(t, err)
 
-	err = did.AddTrustIssuerListToChain([]string{document.Id}, c)
+	_, err = did.AddTrustIssuerListToChain([]string{document.Id}, c)
 	require.Nil(t, err)
 
 	// 被签发者上链
@@ -185,7 +186,7 @@ func TestVerifyVCOnChain(t *testing.T) {
 	doc2, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo2}, c)
 	require.Nil(t, err)
 
-	err = did.AddDidDocToChain(string(doc2), c)
+	_, err = did.AddDidDocToChain(string(doc2), c)
 	require.Nil(t, err)
 
 	var doc2Struct model.DidDocument
@@ -202,7 +203,7 @@ func TestVerifyVCOnChain(t *testing.T) {
 	jsonSchema, err := GenerateSimpleVcTemplate(fieldsMap)
 	require.Nil(t, err)
 
-	err = AddVcTemplateToChain("abc12345", "模板1", "1.0.0", jsonSchema, c)
+	_, err = AddVcTemplateToChain("abc12345", "模板1", "1.0.0", jsonSchema, c)
 	require.Nil(t, err)
 
 	sub := make(map[string]interface{})
@@ -213,7 +214,7 @@ func TestVerifyVCOnChain(t *testing.T) {
 
 	e := time.Now().Add(time.Hour * 24 * 365).Unix()
 
-	vcBytes, err := IssueVC(keyInfo.SkPEM, keyInfo.PkPEM, 0, sub, c, "vc_2222", e, "abc12345")
+	vcBytes, _, err := IssueVC(keyInfo.SkPEM, keyInfo.PkPEM, 0, sub, c, "vc_2222", e, "abc12345")
 	require.Nil(t, err)
 	fmt.Println(string(vcBytes))
 
@@ -221,3 +222,77 @@ func TestVerifyVCOnChain(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, true, ok)
 }
+
+func TestIssueVCWithScopedTrustIssuer(t *testing.T) {
+	c, err := testdata.GetChainmakerClient(testdata.ConfigPath1)
+	require.Nil(t, err)
+
+	fieldsMap := make(map[string]string)
+
+	fieldsMap["name"] = "姓名7"
+	fieldsMap["degree"] = "学位7"
+
+	jsonSchema, err := GenerateSimpleVcTemplate(fieldsMap)
+	require.Nil(t, err)
+
+	_, err = AddVcTemplateToChain("diploma001", "学位证书", "1.0.0", jsonSchema, c)
+	require.Nil(t, err)
+
+	_, err = AddVcTemplateToChain("license001", "驾驶证", "1.0.0", jsonSchema, c)
+	require.Nil(t, err)
+
+	// 仅可签发学位证书的签发者上链
+	keyInfo, err := key.GenerateKey("EC_Secp256k1")
+	require.Nil(t, err)
+
+	doc, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo}, c)
+	require.Nil(t, err)
+
+	_, err = did.AddDidDocToChain(string(doc), c)
+	require.Nil(t, err)
+
+	var document model.DidDocument
+
+	err = json.Unmarshal(doc, &document)
+	require.Nil(t, err)
+
+	_, err = did.AddTrustIssuerListToChain([]string{document.Id}, c, "diploma001")
+	require.Nil(t, err)
+
+	issuer, err := did.GetTrustIssuerFromChain(document.Id, c)
+	require.Nil(t, err)
+	require.Equal(t, []string{"diploma001"}, issuer.TemplateIds)
+
+	// 被签发者上链
+	keyInfo2, err := key.GenerateKey("EC_Secp256k1")
+	require.Nil(t, err)
+
+	doc2, err := did.GenerateDidDoc([]*key.KeyInfo{keyInfo2}, c)
+	require.Nil(t, err)
+
+	_, err = did.AddDidDocToChain(string(doc2), c)
+	require.Nil(t, err)
+
+	var doc2Struct model.DidDocument
+
+	err = json.Unmarshal(doc2, &doc2Struct)
+	require.Nil(t, err)
+
+	sub := make(map[string]interface{})
+	sub["id"] = doc2Struct.Id
+	sub["name"] = "XiaoMing"
+	sub["degree"] = "Bachelor"
+
+	e := time.Now().Add(time.Hour * 24 * 365).Unix()
+
+	vcBytes, _, err := IssueVC(keyInfo.SkPEM, keyInfo.PkPEM, 0, sub, c, "vc_diploma_001", e, "diploma001")
+	require.Nil(t, err)
+
+	ok, err := VerifyVCOnChain(string(vcBytes), c)
+	require.Nil(t, err)
+	require.Equal(t, true, ok)
+
+	// 超出模板范围的签发会被拒绝
+	_, _, err = IssueVC(keyInfo.SkPEM, keyInfo.PkPEM, 0, sub, c, "vc_license_001", e, "license001")
+	require.NotNil(t, err)
+}
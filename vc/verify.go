@@ -0,0 +1,369 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vc
+
+import (
+	"context"
+	"did-sdk/admin"
+	"did-sdk/did"
+	"did-sdk/invoke"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// DidResolver 解析DID文档
+type DidResolver interface {
+	// GetDidDocument 获取DID文档，不存在时返回nil或满足errors.Is(err, invoke.ErrDidNotFound)的错误
+	GetDidDocument(ctx context.Context, did string) ([]byte, error)
+}
+
+// TemplateSource 获取VC模板
+type TemplateSource interface {
+	// GetVcTemplate 获取VC模板，不存在时返回nil或满足errors.Is(err, invoke.ErrVcTemplateNotFound)的错误
+	GetVcTemplate(ctx context.Context, id string) ([]byte, error)
+}
+
+// StatusSource 获取VC的吊销、暂停状态和DID的黑名单状态
+type StatusSource interface {
+	// GetVcRevocation 获取签发者的VC的吊销记录，VC未被吊销时返回nil
+	GetVcRevocation(ctx context.Context, issuer, vcId string) (*model.VcRevocation, error)
+	// GetVcSuspension 获取签发者的VC的暂停记录，VC未被暂停时返回nil
+	GetVcSuspension(ctx context.Context, issuer, vcId string) (*model.VcSuspension, error)
+	// IsInBlackList DID在now时是否在黑名单中，now为验证使用的当前时间，用于判断黑名单记录是否过期
+	IsInBlackList(ctx context.Context, did string, now time.Time) (bool, error)
+}
+
+// TrustSource 获取可信签发者数据
+type TrustSource interface {
+	// IsTrustIssuerEnabled 是否开启了可信签发者检查，未开启时任何签发者都可以签发VC
+	IsTrustIssuerEnabled(ctx context.Context) (bool, error)
+	// GetAccreditationChain 获取签发者的认证链，第一个为签发者本身，最后一个为管理员直接添加的签发者
+	// 签发者不是可信签发者时返回错误码为ErrCode_NotTrustedIssuer的错误
+	GetAccreditationChain(ctx context.Context, did string) ([]*model.TrustIssuer, error)
+}
+
+// StatusListSource 获取VC的credentialStatus指向的状态列表凭证
+type StatusListSource interface {
+	// GetStatusListCredential 获取状态列表凭证，不存在时返回nil或满足errors.Is(err, invoke.ErrStatusListNotFound)的错误
+	GetStatusListCredential(ctx context.Context, id string) ([]byte, error)
+}
+
+// LocalVerifyOptions 本地验证VC和VP时使用的数据源和时钟
+// ChainSource、Snapshot和cache.Cache实现了全部数据源
+type LocalVerifyOptions struct {
+	Resolver  DidResolver
+	Templates TemplateSource
+	Status    StatusSource
+	// Trust 可信签发者数据，不需要检查签发者是否可信时使用EnableTrustIssuer为false的Snapshot
+	Trust TrustSource
+	// StatusLists 状态列表凭证，为nil时带credentialStatus的VC因找不到状态列表而验证失败
+	StatusLists StatusListSource
+	// Now 当前时间，为nil时使用time.Now
+	Now func() time.Time
+}
+
+// NewVerifySource 根据本地验证的数据源生成model.VerifySource，用于model.VerifyVc和model.VerifyVp
+// @params ctx：调用数据源时使用的上下文
+// @params opts：数据源
+func NewVerifySource(ctx context.Context, opts LocalVerifyOptions) (model.VerifySource, error) {
+	if opts.Resolver == nil || opts.Templates == nil || opts.Status == nil || opts.Trust == nil {
+		return nil, errors.New("the resolver, templates, status and trust of the options are required")
+	}
+
+	return &localSource{ctx: ctx, opts: opts}, nil
+}
+
+// VerifyVCLocal 在本地验证VC并返回验证报告，检查项与合约的VerifyVc相同，不需要发送链上查询
+// 报告未通过时可以通过report.Err()获取第一个未通过的检查项对应的错误
+// @params ctx：调用数据源时使用的上下文
+// @params vcJson：VC的json字符串
+// @params opts：数据源和时钟
+func VerifyVCLocal(ctx context.Context, vcJson string, opts LocalVerifyOptions) (*model.VerifyReport, error) {
+	src, err := NewVerifySource(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	vc, err := model.NewVerifiableCredential(vcJson)
+	if err != nil {
+		report := model.NewVerifyReport("")
+		report.Fail(model.VerifyCheck_Format,
+			model.NewError(model.ErrCode_InvalidCredential, "invalid vc: [%s]", err.Error()))
+		return report, nil
+	}
+
+	return model.VerifyVc(vc, opts.CurrentTime().Unix(), src)
+}
+
+// CurrentTime 返回验证使用的当前时间
+func (opts LocalVerifyOptions) CurrentTime() time.Time {
+	if opts.Now == nil {
+		return time.Now()
+	}
+	return opts.Now()
+}
+
+// localSource 将本地验证的数据源适配为model.VerifySource
+type localSource struct {
+	ctx  context.Context
+	opts LocalVerifyOptions
+}
+
+func (s *localSource) GetDidDocument(did string) ([]byte, error) {
+	doc, err := s.opts.Resolver.GetDidDocument(s.ctx, did)
+	if errors.Is(err, model.ErrDidNotFound) {
+		return nil, nil
+	}
+	return doc, err
+}
+
+func (s *localSource) GetVcTemplate(id string) ([]byte, error) {
+	template, err := s.opts.Templates.GetVcTemplate(s.ctx, id)
+	if errors.Is(err, model.ErrVcTemplateNotFound) {
+		return nil, nil
+	}
+	return template, err
+}
+
+func (s *localSource) GetVcRevocation(issuer, vcId string) (*model.VcRevocation, error) {
+	return s.opts.Status.GetVcRevocation(s.ctx, issuer, vcId)
+}
+
+func (s *localSource) GetVcSuspension(issuer, vcId string) (*model.VcSuspension, error) {
+	return s.opts.Status.GetVcSuspension(s.ctx, issuer, vcId)
+}
+
+func (s *localSource) IsInBlackList(did string) (bool, error) {
+	return s.opts.Status.IsInBlackList(s.ctx, did, s.opts.CurrentTime())
+}
+
+func (s *localSource) GetStatusListCredential(id string) ([]byte, error) {
+	if s.opts.StatusLists == nil {
+		return nil, nil
+	}
+
+	list, err := s.opts.StatusLists.GetStatusListCredential(s.ctx, id)
+	if errors.Is(err, model.ErrStatusListNotFound) {
+		return nil, nil
+	}
+	return list, err
+}
+
+// VerifyTrustIssuer 与合约的isTrustIssuer相同：认证链上每一级都有效且都可以签发该模板
+func (s *localSource) VerifyTrustIssuer(did, templateId string) error {
+	enable, err := s.opts.Trust.IsTrustIssuerEnabled(s.ctx)
+	if err != nil {
+		return err
+	}
+	if !enable {
+		return nil
+	}
+
+	chain, err := s.opts.Trust.GetAccreditationChain(s.ctx, did)
+	if err != nil {
+		return notTrustedIssuer(err)
+	}
+
+	err = model.VerifyAccreditationChain(chain, s.IsInBlackList)
+	if err != nil {
+		return notTrustedIssuer(err)
+	}
+
+	if !model.IsChainTrustedFor(chain, templateId) {
+		return model.NewError(model.ErrCode_NotTrustedIssuer,
+			"the issuer of VC is not a trusted issuer of the VC template on the chain")
+	}
+
+	return nil
+}
+
+// notTrustedIssuer 带错误码的错误表示签发者不可信，其他错误表示数据获取失败，原样返回
+func notTrustedIssuer(err error) error {
+	if model.CodeOf(err) == model.ErrCode_Unknown {
+		return err
+	}
+
+	return model.NewError(model.ErrCode_NotTrustedIssuer,
+		"the issuer of VC is not a trusted issuer of the VC template on the chain, err: [%s]", err.Error())
+}
+
+// ChainSource 通过链上查询实现DidResolver、TemplateSource、StatusSource、TrustSource和StatusListSource
+type ChainSource struct {
+	client invoke.ChainClient
+}
+
+// NewChainSource 新建通过链上查询获取验证数据的数据源
+// @params client：长安链客户端
+func NewChainSource(client invoke.ChainClient) *ChainSource {
+	return &ChainSource{client: client}
+}
+
+// Options 返回使用该数据源的本地验证选项
+func (c *ChainSource) Options() LocalVerifyOptions {
+	return LocalVerifyOptions{Resolver: c, Templates: c, Status: c, Trust: c, StatusLists: c}
+}
+
+// GetDidDocument 从链上获取DID文档
+func (c *ChainSource) GetDidDocument(ctx context.Context, didStr string) ([]byte, error) {
+	return did.GetDidDocFromChainCtx(ctx, didStr, c.client)
+}
+
+// GetVcTemplate 从链上获取VC模板
+func (c *ChainSource) GetVcTemplate(ctx context.Context, id string) ([]byte, error) {
+	return GetVcTemplateFromChainCtx(ctx, id, c.client)
+}
+
+// GetVcRevocation 从链上获取签发者的VC的吊销记录
+func (c *ChainSource) GetVcRevocation(ctx context.Context, issuer, vcId string) (*model.VcRevocation, error) {
+	return GetVcRevocationFromChainCtx(ctx, issuer, vcId, c.client)
+}
+
+// GetVcSuspension 从链上获取签发者的VC的暂停记录
+func (c *ChainSource) GetVcSuspension(ctx context.Context, issuer, vcId string) (*model.VcSuspension, error) {
+	return GetVcSuspensionFromChainCtx(ctx, issuer, vcId, c.client)
+}
+
+// IsInBlackList 从链上获取DID是否在黑名单中
+func (c *ChainSource) IsInBlackList(ctx context.Context, didStr string, now time.Time) (bool, error) {
+	list, err := did.GetDidBlackListFromChainCtx(ctx, didStr, 0, 1, c.client)
+	if err != nil {
+		return false, err
+	}
+
+	return len(list) != 0 && list[0].Did == didStr && !list[0].IsExpired(now.Unix()), nil
+}
+
+// IsTrustIssuerEnabled 从链上获取DID合约是否开启了可信签发者检查
+func (c *ChainSource) IsTrustIssuerEnabled(ctx context.Context) (bool, error) {
+	config, err := admin.GetContractConfigOfDidContractCtx(ctx, c.client)
+	if err != nil {
+		return false, err
+	}
+
+	return config.EnableTrustIssuer, nil
+}
+
+// GetAccreditationChain 从链上获取签发者的认证链
+func (c *ChainSource) GetAccreditationChain(ctx context.Context, didStr string) ([]*model.TrustIssuer, error) {
+	return did.GetIssuerAccreditationChainFromChainCtx(ctx, didStr, c.client)
+}
+
+// GetStatusListCredential 从链上获取状态列表凭证
+func (c *ChainSource) GetStatusListCredential(ctx context.Context, id string) ([]byte, error) {
+	return GetStatusListFromChainCtx(ctx, id, c.client)
+}
+
+// Snapshot 验证VC和VP需要的链上数据的快照，用于无法连接链的环境
+// 快照可以保存为json文件，通过LoadSnapshot加载
+type Snapshot struct {
+	// DidDocuments DID到DID文档的映射
+	DidDocuments map[string]json.RawMessage `json:"didDocuments,omitempty"`
+	// VcTemplates 模板ID到VC模板的映射，模板为GetVcTemplateFromChain返回的内容
+	VcTemplates map[string]json.RawMessage `json:"vcTemplates,omitempty"`
+	// RevokedVcs VC吊销记录，签发者为空的记录对所有签发者的同名VC生效
+	RevokedVcs []*model.VcRevocation `json:"revokedVcs,omitempty"`
+	// SuspendedVcs 被暂停的VC的暂停记录
+	SuspendedVcs []*model.VcSuspension `json:"suspendedVcs,omitempty"`
+	// BlackList DID黑名单记录，过期时间按验证使用的当前时间判断
+	BlackList []*model.BlackListRecord `json:"blackList,omitempty"`
+	// EnableTrustIssuer 是否开启了可信签发者检查
+	EnableTrustIssuer bool `json:"enableTrustIssuer"`
+	// TrustIssuers 可信签发者列表，需要包含认证链上的所有上级签发者
+	TrustIssuers []*model.TrustIssuer `json:"trustIssuers,omitempty"`
+	// StatusLists 状态列表凭证ID到状态列表凭证的映射
+	StatusLists map[string]json.RawMessage `json:"statusLists,omitempty"`
+}
+
+// LoadSnapshot 从json文件加载验证数据快照
+// @params path：快照文件路径
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: [%s]", err.Error())
+	}
+
+	return &s, nil
+}
+
+// Options 返回使用该快照的本地验证选项
+func (s *Snapshot) Options() LocalVerifyOptions {
+	return LocalVerifyOptions{Resolver: s, Templates: s, Status: s, Trust: s, StatusLists: s}
+}
+
+// GetDidDocument 从快照获取DID文档
+func (s *Snapshot) GetDidDocument(_ context.Context, did string) ([]byte, error) {
+	return s.DidDocuments[did], nil
+}
+
+// GetVcTemplate 从快照获取VC模板
+func (s *Snapshot) GetVcTemplate(_ context.Context, id string) ([]byte, error) {
+	return s.VcTemplates[id], nil
+}
+
+// GetVcRevocation 从快照获取签发者的VC的吊销记录
+func (s *Snapshot) GetVcRevocation(_ context.Context, issuer, vcId string) (*model.VcRevocation, error) {
+	for _, r := range s.RevokedVcs {
+		if r.Matches(issuer, vcId) {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetVcSuspension 从快照获取签发者的VC的暂停记录
+func (s *Snapshot) GetVcSuspension(_ context.Context, issuer, vcId string) (*model.VcSuspension, error) {
+	for _, r := range s.SuspendedVcs {
+		if r.Issuer == issuer && r.VcId == vcId {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// IsInBlackList 从快照获取DID是否在黑名单中
+func (s *Snapshot) IsInBlackList(_ context.Context, did string, now time.Time) (bool, error) {
+	for _, r := range s.BlackList {
+		if r.Did == did && !r.IsExpired(now.Unix()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsTrustIssuerEnabled 从快照获取是否开启了可信签发者检查
+func (s *Snapshot) IsTrustIssuerEnabled(_ context.Context) (bool, error) {
+	return s.EnableTrustIssuer, nil
+}
+
+// GetAccreditationChain 根据快照中的可信签发者列表生成签发者的认证链
+func (s *Snapshot) GetAccreditationChain(_ context.Context, did string) ([]*model.TrustIssuer, error) {
+	return model.BuildAccreditationChain(did, func(d string) (*model.TrustIssuer, error) {
+		for _, issuer := range s.TrustIssuers {
+			if issuer.Did == d {
+				return issuer, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// GetStatusListCredential 从快照获取状态列表凭证
+func (s *Snapshot) GetStatusListCredential(_ context.Context, id string) ([]byte, error) {
+	return s.StatusLists[id], nil
+}
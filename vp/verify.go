@@ -0,0 +1,50 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) DCPS. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vp
+
+import (
+	"context"
+	"did-sdk/vc"
+
+	"chainmaker.org/chainmaker/did-contract/model"
+)
+
+// VerifyVPLocal 在本地验证VP并返回验证报告，检查项与合约的VerifyVp相同，VP中每个VC的验证报告在Credentials中
+// challenge或domain不为空时增加challenge检查项，检查持有者的证明绑定了验证者提供的挑战和域
+// 数据源可以使用vc.NewChainSource、vc.LoadSnapshot加载的快照或cache.Cache，见vc.LocalVerifyOptions
+// @params ctx：调用数据源时使用的上下文
+// @params vpJson：VP的json字符串
+// @params challenge：验证者提供的挑战，为空时不检查
+// @params domain：验证者的域，为空时不检查
+// @params opts：数据源和时钟
+func VerifyVPLocal(ctx context.Context, vpJson string, challenge, domain string,
+	opts vc.LocalVerifyOptions) (*model.VerifyReport, error) {
+	src, err := vc.NewVerifySource(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	vp, err := model.NewVerifiablePresentation(vpJson)
+	if err != nil {
+		report := model.NewVerifyReport("")
+		report.Fail(model.VerifyCheck_Format,
+			model.NewError(model.ErrCode_InvalidCredential, "invalid vp: [%s]", err.Error()))
+		return report, nil
+	}
+
+	report, err := model.VerifyVp(vp, opts.CurrentTime().Unix(), src)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(challenge) != 0 || len(domain) != 0 {
+		report.Check(model.VerifyCheck_Challenge, vp.VerifyChallenge(challenge, domain))
+	}
+
+	return report, nil
+}
@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package vp
 
 import (
+	"context"
 	"did-sdk/did"
 	"did-sdk/invoke"
 	"did-sdk/proof"
@@ -17,7 +18,6 @@ import (
 
 	"chainmaker.org/chainmaker/did-contract/model"
 	"chainmaker.org/chainmaker/pb-go/v2/common"
-	cmsdk "chainmaker.org/chainmaker/sdk-go/v2"
 )
 
 var ContextVP = []string{
@@ -33,6 +33,28 @@ var ContextVP = []string{
 // @params vpType：VP中的`type`字段，描述VP的类型信息（可变参数，默认会填写“VerifiablePresentation”,可继续根据业务类型追加）
 func GenerateVP(skPem []byte, keyIndex int, holder string,
 	vpId string, vcList []string, vpType ...string) ([]byte, error) {
+	return GenerateVPWithOptions(skPem, keyIndex, holder, vpId, vcList, VpOptions{}, vpType...)
+}
+
+// VpOptions 生成VP的可选项
+type VpOptions struct {
+	// Challenge 验证者提供的挑战，防止VP被重放
+	Challenge string
+	// Domain 验证者的域，限制VP只能提交给该验证者
+	Domain string
+	// Expiration VP的过期时间，Unix时间戳，0表示不过期
+	Expiration int64
+}
+
+// GenerateVPWithOptions 同GenerateVP，可以设置VP的挑战、域和过期时间，挑战和域会包含在签名原文中
+// @params skPem: 私钥的PEM编码
+// @params keyIndex：公钥在DID文档中的索引
+// @params vpId：VP的`id`字段，可以根据业务自定义
+// @params VP中包含的VC列表
+// @params opts：挑战、域和过期时间
+// @params vpType：VP中的`type`字段
+func GenerateVPWithOptions(skPem []byte, keyIndex int, holder string,
+	vpId string, vcList []string, opts VpOptions, vpType ...string) ([]byte, error) {
 
 	var verifiablePresentation model.VerifiablePresentation
 
@@ -52,13 +74,16 @@ func GenerateVP(skPem []byte, keyIndex int, holder string,
 	verifiablePresentation.Id = vpId
 	verifiablePresentation.Type = vpType
 	verifiablePresentation.Holder = holder
+	if opts.Expiration != 0 {
+		verifiablePresentation.ExpirationDate = utils.ISO8601Time(opts.Expiration)
+	}
 
 	vpBytes, err := json.Marshal(verifiablePresentation)
 	if err != nil {
 		return nil, err
 	}
 
-	msg, err := utils.CompactJson(vpBytes)
+	msg, err := model.PresentationSigningMessage(vpBytes, opts.Challenge, opts.Domain)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +95,8 @@ func GenerateVP(skPem []byte, keyIndex int, holder string,
 		return nil, err
 	}
 
+	pf.Challenge = opts.Challenge
+	pf.Domain = opts.Domain
 	verifiablePresentation.Proof = pf
 
 	return json.Marshal(verifiablePresentation)
@@ -79,7 +106,12 @@ func GenerateVP(skPem []byte, keyIndex int, holder string,
 // VerifyVPOnChain 在链上验证VP的有效性
 // @params vc: VP的JSON字符串
 // @params client：长安链客户端
-func VerifyVPOnChain(vp string, client *cmsdk.ChainClient) (bool, error) {
+func VerifyVPOnChain(vp string, client invoke.ChainClient) (bool, error) {
+	return VerifyVPOnChainCtx(context.Background(), vp, client)
+}
+
+// VerifyVPOnChainCtx 同VerifyVPOnChain，可以通过ctx设置超时时间或取消调用
+func VerifyVPOnChainCtx(ctx context.Context, vp string, client invoke.ChainClient) (bool, error) {
 	params := make([]*common.KeyValuePair, 0)
 
 	params = append(params, &common.KeyValuePair{
@@ -87,10 +119,42 @@ func VerifyVPOnChain(vp string, client *cmsdk.ChainClient) (bool, error) {
 		Value: []byte(vp),
 	})
 
-	_, err := invoke.QueryContract(invoke.DIDContractName, model.Method_VerifyVp, params, client)
+	_, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_VerifyVp, params, client)
 	if err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
+
+// VerifyVPReportOnChain 链上验证VP，返回每个检查项的结果，VP中每个VC的验证报告在Credentials中
+// @params vp: VP的JSON字符串
+// @params client：长安链客户端
+func VerifyVPReportOnChain(vp string, client invoke.ChainClient) (*model.VerifyReport, error) {
+	return VerifyVPReportOnChainCtx(context.Background(), vp, client)
+}
+
+// VerifyVPReportOnChainCtx 同VerifyVPReportOnChain，可以通过ctx设置超时时间或取消调用
+func VerifyVPReportOnChainCtx(ctx context.Context, vp string, client invoke.ChainClient) (*model.VerifyReport,
+	error) {
+	params := make([]*common.KeyValuePair, 0)
+
+	params = append(params, &common.KeyValuePair{
+		Key:   model.Params_VpJson,
+		Value: []byte(vp),
+	})
+
+	resp, err := invoke.QueryContractCtx(ctx, invoke.ContractNameOf(client), model.Method_VerifyVpReport, params,
+		client)
+	if err != nil {
+		return nil, err
+	}
+
+	var report model.VerifyReport
+	err = json.Unmarshal(resp, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
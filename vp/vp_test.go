@@ -70,7 +70,7 @@ func TestVerifyVPOnChain(t *testing.T) {
 	require.Nil(t, err)
 
 	// 签发者DID文档上链
-	err = did.AddDidDocToChain(string(issuerDocJson), c)
+	_, err = did.AddDidDocToChain(string(issuerDocJson), c)
 	require.Nil(t, err)
 
 	var issuerDoc model.DidDocument
@@ -78,7 +78,7 @@ func TestVerifyVPOnChain(t *testing.T) {
 	err = json.Unmarshal(issuerDocJson, &issuerDoc)
 	require.Nil(t, err)
 
-	err = did.AddTrustIssuerListToChain([]string{issuerDoc.Id}, c)
+	_, err = did.AddTrustIssuerListToChain([]string{issuerDoc.Id}, c)
 	require.Nil(t, err)
 
 	// 被签发者密钥生成
@@ -93,7 +93,7 @@ func TestVerifyVPOnChain(t *testing.T) {
 	require.Nil(t, err)
 
 	// 被签发者DID文档上链
-	err = did.AddDidDocToChain(string(holderDocBytes), c)
+	_, err = did.AddDidDocToChain(string(holderDocBytes), c)
 	require.Nil(t, err)
 
 	fieldsMap := make(map[string]string)
@@ -108,7 +108,7 @@ func TestVerifyVPOnChain(t *testing.T) {
 	require.Nil(t, err)
 
 	// 将VC模板添加到链上
-	err = vc.AddVcTemplateToChain("template001", "模板1", "1.0.0", jsonSchema, c)
+	_, err = vc.AddVcTemplateToChain("template001", "模板1", "1.0.0", jsonSchema, c)
 	require.Nil(t, err)
 
 	sub := make(map[string]interface{})
@@ -120,7 +120,7 @@ func TestVerifyVPOnChain(t *testing.T) {
 	e := time.Now().Add(time.Hour * 24 * 365).Unix()
 
 	// 颁发VC
-	vcBytes, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, sub, c, "vc_001", e, "template001")
+	vcBytes, _, err := vc.IssueVC(issuerKey.SkPEM, issuerKey.PkPEM, 0, sub, c, "vc_001", e, "template001")
 	require.Nil(t, err)
 
 	// 被签发者生成VP